@@ -4,21 +4,31 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"mono_pardo/internal/api"
 	"mono_pardo/internal/api/controller"
+	accountDomain "mono_pardo/internal/domain/account"
+	adminDomain "mono_pardo/internal/domain/admin"
 	setsDomain "mono_pardo/internal/domain/sets"
 	usersDomain "mono_pardo/internal/domain/users"
 	wordsDomain "mono_pardo/internal/domain/words"
+	mailerInfra "mono_pardo/internal/infrastructure/mailer"
+	oidcInfra "mono_pardo/internal/infrastructure/oidc"
 	setsInfra "mono_pardo/internal/infrastructure/sets"
 	usersInfra "mono_pardo/internal/infrastructure/users"
 	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/internal/utils"
 	"mono_pardo/pkg/config"
 
 	"github.com/go-playground/validator"
 	"github.com/rs/cors"
+	"golang.org/x/crypto/bcrypt"
 )
 
+const oidcRequestTimeout = 10 * time.Second
+
 func main() {
 	loadConfig, err := config.LoadConfig(".")
 	if err != nil {
@@ -29,31 +39,165 @@ func main() {
 
 	//Database
 	db := config.ConnectionDB(&loadConfig)
+	mongoDb := config.ConnectionMongo(&loadConfig)
 
 	if err = db.Table("words").AutoMigrate(&wordsDomain.Word{}); err != nil {
 		log.Fatalf("Database table error: %v\n", err)
 	}
 
+	if err = wordsInfra.CreateSearchIndexes(db); err != nil {
+		log.Fatalf("Database index error: %v\n", err)
+	}
+
+	if err = db.Table("training_attempts").AutoMigrate(&wordsDomain.TrainingAttempt{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
 	if err = db.Table("users").AutoMigrate(&usersDomain.User{}); err != nil {
 		log.Fatalf("Database table error: %v\n", err)
 	}
 
+	if err = db.Table("refresh_tokens").AutoMigrate(&usersDomain.RefreshToken{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("revoked_tokens").AutoMigrate(&usersDomain.RevokedToken{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("password_reset_tokens").AutoMigrate(&usersDomain.PasswordResetToken{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("counters").AutoMigrate(&usersDomain.Counter{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("two_factors").AutoMigrate(&usersDomain.TwoFactor{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("recovery_codes").AutoMigrate(&usersDomain.RecoveryCode{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("identities").AutoMigrate(&usersDomain.Identity{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("access_tokens").AutoMigrate(&usersDomain.AccessToken{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	if err = db.Table("security_events").AutoMigrate(&usersDomain.SecurityEvent{}); err != nil {
+		log.Fatalf("Database table error: %v\n", err)
+	}
+
+	var tokenKeys *utils.KeySet
+	if loadConfig.TokenKeysDir != "" {
+		if tokenKeys, err = utils.LoadKeySet(loadConfig.TokenKeysDir, loadConfig.TokenSigningKeyId); err != nil {
+			log.Fatalf("Token keys error: %v\n", err)
+		}
+	} else {
+		log.Println("TOKEN_KEYS_DIR is not set, access tokens are signed with TOKEN_SECRET")
+		tokenKeys = utils.NewHMACKeySet(loadConfig.TokenSecret)
+	}
+
+	// Unset parameters are the defaults, bcrypt hashes of old accounts are upgraded on login
+	argon2Params := utils.DefaultArgon2idParams
+	if loadConfig.PasswordArgon2Memory > 0 {
+		argon2Params.Memory = loadConfig.PasswordArgon2Memory
+	}
+	if loadConfig.PasswordArgon2Iterations > 0 {
+		argon2Params.Iterations = loadConfig.PasswordArgon2Iterations
+	}
+	if loadConfig.PasswordArgon2Parallelism > 0 {
+		argon2Params.Parallelism = loadConfig.PasswordArgon2Parallelism
+	}
+	passwordHasher := utils.NewPasswordHasher(utils.Argon2idHasher{Params: argon2Params}, utils.BcryptHasher{Cost: bcrypt.DefaultCost})
+
 	//Init Repositories
 	userRepository := usersInfra.NewPostgresRepositoryImpl(db)
+	refreshTokenRepository := usersInfra.NewPostgresRefreshTokenRepositoryImpl(db)
+	passwordResetRepository := usersInfra.NewPostgresPasswordResetRepositoryImpl(db)
+	twoFactorRepository := usersInfra.NewPostgresTwoFactorRepositoryImpl(db)
+	identityRepository := usersInfra.NewPostgresIdentityRepositoryImpl(db)
+	accessTokenRepository := usersInfra.NewPostgresAccessTokenRepositoryImpl(db)
+	securityEventRepository := usersInfra.NewPostgresSecurityEventRepositoryImpl(db)
+
+	oidcClient := &http.Client{Timeout: oidcRequestTimeout}
+	oidcProviders := map[string]usersDomain.OIDCProvider{}
+	for name, providerConfig := range loadConfig.OIDCProviders {
+		oidcProviders[name] = oidcInfra.NewProviderImpl(providerConfig, oidcClient)
+	}
+
+	var mailer usersDomain.Mailer
+	switch {
+	case loadConfig.SMTPHost != "":
+		mailer = mailerInfra.NewSMTPMailerImpl(&loadConfig)
+	case loadConfig.MailDir != "" || loadConfig.MailLog:
+		log.Println("SMTP_HOST is not set, emails are not sent")
+		mailer = mailerInfra.NewFileMailerImpl(loadConfig.MailDir, loadConfig.MailFrom)
+	default:
+		log.Fatalf("Mailer error: set SMTP_HOST, or MAIL_DIR or MAIL_LOG for development\n")
+	}
+
+	if err = usersDomain.BootstrapAdmins(userRepository, strings.Split(loadConfig.AdminEmails, ",")); err != nil {
+		log.Fatalf("Admin bootstrap error: %v\n", err)
+	}
+
+	var counterStore usersDomain.CounterStore
+	if loadConfig.CounterStore == "memory" {
+		counterStore = usersInfra.NewMemoryCounterStore()
+	} else {
+		counterStore = usersInfra.NewPostgresCounterStoreImpl(db)
+	}
+
+	revocationStore := usersInfra.NewCachedRevocationStore(usersInfra.NewPostgresRevocationStoreImpl(db), loadConfig.RevocationCacheTTL)
 	wordRepository := wordsInfra.NewPostgresRepositoryImpl(db)
-	setsRepository := setsInfra.NewMongoRepositoryImpl()
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb)
 
 	//Init Services
-	authenticationService := usersDomain.NewServiceImpl(loadConfig, validate, userRepository)
+	authenticationService := usersDomain.NewServiceImpl(usersDomain.Deps{
+		Config:                  loadConfig,
+		Keys:                    tokenKeys,
+		PasswordHasher:          passwordHasher,
+		Validate:                validate,
+		Repository:              userRepository,
+		RefreshTokenRepository:  refreshTokenRepository,
+		RevocationStore:         revocationStore,
+		PasswordResetRepository: passwordResetRepository,
+		CounterStore:            counterStore,
+		TwoFactorRepository:     twoFactorRepository,
+		IdentityRepository:      identityRepository,
+		AccessTokenRepository:   accessTokenRepository,
+		SecurityEventRepository: securityEventRepository,
+		OIDCProviders:           oidcProviders,
+		Mailer:                  mailer,
+	})
 	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
-	setsService := setsDomain.NewServiceImpl(validate, setsRepository)
+	importService := wordsDomain.NewImportServiceImpl(vocabService, wordsInfra.NewFileParserImpl())
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordRepository)
+	accountService := accountDomain.NewServiceImpl(userRepository, wordRepository, setsRepository)
+	adminService := adminDomain.NewServiceImpl(userRepository, wordRepository, securityEventRepository, authenticationService)
+
+	stopPurger := accountDomain.StartPurger(accountService, loadConfig.AccountDeletionGracePeriod, loadConfig.AccountPurgeInterval)
+	defer stopPurger()
 
 	//Init controllers
 	authenticationController := controller.NewAuthenticationController(authenticationService)
 	vocabController := controller.NewVocabController(vocabService)
+	importController := controller.NewImportController(importService)
 	setsController := controller.NewSetsController(setsService)
+	exportController := controller.NewExportController(vocabService, setsService)
+	accountController := controller.NewAccountController(accountService)
+	profileController := controller.NewProfileController(authenticationService)
+	adminController := controller.NewAdminController(adminService)
 
-	router := api.NewRouter(authenticationController, vocabController, setsController)
+	router, err := api.NewRouter(&loadConfig, counterStore, authenticationController, vocabController, importController, setsController, exportController, accountController, profileController, adminController)
+	if err != nil {
+		log.Fatalf("Router error: %v\n", err)
+	}
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{loadConfig.ALLOWED_ORIGINS},
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"mono_pardo/internal/api/errors"
+	domain "mono_pardo/internal/domain/account"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxAccountArchiveSize = 50 << 20 // 50 MB
+
+type AccountController struct {
+	accountService domain.Service
+}
+
+func NewAccountController(service domain.Service) *AccountController {
+	return &AccountController{accountService: service}
+}
+
+func (controller *AccountController) Export(ctx *gin.Context) {
+	archive, err := controller.accountService.ExportAccount(ctx.GetInt("userId"))
+	if err != nil {
+		SendError(ctx, http.StatusInternalServerError, errors.InternalError, "Cannot export account")
+		return
+	}
+
+	fileName := fmt.Sprintf("pardo-export-%s.json", archive.ExportedAt.Format(time.DateOnly))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.JSON(http.StatusOK, archive)
+}
+
+func (controller *AccountController) Import(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxAccountArchiveSize)
+
+	var archive domain.Archive
+	if !BindJSON(ctx, &archive) {
+		return
+	}
+
+	report, err := controller.accountService.ImportAccount(ctx.GetInt("userId"), archive)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"mono_pardo/internal/api/errors"
+	domain "mono_pardo/internal/domain/admin"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	adminService domain.Service
+}
+
+func NewAdminController(service domain.Service) *AdminController {
+	return &AdminController{adminService: service}
+}
+
+func (controller *AdminController) ListUsers(ctx *gin.Context) {
+	var usersRequest request.AdminUsersRequest
+	if !BindQuery(ctx, &usersRequest) {
+		return
+	}
+
+	res, err := controller.adminService.ListUsers(usersRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *AdminController) GetUser(ctx *gin.Context) {
+	userId, ok := parseUserId(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := controller.adminService.GetUser(userId)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *AdminController) SetRole(ctx *gin.Context) {
+	userId, ok := parseUserId(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.SetRoleRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.AdminId = ctx.GetInt("userId")
+	req.UserId = userId
+	req.Client = utils.GetClientInfo(ctx)
+
+	if err := controller.adminService.SetRole(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AdminController) SuspendUser(ctx *gin.Context) {
+	req, ok := adminUserRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := controller.adminService.SuspendUser(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AdminController) UnsuspendUser(ctx *gin.Context) {
+	req, ok := adminUserRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := controller.adminService.UnsuspendUser(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AdminController) DeleteUser(ctx *gin.Context) {
+	req, ok := adminUserRequest(ctx)
+	if !ok {
+		return
+	}
+
+	if err := controller.adminService.DeleteUser(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AdminController) ListSecurityEvents(ctx *gin.Context) {
+	var eventsRequest request.AdminSecurityEventsRequest
+	if !BindQuery(ctx, &eventsRequest) {
+		return
+	}
+
+	res, err := controller.adminService.ListSecurityEvents(eventsRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func adminUserRequest(ctx *gin.Context) (request.AdminUserRequest, bool) {
+	userId, ok := parseUserId(ctx)
+	if !ok {
+		return request.AdminUserRequest{}, false
+	}
+
+	return request.AdminUserRequest{AdminId: ctx.GetInt("userId"), UserId: userId, Client: utils.GetClientInfo(ctx)}, true
+}
+
+func parseUserId(ctx *gin.Context) (int, bool) {
+	userId, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot parse id from url")
+		return 0, false
+	}
+
+	return userId, true
+}
@@ -1,16 +1,27 @@
 package controller
 
 import (
+	stdErrors "errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"mono_pardo/internal/api/errors"
+	domainErrors "mono_pardo/internal/domain/errors"
 	domain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
 	"mono_pardo/pkg/data/request"
-	"mono_pardo/pkg/data/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxDeviceLength = 255
+	jwksMaxAge      = 5 * time.Minute
+)
+
 type AuthenticationController struct {
 	AuthenticationService domain.Service
 }
@@ -25,30 +36,259 @@ func (controller *AuthenticationController) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := controller.AuthenticationService.Login(req)
-	if err != nil {
+	req.Client = utils.GetClientInfo(ctx)
+
+	if req.Device == "" {
+		req.Device = truncate(ctx.Request.UserAgent(), maxDeviceLength)
+	}
+
+	resp, err := controller.AuthenticationService.Login(req)
+	if sendLocked(ctx, err) {
+		return
+	}
+	switch {
+	case err == nil:
+	// Invalid request is answered the same way as wrong password, so it doesn't tell which emails exist
+	case stdErrors.Is(err, domain.ErrInvalidCredentials), stdErrors.Is(domainErrors.FromValidator(err), domainErrors.ErrValidation):
 		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid username or password")
 		return
+	case stdErrors.Is(err, domainErrors.ErrForbidden):
+		SendServiceError(ctx, err)
+		return
+	default:
+		SendError(ctx, http.StatusInternalServerError, errors.InternalError, "Cannot log in, try again later")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (controller *AuthenticationController) VerifyMFA(ctx *gin.Context) {
+	req := request.MFALoginRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.Client = utils.GetClientInfo(ctx)
+
+	resp, err := controller.AuthenticationService.VerifyMFA(req)
+	if sendLocked(ctx, err) {
+		return
+	}
+	switch {
+	case err == nil:
+	case stdErrors.Is(err, domain.ErrInvalidMFACode), stdErrors.Is(err, domain.ErrInvalidMFAChallenge),
+		stdErrors.Is(domainErrors.FromValidator(err), domainErrors.ErrValidation):
+		SendError(ctx, http.StatusUnauthorized, errors.UnauthorizedError, domainErrors.FromValidator(err).Error())
+		return
+	default:
+		SendError(ctx, http.StatusInternalServerError, errors.InternalError, "Cannot log in, try again later")
+		return
 	}
 
-	resp := response.LoginResponse{
-		TokenType: "Bearer",
-		Token:     token,
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (controller *AuthenticationController) StartOIDCLogin(ctx *gin.Context) {
+	req := request.OIDCStartRequest{Provider: ctx.Param("provider")}
+
+	resp, err := controller.AuthenticationService.StartOIDCLogin(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
+func (controller *AuthenticationController) FinishOIDCLogin(ctx *gin.Context) {
+	req := request.OIDCCallbackRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.Client = utils.GetClientInfo(ctx)
+
+	req.Provider = ctx.Param("provider")
+	if req.Device == "" {
+		req.Device = truncate(ctx.Request.UserAgent(), maxDeviceLength)
+	}
+
+	resp, err := controller.AuthenticationService.FinishOIDCLogin(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// sendLocked responds with 423 if there were too many failed attempts
+func sendLocked(ctx *gin.Context, err error) bool {
+	var lockedErr *domain.LockedError
+	if !stdErrors.As(err, &lockedErr) {
+		return false
+	}
+
+	ctx.Header("Retry-After", strconv.Itoa(int(lockedErr.RetryAfter.Seconds())+1))
+	SendError(ctx, http.StatusLocked, errors.AccountLockedError, lockedErr.Error())
+	return true
+}
+
+func (controller *AuthenticationController) Refresh(ctx *gin.Context) {
+	req := request.RefreshTokenRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.Client = utils.GetClientInfo(ctx)
+
+	resp, err := controller.AuthenticationService.Refresh(req)
+	switch {
+	case err == nil:
+	case stdErrors.Is(err, domain.ErrInvalidRefreshToken), stdErrors.Is(err, domain.ErrRefreshTokenReused),
+		stdErrors.Is(domainErrors.FromValidator(err), domainErrors.ErrValidation):
+		SendError(ctx, http.StatusUnauthorized, errors.UnauthorizedError, domainErrors.FromValidator(err).Error())
+		return
+	default:
+		SendError(ctx, http.StatusInternalServerError, errors.InternalError, "Cannot refresh the session, try again later")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (controller *AuthenticationController) Logout(ctx *gin.Context) {
+	token, err := utils.GetToken(ctx)
+	if err != nil {
+		SendError(ctx, http.StatusUnauthorized, errors.UnauthorizedError, "Login required")
+		return
+	}
+
+	req := request.LogoutRequest{Token: token, Client: utils.GetClientInfo(ctx)}
+	if err = controller.AuthenticationService.Logout(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AuthenticationController) LogoutEverywhere(ctx *gin.Context) {
+	req := request.LogoutEverywhereRequest{UserId: ctx.GetInt("userId"), Client: utils.GetClientInfo(ctx)}
+	if err := controller.AuthenticationService.LogoutEverywhere(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AuthenticationController) VerifyEmail(ctx *gin.Context) {
+	req := request.VerifyEmailRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	if err := controller.AuthenticationService.VerifyEmail(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AuthenticationController) ResendVerification(ctx *gin.Context) {
+	if err := controller.AuthenticationService.ResendVerification(ctx.GetInt("userId")); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// ForgotPassword responds the same way whether the email is registered or not
+func (controller *AuthenticationController) ForgotPassword(ctx *gin.Context) {
+	req := request.ForgotPasswordRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	if err := controller.AuthenticationService.ForgotPassword(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (controller *AuthenticationController) ResetPassword(ctx *gin.Context) {
+	req := request.ResetPasswordRequest{}
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.Client = utils.GetClientInfo(ctx)
+
+	if err := controller.AuthenticationService.ResetPassword(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *AuthenticationController) GetSessions(ctx *gin.Context) {
+	res, err := controller.AuthenticationService.GetSessions(ctx.GetInt("userId"))
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *AuthenticationController) RevokeSession(ctx *gin.Context) {
+	req := request.RevokeSessionRequest{UserId: ctx.GetInt("userId"), SessionId: ctx.Param("sessionId"), Client: utils.GetClientInfo(ctx)}
+
+	if err := controller.AuthenticationService.RevokeSession(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
 func (controller *AuthenticationController) Register(ctx *gin.Context) {
 	req := request.CreateUserRequest{}
 	if !BindJSON(ctx, &req) {
 		return
 	}
 
+	req.Client = utils.GetClientInfo(ctx)
+
 	if err := controller.AuthenticationService.Register(req); err != nil {
-		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Please use another email address")
+		SendServiceError(ctx, err)
 		return
 	}
 
 	ctx.Status(http.StatusCreated)
 }
+
+// JWKS publishes the keys for verifying access tokens, verifiers may cache them for a while,
+// so a new signing key is published before it's used
+func (controller *AuthenticationController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksMaxAge.Seconds())))
+	ctx.JSON(http.StatusOK, controller.AuthenticationService.GetJWKS())
+}
+
+// truncate cuts the value to at most length bytes, on a rune boundary so it stays valid UTF-8
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	for length > 0 && !utf8.RuneStart(value[length]) {
+		length--
+	}
+	return value[:length]
+}
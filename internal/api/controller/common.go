@@ -1,17 +1,49 @@
 package controller
 
 import (
+	stdErrors "errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"mono_pardo/internal/api/errors"
+	domainErrors "mono_pardo/internal/domain/errors"
 )
 
 func SendError(c *gin.Context, status int, errType errors.ErrorType, message string) {
 	c.JSON(status, errors.NewAPIError(errType, message))
 }
 
+// SendServiceError maps errors of the domain to status codes. Errors without a kind are failures of
+// the server, their details go only to the log
+func SendServiceError(c *gin.Context, err error) {
+	if sendLocked(c, err) {
+		return
+	}
+
+	var domainErr *domainErrors.Error
+	if !stdErrors.As(domainErrors.FromValidator(err), &domainErr) {
+		log.Printf("%s %s: %v\n", c.Request.Method, c.Request.URL.Path, err)
+		SendError(c, http.StatusInternalServerError, errors.InternalError, "Something went wrong, try again later")
+		return
+	}
+
+	status, errType := http.StatusBadRequest, errors.ValidationError
+	switch domainErr.Kind {
+	case domainErrors.ErrNotFound:
+		status, errType = http.StatusNotFound, errors.NotFoundError
+	case domainErrors.ErrForbidden:
+		status, errType = http.StatusForbidden, errors.ForbiddenError
+	case domainErrors.ErrConflict:
+		status, errType = http.StatusConflict, errors.ConflictError
+	case domainErrors.ErrValidation:
+		status, errType = http.StatusUnprocessableEntity, errors.ValidationError
+	}
+
+	c.JSON(status, &errors.APIError{Type: errType, Code: domainErr.Code, Message: domainErr.Error(), Fields: domainErr.Fields})
+}
+
 func BindJSON(c *gin.Context, obj interface{}) bool {
 	if err := c.ShouldBindJSON(obj); err != nil {
 		SendError(c, http.StatusBadRequest, errors.ValidationError, "Invalid request format")
@@ -19,3 +51,11 @@ func BindJSON(c *gin.Context, obj interface{}) bool {
 	}
 	return true
 }
+
+func BindQuery(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		SendError(c, http.StatusBadRequest, errors.ValidationError, "Invalid query parameters")
+		return false
+	}
+	return true
+}
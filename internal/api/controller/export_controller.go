@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"mono_pardo/internal/api/errors"
+	setsDomain "mono_pardo/internal/domain/sets"
+	wordsDomain "mono_pardo/internal/domain/words"
+	"mono_pardo/internal/infrastructure/anki"
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ankiContentType = "application/octet-stream"
+	vocabDeckName   = "Pardo Vocabulary"
+)
+
+type ExportController struct {
+	vocabService wordsDomain.Service
+	setsService  setsDomain.Service
+}
+
+func NewExportController(vocabService wordsDomain.Service, setsService setsDomain.Service) *ExportController {
+	return &ExportController{vocabService: vocabService, setsService: setsService}
+}
+
+func (controller *ExportController) ExportVocabAnki(ctx *gin.Context) {
+	req := request.ExportWordsRequest{UserId: ctx.GetInt("userId")}
+
+	words, err := controller.vocabService.ExportWords(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	controller.sendAnki(ctx, vocabDeckName, "vocabulary.apkg", anki.NotesFromVocab(words))
+}
+
+func (controller *ExportController) ExportSetAnki(ctx *gin.Context) {
+	userId := ctx.GetInt("userId")
+
+	set, err := controller.setsService.GetSet(request.GetSetRequest{UserId: userId, SetId: ctx.Param("setId")})
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	wordIds := set.Words
+	if wordIds == nil {
+		wordIds = []int{}
+	}
+
+	words, err := controller.vocabService.ExportWords(request.ExportWordsRequest{UserId: userId, WordIds: wordIds})
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	controller.sendAnki(ctx, set.Name, fmt.Sprintf("set-%s.apkg", set.Id), anki.NotesFromVocab(words))
+}
+
+func (controller *ExportController) sendAnki(ctx *gin.Context, deckName, fileName string, notes []anki.Note) {
+	archive, err := anki.Export(deckName, notes)
+	if err != nil {
+		SendError(ctx, http.StatusInternalServerError, errors.InternalError, "Cannot export words")
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Data(http.StatusOK, ankiContentType, archive)
+}
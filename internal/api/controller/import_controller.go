@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"mono_pardo/internal/api/errors"
+	domain "mono_pardo/internal/domain/words"
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxImportFileSize = 5 << 20   // 5 MB
+	maxAnkiFileSize   = 100 << 20 // 100 MB, decks often contain media
+	maxKindleFileSize = 50 << 20  // 50 MB
+)
+
+type ImportController struct {
+	importService domain.ImportService
+}
+
+func NewImportController(service domain.ImportService) *ImportController {
+	return &ImportController{importService: service}
+}
+
+func (controller *ImportController) ImportCSV(ctx *gin.Context) {
+	var req request.ImportCSVRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid request format")
+		return
+	}
+
+	file, _, ok := openImportFile(ctx, maxImportFileSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	req.UserId = ctx.GetInt("userId")
+	req.File = file
+
+	res, err := controller.importService.ImportCSV(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ImportController) ImportAnki(ctx *gin.Context) {
+	var req request.ImportAnkiRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid request format")
+		return
+	}
+
+	file, size, ok := openImportFile(ctx, maxAnkiFileSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	req.UserId = ctx.GetInt("userId")
+	req.File = file
+	req.Size = size
+
+	res, err := controller.importService.ImportAnki(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ImportController) ImportKindle(ctx *gin.Context) {
+	var req request.ImportKindleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid request format")
+		return
+	}
+
+	file, _, ok := openImportFile(ctx, maxKindleFileSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	req.UserId = ctx.GetInt("userId")
+	req.File = file
+
+	res, err := controller.importService.ImportKindle(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func openImportFile(ctx *gin.Context, maxSize int64) (multipart.File, int64, bool) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "File is required")
+		return nil, 0, false
+	}
+
+	if fileHeader.Size > maxSize {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "File is too large")
+		return nil, 0, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot read file")
+		return nil, 0, false
+	}
+
+	return file, fileHeader.Size, true
+}
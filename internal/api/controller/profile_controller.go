@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"mono_pardo/internal/api/errors"
+	domain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileController struct {
+	profileService domain.Service
+}
+
+func NewProfileController(service domain.Service) *ProfileController {
+	return &ProfileController{profileService: service}
+}
+
+func (controller *ProfileController) GetProfile(ctx *gin.Context) {
+	res, err := controller.profileService.FindUser(ctx.GetInt("userId"))
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ProfileController) UpdateProfile(ctx *gin.Context) {
+	var req request.UpdateProfileRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	res, err := controller.profileService.UpdateProfile(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ProfileController) ChangePassword(ctx *gin.Context) {
+	var req request.ChangePasswordRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+	req.Token, _ = utils.GetToken(ctx)
+	req.Client = utils.GetClientInfo(ctx)
+
+	if err := controller.profileService.ChangePassword(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *ProfileController) ChangeEmail(ctx *gin.Context) {
+	var req request.ChangeEmailRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	if err := controller.profileService.ChangeEmail(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (controller *ProfileController) ConfirmEmailChange(ctx *gin.Context) {
+	var req request.ConfirmEmailChangeRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	if err := controller.profileService.ConfirmEmailChange(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *ProfileController) DeleteAccount(ctx *gin.Context) {
+	var req request.DeleteAccountRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+	req.Client = utils.GetClientInfo(ctx)
+
+	res, err := controller.profileService.DeleteAccount(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func (controller *ProfileController) RestoreAccount(ctx *gin.Context) {
+	var req request.RestoreAccountRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.Client = utils.GetClientInfo(ctx)
+
+	if err := controller.profileService.RestoreAccount(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *ProfileController) EnrollTwoFactor(ctx *gin.Context) {
+	var req request.EnrollTwoFactorRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	res, err := controller.profileService.EnrollTwoFactor(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ProfileController) ConfirmTwoFactor(ctx *gin.Context) {
+	var req request.ConfirmTwoFactorRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	res, err := controller.profileService.ConfirmTwoFactor(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ProfileController) DisableTwoFactor(ctx *gin.Context) {
+	var req request.DisableTwoFactorRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	if err := controller.profileService.DisableTwoFactor(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *ProfileController) GetAccessTokens(ctx *gin.Context) {
+	res, err := controller.profileService.GetAccessTokens(ctx.GetInt("userId"))
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *ProfileController) CreateAccessToken(ctx *gin.Context) {
+	var req request.CreateAccessTokenRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+	req.Client = utils.GetClientInfo(ctx)
+
+	res, err := controller.profileService.CreateAccessToken(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (controller *ProfileController) RevokeAccessToken(ctx *gin.Context) {
+	tokenId, err := strconv.Atoi(ctx.Param("tokenId"))
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot parse id from url")
+		return
+	}
+
+	req := request.RevokeAccessTokenRequest{UserId: ctx.GetInt("userId"), TokenId: tokenId, Client: utils.GetClientInfo(ctx)}
+
+	if err = controller.profileService.RevokeAccessToken(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *ProfileController) GetSecurityEvents(ctx *gin.Context) {
+	var req request.SecurityEventsRequest
+	if !BindQuery(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	res, err := controller.profileService.GetSecurityEvents(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
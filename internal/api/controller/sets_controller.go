@@ -1,9 +1,14 @@
 package controller
 
 import (
-	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
 
+	"mono_pardo/internal/api/errors"
 	domain "mono_pardo/internal/domain/sets"
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
 )
 
 type SetsController struct {
@@ -14,16 +19,110 @@ func NewSetsController(service domain.Service) *SetsController {
 	return &SetsController{setsService: service}
 }
 
-func (controller *SetsController) CreateSet(ctx *gin.Context) {}
+func (controller *SetsController) CreateSet(ctx *gin.Context) {
+	var req request.CreateSetRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+
+	res, err := controller.setsService.CreateSet(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (controller *SetsController) GetSets(ctx *gin.Context) {
+	setsRequest := request.SetsRequest{UserId: ctx.GetInt("userId")}
+
+	res, err := controller.setsService.GetSets(setsRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *SetsController) UpdateSet(ctx *gin.Context) {
+	var req request.UpdateSetRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+	req.SetId = ctx.Param("setId")
+
+	if err := controller.setsService.UpdateSet(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *SetsController) DeleteSet(ctx *gin.Context) {
+	req := request.DeleteSetRequest{UserId: ctx.GetInt("userId"), SetId: ctx.Param("setId")}
+
+	if err := controller.setsService.DeleteSet(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (controller *SetsController) GetSet(ctx *gin.Context) {
+	req := request.GetSetRequest{UserId: ctx.GetInt("userId"), SetId: ctx.Param("setId")}
 
-func (controller *SetsController) GetSets(ctx *gin.Context) {}
+	res, err := controller.setsService.GetSet(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
 
-func (controller *SetsController) UpdateSet(ctx *gin.Context) {}
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *SetsController) AddWord(ctx *gin.Context) {
+	var req request.AddWordToSetRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
 
-func (controller *SetsController) DeleteSet(ctx *gin.Context) {}
+	req.UserId = ctx.GetInt("userId")
+	req.SetId = ctx.Param("setId")
 
-func (controller *SetsController) GetSet(ctx *gin.Context) {}
+	if err := controller.setsService.AddWord(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
 
-func (controller *SetsController) AddWord(ctx *gin.Context) {}
+func (controller *SetsController) RemoveWord(ctx *gin.Context) {
+	wordId := ctx.Param("wordId")
+	id, err := strconv.Atoi(wordId)
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot parse id from url")
+		return
+	}
 
-func (controller *SetsController) RemoveWord(ctx *gin.Context) {}
+	req := request.RemoveWordFromSetRequest{
+		UserId: ctx.GetInt("userId"),
+		SetId:  ctx.Param("setId"),
+		WordId: id,
+	}
+
+	if err = controller.setsService.RemoveWord(req); err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultDueWordsLimit = 100
+	maxDueWordsLimit     = 500
+
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
 type VocabController struct {
 	vocabService domain.Service
 }
@@ -28,7 +36,7 @@ func (controller *VocabController) CreateWord(ctx *gin.Context) {
 	req.UserId = ctx.GetInt("userId")
 
 	if err := controller.vocabService.CreateWord(req); err != nil {
-		SendError(ctx, http.StatusBadRequest, errors.ValidationError, err.Error())
+		SendServiceError(ctx, err)
 		return
 	}
 
@@ -46,7 +54,7 @@ func (controller *VocabController) DeleteWord(ctx *gin.Context) {
 	req := request.DeleteWordRequest{UserId: ctx.GetInt("userId"), WordId: id}
 
 	if err = controller.vocabService.DeleteWord(req); err != nil {
-		SendError(ctx, http.StatusBadRequest, errors.ValidationError, err.Error())
+		SendServiceError(ctx, err)
 		return
 	}
 
@@ -54,11 +62,30 @@ func (controller *VocabController) DeleteWord(ctx *gin.Context) {
 }
 
 func (controller *VocabController) GetWords(ctx *gin.Context) {
-	vocabRequest := request.VocabRequest{UserId: ctx.GetInt("userId")}
+	var vocabRequest request.VocabRequest
+	if !BindQuery(ctx, &vocabRequest) {
+		return
+	}
+
+	vocabRequest.UserId = ctx.GetInt("userId")
+
+	// Clients which don't send limit or cursor get the whole vocab as an array, as before pagination
+	_, hasLimit := ctx.GetQuery("limit")
+	_, hasCursor := ctx.GetQuery("cursor")
+	if !hasLimit && !hasCursor {
+		words, err := controller.vocabService.GetAllWords(vocabRequest)
+		if err != nil {
+			SendServiceError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, words)
+		return
+	}
 
 	res, err := controller.vocabService.GetWords(vocabRequest)
 	if err != nil {
-		SendError(ctx, http.StatusBadRequest, errors.ValidationError, err.Error())
+		SendServiceError(ctx, err)
 		return
 	}
 
@@ -79,9 +106,117 @@ func (controller *VocabController) UpdateWord(ctx *gin.Context) {
 	}
 
 	if err := controller.vocabService.UpdateWord(req); err != nil {
-		SendError(ctx, http.StatusBadRequest, errors.ValidationError, err.Error())
+		SendServiceError(ctx, err)
 		return
 	}
 
 	ctx.Status(http.StatusOK)
 }
+
+func (controller *VocabController) ReviewWord(ctx *gin.Context) {
+	wordId := ctx.Param("wordId")
+	id, err := strconv.Atoi(wordId)
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot parse id from url")
+		return
+	}
+
+	var req request.ReviewWordRequest
+	if !BindJSON(ctx, &req) {
+		return
+	}
+
+	req.UserId = ctx.GetInt("userId")
+	req.WordId = id
+
+	res, err := controller.vocabService.ReviewWord(req)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *VocabController) GetDueWords(ctx *gin.Context) {
+	limit := defaultDueWordsLimit
+	if rawLimit := ctx.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 || parsed > maxDueWordsLimit {
+			SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	dueWordsRequest := request.DueWordsRequest{UserId: ctx.GetInt("userId"), Limit: limit}
+
+	res, err := controller.vocabService.GetDueWords(dueWordsRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *VocabController) GetHistory(ctx *gin.Context) {
+	wordId := ctx.Param("wordId")
+	id, err := strconv.Atoi(wordId)
+	if err != nil {
+		SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Cannot parse id from url")
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if rawLimit := ctx.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 || parsed > maxHistoryLimit {
+			SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	offset := 0
+	if rawOffset := ctx.Query("offset"); rawOffset != "" {
+		parsed, err := strconv.Atoi(rawOffset)
+		if err != nil || parsed < 0 {
+			SendError(ctx, http.StatusBadRequest, errors.ValidationError, "Invalid offset")
+			return
+		}
+		offset = parsed
+	}
+
+	historyRequest := request.HistoryRequest{
+		UserId: ctx.GetInt("userId"),
+		WordId: id,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	res, err := controller.vocabService.GetHistory(historyRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (controller *VocabController) SearchWords(ctx *gin.Context) {
+	var searchRequest request.SearchWordsRequest
+	if !BindQuery(ctx, &searchRequest) {
+		return
+	}
+
+	searchRequest.UserId = ctx.GetInt("userId")
+
+	res, err := controller.vocabService.SearchWords(searchRequest)
+	if err != nil {
+		SendServiceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -3,15 +3,21 @@ package errors
 type ErrorType string
 
 const (
-	ValidationError   ErrorType = "VALIDATION_ERROR"
-	NotFoundError     ErrorType = "NOT_FOUND"
-	UnauthorizedError ErrorType = "UNAUTHORIZED"
-	InternalError     ErrorType = "INTERNAL_ERROR"
+	ValidationError      ErrorType = "VALIDATION_ERROR"
+	NotFoundError        ErrorType = "NOT_FOUND"
+	UnauthorizedError    ErrorType = "UNAUTHORIZED"
+	ForbiddenError       ErrorType = "FORBIDDEN"
+	ConflictError        ErrorType = "CONFLICT"
+	TooManyRequestsError ErrorType = "TOO_MANY_REQUESTS"
+	AccountLockedError   ErrorType = "ACCOUNT_LOCKED"
+	InternalError        ErrorType = "INTERNAL_ERROR"
 )
 
 type APIError struct {
-	Type    ErrorType `json:"type"`
-	Message string    `json:"message"`
+	Type    ErrorType         `json:"type"`
+	Code    string            `json:"code,omitempty"` // stable code of the domain error, e.g. "word_not_found"
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
 }
 
 func NewAPIError(errorType ErrorType, message string) *APIError {
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 
 	"mono_pardo/internal/api/errors"
 	usersDomain "mono_pardo/internal/domain/users"
@@ -20,7 +21,23 @@ func NewAuthMiddleware(authService usersDomain.Service) *AuthMiddleware {
 	}
 }
 
+// Handle accepts only session tokens
 func (m *AuthMiddleware) Handle() gin.HandlerFunc {
+	return m.handle(nil)
+}
+
+// HandleScoped accepts personal access tokens too, if they have readScope for GET requests
+// and writeScope for the others
+func (m *AuthMiddleware) HandleScoped(readScope, writeScope string) gin.HandlerFunc {
+	return m.handle(func(c *gin.Context) string {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return readScope
+		}
+		return writeScope
+	})
+}
+
+func (m *AuthMiddleware) handle(requiredScope func(c *gin.Context) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := utils.GetToken(c)
 		if err != nil {
@@ -29,6 +46,11 @@ func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 			return
 		}
 
+		if usersDomain.IsAccessToken(token) {
+			m.handleAccessToken(c, token, requiredScope)
+			return
+		}
+
 		userId, err := m.authService.GetUserId(token)
 		if err != nil {
 			c.AbortWithStatusJSON(
@@ -41,3 +63,39 @@ func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// handleAccessToken authenticates the token before checking where it's used, so denied requests
+// are recorded in the security log of the token's owner
+func (m *AuthMiddleware) handleAccessToken(c *gin.Context, token string, requiredScope func(c *gin.Context) string) {
+	userId, scopes, err := m.authService.AuthenticateAccessToken(token)
+	if err != nil {
+		m.recordDenied(c, 0, "invalid access token")
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized, errors.NewAPIError(errors.UnauthorizedError, "Invalid token"))
+		return
+	}
+
+	if requiredScope == nil {
+		m.recordDenied(c, userId, "route requires login: "+c.FullPath())
+		c.AbortWithStatusJSON(
+			http.StatusForbidden, errors.NewAPIError(errors.ForbiddenError, "Access token cannot be used here, login required"))
+		return
+	}
+
+	scope := requiredScope(c)
+	if !slices.Contains(scopes, scope) {
+		m.recordDenied(c, userId, "missing scope "+scope+": "+c.FullPath())
+		c.AbortWithStatusJSON(
+			http.StatusForbidden, errors.NewAPIError(errors.ForbiddenError, "Access token has no scope "+scope))
+		return
+	}
+
+	c.Set("userId", userId)
+
+	c.Next()
+}
+
+func (m *AuthMiddleware) recordDenied(c *gin.Context, userId int, details string) {
+	m.authService.RecordSecurityEvent(
+		usersDomain.NewSecurityEvent(usersDomain.EventAccessTokenDenied, userId, utils.GetClientInfo(c), details))
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mono_pardo/internal/api/errors"
+	usersDomain "mono_pardo/internal/domain/users"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware allows at most Limit requests per Window from one client IP to the routes
+// it's used on. Limiters with different names are counted separately.
+type RateLimitMiddleware struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+
+	store usersDomain.CounterStore
+}
+
+func NewRateLimitMiddleware(store usersDomain.CounterStore, name string, limit int, window time.Duration) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		Name:   name,
+		Limit:  limit,
+		Window: window,
+		store:  store,
+	}
+}
+
+func (m *RateLimitMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		count, resetAt, err := m.store.Increment("rate:"+m.Name+":"+c.ClientIP(), m.Window)
+		if err != nil {
+			// Broken store must not make the API unavailable
+			c.Next()
+			return
+		}
+
+		if count > m.Limit {
+			retryAfter := time.Until(resetAt)
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(
+				http.StatusTooManyRequests,
+				errors.NewAPIError(errors.TooManyRequestsError, fmt.Sprintf("Too many requests, try again in %s", retryAfter.Round(time.Second))))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"regexp"
+
+	"mono_pardo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+// Request id of a proxy is kept, so the request can be found in its logs too
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIdMiddleware names every request, the id is returned in the header and written to the security log
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId, _ = utils.GenerateOpaqueToken()
+		}
+
+		c.Set("requestId", requestId)
+		c.Header(RequestIdHeader, requestId)
+
+		c.Next()
+	}
+}
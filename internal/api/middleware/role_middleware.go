@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"mono_pardo/internal/api/errors"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleMiddleware lets through only users with one of the roles, must go after AuthMiddleware
+type RoleMiddleware struct {
+	authService usersDomain.Service
+}
+
+func NewRoleMiddleware(authService usersDomain.Service) *RoleMiddleware {
+	return &RoleMiddleware{
+		authService: authService,
+	}
+}
+
+func (m *RoleMiddleware) Require(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := utils.GetToken(c)
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized, errors.NewAPIError(errors.UnauthorizedError, "Login required"))
+			return
+		}
+
+		role, err := m.authService.GetRole(token)
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized, errors.NewAPIError(errors.UnauthorizedError, "Invalid token"))
+			return
+		}
+
+		if !slices.Contains(roles, role) {
+			c.AbortWithStatusJSON(
+				http.StatusForbidden, errors.NewAPIError(errors.ForbiddenError, "Not enough rights"))
+			return
+		}
+
+		c.Set("role", role)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"mono_pardo/internal/api/errors"
+	usersDomain "mono_pardo/internal/domain/users"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifiedMiddleware lets through only users who confirmed their email, must go after AuthMiddleware
+type VerifiedMiddleware struct {
+	authService usersDomain.Service
+}
+
+func NewVerifiedMiddleware(authService usersDomain.Service) *VerifiedMiddleware {
+	return &VerifiedMiddleware{
+		authService: authService,
+	}
+}
+
+func (m *VerifiedMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := m.authService.FindUser(c.GetInt("userId"))
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusUnauthorized, errors.NewAPIError(errors.UnauthorizedError, "Login required"))
+			return
+		}
+
+		if !user.IsVerified {
+			c.AbortWithStatusJSON(
+				http.StatusForbidden, errors.NewAPIError(errors.ForbiddenError, "Email is not verified"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,43 +1,148 @@
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"mono_pardo/internal/api/controller"
 	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/pkg/config"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultAuthRateLimit    = 30 // per minute
+	passwordResetRateLimit  = 5
+	passwordResetRateWindow = 15 * time.Minute
+)
+
 func NewRouter(
+	config *config.Config,
+	counterStore usersDomain.CounterStore,
 	authenticationController *controller.AuthenticationController,
 	vocabController *controller.VocabController,
-	setsController *controller.SetsController) *gin.Engine {
-	router := gin.Default()
+	importController *controller.ImportController,
+	setsController *controller.SetsController,
+	exportController *controller.ExportController,
+	accountController *controller.AccountController,
+	profileController *controller.ProfileController,
+	adminController *controller.AdminController) (*gin.Engine, error) {
+	router, err := NewEngine(config)
+	if err != nil {
+		return nil, err
+	}
 
+	router.Use(middleware.RequestIdMiddleware())
 	router.Use(middleware.LoggerMiddleware())
 
 	authMiddleware := middleware.NewAuthMiddleware(authenticationController.AuthenticationService)
+	roleMiddleware := middleware.NewRoleMiddleware(authenticationController.AuthenticationService)
+
+	// Vocab and sets accept personal access tokens with their scopes,
+	// and they are available only after email verification, if it's required
+	vocabHandlers := []gin.HandlerFunc{authMiddleware.HandleScoped(usersDomain.ScopeVocabRead, usersDomain.ScopeVocabWrite)}
+	setsHandlers := []gin.HandlerFunc{authMiddleware.HandleScoped(usersDomain.ScopeSetsManage, usersDomain.ScopeSetsManage)}
+	if config.RequireVerifiedEmail {
+		verifiedMiddleware := middleware.NewVerifiedMiddleware(authenticationController.AuthenticationService)
+		vocabHandlers = append(vocabHandlers, verifiedMiddleware.Handle())
+		setsHandlers = append(setsHandlers, verifiedMiddleware.Handle())
+	}
+
+	// Guessing passwords and tokens is slowed down per client IP, in addition to the login lockout per account
+	authLimit := config.AuthRateLimit
+	if authLimit <= 0 {
+		authLimit = defaultAuthRateLimit
+	}
+	authRateLimit := middleware.NewRateLimitMiddleware(counterStore, "authentication", authLimit, time.Minute)
+
+	// Password reset sends emails, so it's limited per client IP in addition to the limit per user
+	passwordRateLimit := middleware.NewRateLimitMiddleware(counterStore, "password_reset", passwordResetRateLimit, passwordResetRateWindow)
 
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(404, gin.H{"code": "PAGE_NOT_FOUND", "message": "Page not found"})
 	})
 
+	router.GET("/.well-known/jwks.json", authenticationController.JWKS)
+
 	r := router.Group("/api/v1")
-	authenticationRouter := r.Group("/authentication")
+	authenticationRouter := r.Group("/authentication", authRateLimit.Handle())
 	authenticationRouter.POST("/login", authenticationController.Login)
+	authenticationRouter.POST("/mfa", authenticationController.VerifyMFA)
+	authenticationRouter.POST("/oidc/:provider/start", authenticationController.StartOIDCLogin)
+	authenticationRouter.POST("/oidc/:provider/callback", authenticationController.FinishOIDCLogin)
 	authenticationRouter.POST("/register", authenticationController.Register)
+	authenticationRouter.POST("/refresh", authenticationController.Refresh)
+	authenticationRouter.POST("/verify", authenticationController.VerifyEmail)
+	authenticationRouter.POST("/verify/resend", authMiddleware.Handle(), authenticationController.ResendVerification)
+	authenticationRouter.POST("/email/confirm", profileController.ConfirmEmailChange)
+	authenticationRouter.POST("/restore", profileController.RestoreAccount)
+	authenticationRouter.POST("/password/forgot", passwordRateLimit.Handle(), authenticationController.ForgotPassword)
+	authenticationRouter.POST("/password/reset", passwordRateLimit.Handle(), authenticationController.ResetPassword)
+	authenticationRouter.POST("/logout", authMiddleware.Handle(), authenticationController.Logout)
+	authenticationRouter.POST("/logout/all", authMiddleware.Handle(), authenticationController.LogoutEverywhere)
+	authenticationRouter.GET("/sessions", authMiddleware.Handle(), authenticationController.GetSessions)
+	authenticationRouter.DELETE("/sessions/:sessionId", authMiddleware.Handle(), authenticationController.RevokeSession)
 
-	vocabRouter := r.Group("/vocab", authMiddleware.Handle())
+	vocabRouter := r.Group("/vocab", vocabHandlers...)
 	vocabRouter.GET("", vocabController.GetWords)
 	vocabRouter.POST("", vocabController.CreateWord)
 	vocabRouter.PATCH("", vocabController.UpdateWord)
 	vocabRouter.DELETE("/:wordId", vocabController.DeleteWord)
+	vocabRouter.GET("/due", vocabController.GetDueWords)
+	vocabRouter.GET("/search", vocabController.SearchWords)
+	vocabRouter.POST("/import/csv", importController.ImportCSV)
+	vocabRouter.POST("/import/anki", importController.ImportAnki)
+	vocabRouter.POST("/import/kindle", importController.ImportKindle)
+	vocabRouter.GET("/export/anki", exportController.ExportVocabAnki)
+	vocabRouter.POST("/:wordId/review", vocabController.ReviewWord)
+	vocabRouter.GET("/:wordId/history", vocabController.GetHistory)
+
+	meRouter := r.Group("/me", authMiddleware.Handle())
+	meRouter.GET("", profileController.GetProfile)
+	meRouter.PATCH("", profileController.UpdateProfile)
+	meRouter.DELETE("", profileController.DeleteAccount)
+	meRouter.POST("/password", profileController.ChangePassword)
+	meRouter.POST("/email", profileController.ChangeEmail)
+	meRouter.POST("/2fa/enroll", profileController.EnrollTwoFactor)
+	meRouter.POST("/2fa/confirm", profileController.ConfirmTwoFactor)
+	meRouter.POST("/2fa/disable", profileController.DisableTwoFactor)
+	meRouter.GET("/tokens", profileController.GetAccessTokens)
+	meRouter.POST("/tokens", profileController.CreateAccessToken)
+	meRouter.DELETE("/tokens/:tokenId", profileController.RevokeAccessToken)
+	meRouter.GET("/security-events", profileController.GetSecurityEvents)
+	meRouter.GET("/export", accountController.Export)
+	meRouter.POST("/import", accountController.Import)
 
-	setsRouter := r.Group("/sets", authMiddleware.Handle())
+	setsRouter := r.Group("/sets", setsHandlers...)
 	setsRouter.GET("", setsController.GetSets)
 	setsRouter.POST("", setsController.CreateSet)
-	setsRouter.PATCH("", setsController.UpdateSet)
-	setsRouter.DELETE("", setsController.DeleteSet)
-	setsRouter.GET("/:setId", setsController.GetSets)
+	setsRouter.GET("/:setId", setsController.GetSet)
+	setsRouter.PATCH("/:setId", setsController.UpdateSet)
+	setsRouter.DELETE("/:setId", setsController.DeleteSet)
+	setsRouter.POST("/:setId/words", setsController.AddWord)
+	setsRouter.DELETE("/:setId/words/:wordId", setsController.RemoveWord)
+	setsRouter.GET("/:setId/export/anki", exportController.ExportSetAnki)
+
+	adminRouter := r.Group("/admin", authMiddleware.Handle(), roleMiddleware.Require(usersDomain.RoleAdmin))
+	adminRouter.GET("/users", adminController.ListUsers)
+	adminRouter.GET("/users/:userId", adminController.GetUser)
+	adminRouter.PATCH("/users/:userId/role", adminController.SetRole)
+	adminRouter.POST("/users/:userId/suspend", adminController.SuspendUser)
+	adminRouter.POST("/users/:userId/unsuspend", adminController.UnsuspendUser)
+	adminRouter.DELETE("/users/:userId", adminController.DeleteUser)
+	adminRouter.GET("/security-events", adminController.ListSecurityEvents)
 
-	return router
+	return router, nil
+}
+
+// NewEngine trusts X-Forwarded-For only from the configured proxies, otherwise clients could pick
+// their own IP for rate limits and the security log
+func NewEngine(config *config.Config) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	return router, nil
 }
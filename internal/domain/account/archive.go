@@ -0,0 +1,64 @@
+package account
+
+import "time"
+
+/*
+	Portable archive of the whole account. IDs in the archive are IDs of the exporting instance,
+	they are only used to link words with their history and sets, and are remapped on import.
+	Version is increased on every incompatible change of the format.
+	Only username of the profile is restored on import, email stays the one of the account,
+	because it's the login and it's changed only with confirmation.
+*/
+
+const ArchiveVersion = 1
+
+type Archive struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Profile    ArchiveProfile `json:"profile"`
+	Words      []ArchiveWord  `json:"words"`
+	Sets       []ArchiveSet   `json:"sets"`
+}
+
+type ArchiveProfile struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type ArchiveWord struct {
+	Id         int       `json:"id"`
+	Word       string    `json:"word"`
+	Definition string    `json:"definition"`
+	CreatedAt  time.Time `json:"created_at"`
+	Tags       string    `json:"tags"`
+	Notes      string    `json:"notes"`
+	Context    string    `json:"context"`
+	Source     string    `json:"source"`
+
+	IsLearned       bool `json:"is_learned"`
+	Cards           bool `json:"cards"`
+	WordTranslation bool `json:"word_translation"`
+	Constructor     bool `json:"constructor"`
+	WordAudio       bool `json:"word_audio"`
+
+	EaseFactor   float64   `json:"ease_factor"`
+	IntervalDays int       `json:"interval_days"`
+	Repetitions  int       `json:"repetitions"`
+	DueAt        time.Time `json:"due_at"`
+
+	Attempts []ArchiveAttempt `json:"attempts"`
+}
+
+type ArchiveAttempt struct {
+	Exercise       string    `json:"exercise"`
+	IsCorrect      bool      `json:"is_correct"`
+	ResponseTimeMs int       `json:"response_time_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ArchiveSet struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	Words       []int     `json:"words"` // IDs of archived words
+}
@@ -0,0 +1,13 @@
+package account
+
+import (
+	"time"
+
+	"mono_pardo/pkg/data/response"
+)
+
+type Service interface {
+	ExportAccount(userId int) (Archive, error)
+	ImportAccount(userId int, archive Archive) (response.AccountImportReport, error)
+	PurgeDeletedAccounts(deletedBefore time.Time) (int, error) // returns number of purged accounts
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"log"
+	"time"
+
+	"mono_pardo/internal/domain/users"
+)
+
+const defaultPurgeInterval = time.Hour
+
+// PurgeDeletedAccounts permanently removes accounts which were deleted before the time
+// with all their data. User record goes last, so that a failed purge is retried next time.
+func (s *serviceImpl) PurgeDeletedAccounts(deletedBefore time.Time) (int, error) {
+	deleted, err := s.UsersRepository.FindDeletedBefore(deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range deleted {
+		if err = s.SetsRepository.DeleteByUserId(user.Id); err != nil {
+			return purged, err
+		}
+		if err = s.WordsRepository.DeleteByUserId(user.Id); err != nil {
+			return purged, err
+		}
+		if err = s.UsersRepository.Purge(user.Id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// StartPurger purges accounts after the grace period every interval until stop is called
+func StartPurger(service Service, gracePeriod, interval time.Duration) (stop func()) {
+	gracePeriod = users.DeletionGracePeriod(gracePeriod)
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			purged, err := service.PurgeDeletedAccounts(time.Now().UTC().Add(-gracePeriod))
+			if err != nil {
+				log.Printf("account purge error: %v\n", err)
+			} else if purged > 0 {
+				log.Printf("purged %d deleted accounts\n", purged)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
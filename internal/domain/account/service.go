@@ -0,0 +1,272 @@
+package account
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/domain/sets"
+	"mono_pardo/internal/domain/users"
+	"mono_pardo/internal/domain/words"
+	"mono_pardo/pkg/data/response"
+)
+
+// same limits as username of registration and profile
+const (
+	minUsernameLength = 2
+	maxUsernameLength = 100
+)
+
+// serviceImpl works with repositories of other domains directly, because archive has to keep
+// training state and history which are not exposed by their services
+type serviceImpl struct {
+	UsersRepository users.Repository
+	WordsRepository words.Repository
+	SetsRepository  sets.Repository
+}
+
+func NewServiceImpl(
+	usersRepository users.Repository,
+	wordsRepository words.Repository,
+	setsRepository sets.Repository) Service {
+	return &serviceImpl{
+		UsersRepository: usersRepository,
+		WordsRepository: wordsRepository,
+		SetsRepository:  setsRepository,
+	}
+}
+
+func (s *serviceImpl) ExportAccount(userId int) (Archive, error) {
+	user, err := s.UsersRepository.FindById(userId)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	userWords, err := s.WordsRepository.FindByUserId(userId)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	attempts, err := s.WordsRepository.FindAttemptsByUserId(userId)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	userSets, err := s.SetsRepository.FindByUserId(userId)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	history := make(map[int][]ArchiveAttempt)
+	for _, attempt := range attempts {
+		history[attempt.WordId] = append(history[attempt.WordId], ArchiveAttempt{
+			Exercise:       attempt.Exercise,
+			IsCorrect:      attempt.IsCorrect,
+			ResponseTimeMs: attempt.ResponseTimeMs,
+			CreatedAt:      attempt.CreatedAt,
+		})
+	}
+
+	archive := Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Profile:    ArchiveProfile{Username: user.Username, Email: user.Email},
+		Words:      make([]ArchiveWord, 0, len(userWords)),
+		Sets:       make([]ArchiveSet, 0, len(userSets)),
+	}
+
+	for _, word := range userWords {
+		archiveWord := toArchiveWord(word)
+		if archiveWord.Attempts = history[word.Id]; archiveWord.Attempts == nil {
+			archiveWord.Attempts = []ArchiveAttempt{}
+		}
+		archive.Words = append(archive.Words, archiveWord)
+	}
+
+	for _, set := range userSets {
+		setWords := set.Words
+		if setWords == nil {
+			setWords = []int{}
+		}
+
+		archive.Sets = append(archive.Sets, ArchiveSet{
+			Name:        set.Name,
+			Description: set.Description,
+			CreatedAt:   set.CreatedAt,
+			Words:       setWords,
+		})
+	}
+
+	return archive, nil
+}
+
+// ImportAccount restores archive into the account. Username is taken from the archive, words which
+// user already has are kept as is and only linked to imported sets, sets with the same name as
+// existing ones are skipped.
+func (s *serviceImpl) ImportAccount(userId int, archive Archive) (response.AccountImportReport, error) {
+	if archive.Version != ArchiveVersion {
+		return response.AccountImportReport{}, domainErrors.InvalidField("invalid_archive", "version", "unsupported archive version: %d", archive.Version)
+	}
+
+	report := response.AccountImportReport{Errors: []string{}}
+
+	if err := s.restoreUsername(userId, archive.Profile, &report); err != nil {
+		return response.AccountImportReport{}, err
+	}
+
+	// archived word ID -> ID of the word in this account
+	wordIds := make(map[int]int)
+
+	for _, archiveWord := range archive.Words {
+		word, err := words.NewWord(archiveWord.Word, archiveWord.Definition, userId)
+		if err != nil {
+			report.WordsFailed++
+			report.Errors = append(report.Errors, fmt.Sprintf("word %q: %v", archiveWord.Word, err))
+			continue
+		}
+
+		existing, err := s.WordsRepository.FindByWord(userId, word.Word)
+		if err != nil {
+			return response.AccountImportReport{}, err
+		}
+
+		if existing.Id != 0 {
+			wordIds[archiveWord.Id] = existing.Id
+			report.WordsSkipped++
+			continue
+		}
+
+		restoreWord(word, archiveWord)
+
+		id, err := s.WordsRepository.Save(*word)
+		if err != nil {
+			report.WordsFailed++
+			report.Errors = append(report.Errors, fmt.Sprintf("word %q: %v", archiveWord.Word, err))
+			continue
+		}
+		wordIds[archiveWord.Id] = id
+		report.WordsCreated++
+
+		for _, archiveAttempt := range archiveWord.Attempts {
+			attempt, err := words.NewTrainingAttempt(userId, id, archiveAttempt.Exercise, archiveAttempt.IsCorrect, archiveAttempt.ResponseTimeMs)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("history of word %q: %v", archiveWord.Word, err))
+				continue
+			}
+			if !archiveAttempt.CreatedAt.IsZero() {
+				attempt.CreatedAt = archiveAttempt.CreatedAt
+			}
+
+			if err = s.WordsRepository.SaveAttempt(*attempt); err != nil {
+				return response.AccountImportReport{}, err
+			}
+			report.AttemptsCreated++
+		}
+	}
+
+	existingSets, err := s.SetsRepository.FindByUserId(userId)
+	if err != nil {
+		return response.AccountImportReport{}, err
+	}
+
+	setNames := make(map[string]bool)
+	for _, set := range existingSets {
+		setNames[set.Name] = true
+	}
+
+	for _, archiveSet := range archive.Sets {
+		set, err := sets.NewWordSet(archiveSet.Name, archiveSet.Description, userId)
+		if err != nil {
+			report.SetsFailed++
+			report.Errors = append(report.Errors, fmt.Sprintf("set %q: %v", archiveSet.Name, err))
+			continue
+		}
+
+		if setNames[set.Name] {
+			report.SetsSkipped++
+			continue
+		}
+		setNames[set.Name] = true
+
+		if !archiveSet.CreatedAt.IsZero() {
+			set.CreatedAt = archiveSet.CreatedAt
+		}
+
+		// Words which failed to import are left out of the set
+		for _, archiveId := range archiveSet.Words {
+			if id, ok := wordIds[archiveId]; ok {
+				set.Words = append(set.Words, id)
+			}
+		}
+
+		if _, err = s.SetsRepository.Save(*set); err != nil {
+			return response.AccountImportReport{}, err
+		}
+		report.SetsCreated++
+	}
+
+	return report, nil
+}
+
+func (s *serviceImpl) restoreUsername(userId int, profile ArchiveProfile, report *response.AccountImportReport) error {
+	username := strings.TrimSpace(profile.Username)
+	if username == "" {
+		return nil
+	}
+	if length := len([]rune(username)); length < minUsernameLength || length > maxUsernameLength {
+		report.Errors = append(report.Errors, fmt.Sprintf("username %q: must be from %d to %d characters", username, minUsernameLength, maxUsernameLength))
+		return nil
+	}
+
+	if err := s.UsersRepository.UpdateUsername(userId, username); err != nil {
+		return err
+	}
+	report.UsernameRestored = true
+	return nil
+}
+
+func toArchiveWord(word words.Word) ArchiveWord {
+	return ArchiveWord{
+		Id:              word.Id,
+		Word:            word.Word,
+		Definition:      word.Definition,
+		CreatedAt:       word.CreatedAt,
+		Tags:            word.Tags,
+		Notes:           word.Notes,
+		Context:         word.Context,
+		Source:          word.Source,
+		IsLearned:       word.IsLearned,
+		Cards:           word.Cards,
+		WordTranslation: word.WordTranslation,
+		Constructor:     word.Constructor,
+		WordAudio:       word.WordAudio,
+		EaseFactor:      word.EaseFactor,
+		IntervalDays:    word.IntervalDays,
+		Repetitions:     word.Repetitions,
+		DueAt:           word.DueAt,
+	}
+}
+
+// restoreWord copies training state from the archive, zero values are left to database defaults
+func restoreWord(word *words.Word, archiveWord ArchiveWord) {
+	word.CreatedAt = archiveWord.CreatedAt
+	word.Tags = words.NormalizeTags(archiveWord.Tags)
+	word.Notes = archiveWord.Notes
+	word.Context = archiveWord.Context
+	word.Source = archiveWord.Source
+
+	word.IsLearned = archiveWord.IsLearned
+	word.Cards = archiveWord.Cards
+	word.WordTranslation = archiveWord.WordTranslation
+	word.Constructor = archiveWord.Constructor
+	word.WordAudio = archiveWord.WordAudio
+
+	word.EaseFactor = archiveWord.EaseFactor
+	if word.EaseFactor < words.MinEaseFactor {
+		word.EaseFactor = words.DefaultEaseFactor
+	}
+	word.IntervalDays = max(archiveWord.IntervalDays, 0)
+	word.Repetitions = max(archiveWord.Repetitions, 0)
+	word.DueAt = archiveWord.DueAt
+}
@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+type Service interface {
+	ListUsers(usersRequest request.AdminUsersRequest) (response.AdminUsersResponse, error)
+	GetUser(userId int) (response.AdminUserResponse, error)
+	SetRole(roleRequest request.SetRoleRequest) error
+	SuspendUser(suspendRequest request.AdminUserRequest) error
+	UnsuspendUser(unsuspendRequest request.AdminUserRequest) error
+	DeleteUser(deleteRequest request.AdminUserRequest) error
+	ListSecurityEvents(eventsRequest request.AdminSecurityEventsRequest) (response.SecurityEventsResponse, error)
+}
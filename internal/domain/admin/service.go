@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/domain/users"
+	"mono_pardo/internal/domain/words"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 200
+)
+
+var errSelfAction = domainErrors.Forbidden("self_action", "admin cannot do it with their own account")
+
+// serviceImpl uses users service to close sessions and to write the security log, everything else
+// is done with repositories, because users service works only with the account of the logged-in user
+type serviceImpl struct {
+	UsersRepository         users.Repository
+	WordsRepository         words.Repository
+	SecurityEventRepository users.SecurityEventRepository
+	UsersService            users.Service
+}
+
+func NewServiceImpl(
+	usersRepository users.Repository,
+	wordsRepository words.Repository,
+	securityEventRepository users.SecurityEventRepository,
+	usersService users.Service) Service {
+	return &serviceImpl{
+		UsersRepository:         usersRepository,
+		WordsRepository:         wordsRepository,
+		SecurityEventRepository: securityEventRepository,
+		UsersService:            usersService,
+	}
+}
+
+func (s *serviceImpl) ListUsers(usersRequest request.AdminUsersRequest) (response.AdminUsersResponse, error) {
+	if usersRequest.Role != "" && !users.IsValidRole(usersRequest.Role) {
+		return response.AdminUsersResponse{}, domainErrors.InvalidField("invalid_query", "role", "unknown role: %s", usersRequest.Role)
+	}
+	if usersRequest.Offset < 0 {
+		return response.AdminUsersResponse{}, domainErrors.InvalidField("invalid_query", "offset", "invalid offset")
+	}
+
+	limit := usersRequest.Limit
+	if limit <= 0 {
+		limit = defaultUsersLimit
+	}
+	if limit > maxUsersLimit {
+		return response.AdminUsersResponse{}, domainErrors.InvalidField("invalid_query", "limit", "limit must be at most %d", maxUsersLimit)
+	}
+
+	found, total, err := s.UsersRepository.FindPage(users.UserQuery{
+		Search:    strings.TrimSpace(usersRequest.Search),
+		Role:      usersRequest.Role,
+		Suspended: usersRequest.Suspended,
+		Limit:     limit,
+		Offset:    usersRequest.Offset,
+	})
+	if err != nil {
+		return response.AdminUsersResponse{}, err
+	}
+
+	userIds := make([]int, 0, len(found))
+	for _, user := range found {
+		userIds = append(userIds, user.Id)
+	}
+
+	counts, err := s.WordsRepository.CountByUserIds(userIds)
+	if err != nil {
+		return response.AdminUsersResponse{}, err
+	}
+
+	usersResponse := response.AdminUsersResponse{
+		Users:  make([]response.AdminUserResponse, 0, len(found)),
+		Total:  total,
+		Limit:  limit,
+		Offset: usersRequest.Offset,
+	}
+	for _, user := range found {
+		usersResponse.Users = append(usersResponse.Users, toAdminUserResponse(user, counts[user.Id]))
+	}
+
+	return usersResponse, nil
+}
+
+func (s *serviceImpl) GetUser(userId int) (response.AdminUserResponse, error) {
+	user, err := s.findUser(userId)
+	if err != nil {
+		return response.AdminUserResponse{}, err
+	}
+
+	counts, err := s.WordsRepository.CountByUserIds([]int{user.Id})
+	if err != nil {
+		return response.AdminUserResponse{}, err
+	}
+
+	return toAdminUserResponse(user, counts[user.Id]), nil
+}
+
+func (s *serviceImpl) SetRole(roleRequest request.SetRoleRequest) error {
+	if !users.IsValidRole(roleRequest.Role) {
+		return domainErrors.InvalidField("invalid_role", "role", "unknown role: %s", roleRequest.Role)
+	}
+	// Otherwise the last admin could lock everyone out of the admin API
+	if roleRequest.UserId == roleRequest.AdminId {
+		return errSelfAction
+	}
+
+	user, err := s.findUser(roleRequest.UserId)
+	if err != nil {
+		return err
+	}
+	if user.Role == roleRequest.Role {
+		return nil
+	}
+
+	if err = s.UsersRepository.UpdateRole(user.Id, roleRequest.Role); err != nil {
+		return err
+	}
+
+	s.recordEvent(users.EventRoleChanged, user.Id, roleRequest.AdminId, roleRequest.Client, user.Role+" -> "+roleRequest.Role)
+
+	// Access tokens carry the role, new ones are issued on the next login
+	return s.UsersService.LogoutEverywhere(request.LogoutEverywhereRequest{UserId: user.Id, AdminId: roleRequest.AdminId, Client: roleRequest.Client})
+}
+
+func (s *serviceImpl) SuspendUser(suspendRequest request.AdminUserRequest) error {
+	if suspendRequest.UserId == suspendRequest.AdminId {
+		return errSelfAction
+	}
+
+	user, err := s.findUser(suspendRequest.UserId)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if err = s.UsersRepository.UpdateSuspendedAt(user.Id, &now); err != nil {
+		return err
+	}
+
+	s.recordEvent(users.EventSuspended, user.Id, suspendRequest.AdminId, suspendRequest.Client, "")
+
+	return s.UsersService.LogoutEverywhere(request.LogoutEverywhereRequest{UserId: user.Id, AdminId: suspendRequest.AdminId, Client: suspendRequest.Client})
+}
+
+func (s *serviceImpl) UnsuspendUser(unsuspendRequest request.AdminUserRequest) error {
+	user, err := s.findUser(unsuspendRequest.UserId)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt == nil {
+		return nil
+	}
+
+	if err = s.UsersRepository.UpdateSuspendedAt(user.Id, nil); err != nil {
+		return err
+	}
+
+	s.recordEvent(users.EventUnsuspended, user.Id, unsuspendRequest.AdminId, unsuspendRequest.Client, "")
+	return nil
+}
+
+// DeleteUser deletes the account the same way as the user does, so it's purged after the grace period
+func (s *serviceImpl) DeleteUser(deleteRequest request.AdminUserRequest) error {
+	if deleteRequest.UserId == deleteRequest.AdminId {
+		return errSelfAction
+	}
+
+	user, err := s.findUser(deleteRequest.UserId)
+	if err != nil {
+		return err
+	}
+
+	logoutRequest := request.LogoutEverywhereRequest{UserId: user.Id, AdminId: deleteRequest.AdminId, Client: deleteRequest.Client}
+	if err = s.UsersService.LogoutEverywhere(logoutRequest); err != nil {
+		return err
+	}
+
+	if err = s.UsersRepository.Delete(user.Id); err != nil {
+		return err
+	}
+
+	s.recordEvent(users.EventAccountDeleted, user.Id, deleteRequest.AdminId, deleteRequest.Client, "")
+	return nil
+}
+
+// ListSecurityEvents searches the security log of all users, the newest events first
+func (s *serviceImpl) ListSecurityEvents(eventsRequest request.AdminSecurityEventsRequest) (response.SecurityEventsResponse, error) {
+	limit, err := users.ValidateSecurityEventsPage(eventsRequest.Type, eventsRequest.Limit, eventsRequest.Offset)
+	if err != nil {
+		return response.SecurityEventsResponse{}, err
+	}
+	if eventsRequest.From != nil && eventsRequest.To != nil && eventsRequest.To.Before(*eventsRequest.From) {
+		return response.SecurityEventsResponse{}, domainErrors.InvalidField("invalid_query", "to", "to must be after from")
+	}
+
+	events, total, err := s.SecurityEventRepository.FindPage(users.SecurityEventQuery{
+		UserId: eventsRequest.UserId,
+		Type:   eventsRequest.Type,
+		IP:     strings.TrimSpace(eventsRequest.IP),
+		From:   eventsRequest.From,
+		To:     eventsRequest.To,
+		Limit:  limit,
+		Offset: eventsRequest.Offset,
+	})
+	if err != nil {
+		return response.SecurityEventsResponse{}, err
+	}
+
+	return users.ToSecurityEventsResponse(events, total, limit, eventsRequest.Offset), nil
+}
+
+func (s *serviceImpl) recordEvent(eventType string, userId, adminId int, client request.ClientInfo, details string) {
+	event := users.NewSecurityEvent(eventType, userId, client, details)
+	event.ActorId = adminId
+	s.UsersService.RecordSecurityEvent(event)
+}
+
+func (s *serviceImpl) findUser(userId int) (users.User, error) {
+	return s.UsersRepository.FindById(userId)
+}
+
+func toAdminUserResponse(user users.User, count words.VocabCount) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		UserResponse: users.ToUserResponse(user),
+		SuspendedAt:  user.SuspendedAt,
+		WordsCount:   count.Total,
+		LearnedCount: count.Learned,
+	}
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator"
+)
+
+/*
+	Errors of the domain, which the API maps to status codes (controller.SendServiceError).
+	Each error has a kind, which is checked with errors.Is(err, ErrNotFound), and a stable code
+	for clients, e.g. "word_not_found". Errors without a kind are internal errors.
+*/
+
+var (
+	ErrNotFound   = stdErrors.New("not found")
+	ErrForbidden  = stdErrors.New("forbidden")
+	ErrConflict   = stdErrors.New("conflict")
+	ErrValidation = stdErrors.New("validation failed")
+)
+
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  map[string]string // invalid fields of validation errors, field name -> reason
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Forbidden(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Invalid(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidField is a validation error of one field of the request
+func InvalidField(code, field, format string, args ...interface{}) *Error {
+	message := fmt.Sprintf(format, args...)
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: map[string]string{field: message}}
+}
+
+// FromValidator converts errors of validator.Struct, other errors are returned as they are
+func FromValidator(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !stdErrors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make(map[string]string, len(validationErrors))
+	names := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		name := fieldName(fieldErr.Field())
+		if fieldErr.Param() != "" {
+			fields[name] = fmt.Sprintf("failed on %s=%s", fieldErr.Tag(), fieldErr.Param())
+		} else {
+			fields[name] = "failed on " + fieldErr.Tag()
+		}
+		names = append(names, name)
+	}
+
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    "invalid_request",
+		Message: "invalid fields: " + strings.Join(names, ", "),
+		Fields:  fields,
+	}
+}
+
+// fieldName is the name of the field in JSON, e.g. NewPassword -> new_password, MFAToken -> mfa_token
+func fieldName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previousLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -1,5 +1,27 @@
 package sets
 
-type Service interface{}
+import (
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
 
-type Repository interface{}
+type Service interface {
+	CreateSet(createSetRequest request.CreateSetRequest) (response.SetResponse, error)
+	GetSets(setsRequest request.SetsRequest) ([]response.SetResponse, error)
+	GetSet(getSetRequest request.GetSetRequest) (response.SetResponse, error)
+	UpdateSet(updateSetRequest request.UpdateSetRequest) error
+	DeleteSet(deleteSetRequest request.DeleteSetRequest) error
+	AddWord(addWordRequest request.AddWordToSetRequest) error
+	RemoveWord(removeWordRequest request.RemoveWordFromSetRequest) error
+}
+
+type Repository interface {
+	Save(set WordSet) (string, error)
+	Update(set WordSet) error
+	Delete(setId string) error
+	DeleteByUserId(userId int) error
+	FindByUserId(userId int) ([]WordSet, error)
+	FindById(setId string) (WordSet, error) // ErrSetNotFound if there is no set
+	AddWord(setId string, wordId int) error
+	RemoveWord(setId string, wordId int) error
+}
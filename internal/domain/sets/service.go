@@ -1,15 +1,160 @@
 package sets
 
-import "github.com/go-playground/validator"
+import (
+	"strings"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/domain/words"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+
+	"github.com/go-playground/validator"
+)
 
 type serviceImpl struct {
-	Validate   *validator.Validate
-	Repository Repository
+	Validate        *validator.Validate
+	Repository      Repository
+	WordsRepository words.Repository
 }
 
-func NewServiceImpl(validate *validator.Validate, repository Repository) Service {
+func NewServiceImpl(validate *validator.Validate, repository Repository, wordsRepository words.Repository) Service {
 	return &serviceImpl{
-		Validate:   validate,
-		Repository: repository,
+		Validate:        validate,
+		Repository:      repository,
+		WordsRepository: wordsRepository,
+	}
+}
+
+func (s *serviceImpl) CreateSet(createSetRequest request.CreateSetRequest) (response.SetResponse, error) {
+	newSet, err := NewWordSet(
+		createSetRequest.Name,
+		createSetRequest.Description,
+		createSetRequest.UserId,
+	)
+	if err != nil {
+		return response.SetResponse{}, err
+	}
+
+	setId, err := s.Repository.Save(*newSet)
+	if err != nil {
+		return response.SetResponse{}, err
+	}
+
+	newSet.Id = setId
+
+	return toSetResponse(*newSet), nil
+}
+
+func (s *serviceImpl) GetSets(setsRequest request.SetsRequest) ([]response.SetResponse, error) {
+	setsResponse := []response.SetResponse{}
+
+	sets, err := s.Repository.FindByUserId(setsRequest.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range sets {
+		setsResponse = append(setsResponse, toSetResponse(set))
+	}
+
+	return setsResponse, nil
+}
+
+func (s *serviceImpl) GetSet(getSetRequest request.GetSetRequest) (response.SetResponse, error) {
+	set, err := s.findOwnSet(getSetRequest.UserId, getSetRequest.SetId, "get")
+	if err != nil {
+		return response.SetResponse{}, err
+	}
+
+	return toSetResponse(set), nil
+}
+
+func (s *serviceImpl) UpdateSet(updateSetRequest request.UpdateSetRequest) error {
+	if updateSetRequest.Name == nil && updateSetRequest.Description == nil {
+		return domainErrors.Invalid("no_updates", "no updates provided")
+	}
+
+	set, err := s.findOwnSet(updateSetRequest.UserId, updateSetRequest.SetId, "update")
+	if err != nil {
+		return err
+	}
+
+	if updateSetRequest.Name != nil {
+		name := strings.TrimSpace(*updateSetRequest.Name)
+		if name == "" {
+			return domainErrors.InvalidField("invalid_update", "name", "empty value not allowed for field: name")
+		}
+		set.Name = name
+	}
+
+	if updateSetRequest.Description != nil {
+		set.Description = strings.TrimSpace(*updateSetRequest.Description)
+	}
+
+	return s.Repository.Update(set)
+}
+
+func (s *serviceImpl) DeleteSet(deleteSetRequest request.DeleteSetRequest) error {
+	if _, err := s.findOwnSet(deleteSetRequest.UserId, deleteSetRequest.SetId, "delete"); err != nil {
+		return err
+	}
+
+	return s.Repository.Delete(deleteSetRequest.SetId)
+}
+
+func (s *serviceImpl) AddWord(addWordRequest request.AddWordToSetRequest) error {
+	if addWordRequest.WordId <= 0 {
+		return domainErrors.InvalidField("invalid_word", "word_id", "invalid word ID")
+	}
+
+	if _, err := s.findOwnSet(addWordRequest.UserId, addWordRequest.SetId, "update"); err != nil {
+		return err
+	}
+
+	// Word of another user is reported as missing, so the set can't reveal which ids exist
+	word, err := s.WordsRepository.FindById(addWordRequest.WordId)
+	if err != nil {
+		return err
+	}
+	if word.Id == 0 || word.UserId != addWordRequest.UserId {
+		return words.ErrWordNotFound
+	}
+
+	return s.Repository.AddWord(addWordRequest.SetId, addWordRequest.WordId)
+}
+
+func (s *serviceImpl) RemoveWord(removeWordRequest request.RemoveWordFromSetRequest) error {
+	if _, err := s.findOwnSet(removeWordRequest.UserId, removeWordRequest.SetId, "update"); err != nil {
+		return err
+	}
+
+	return s.Repository.RemoveWord(removeWordRequest.SetId, removeWordRequest.WordId)
+}
+
+// findOwnSet returns the set if it belongs to the user
+func (s *serviceImpl) findOwnSet(userId int, setId, action string) (WordSet, error) {
+	set, err := s.Repository.FindById(setId)
+	if err != nil {
+		return WordSet{}, err
+	}
+	if set.UserId != userId {
+		return WordSet{}, domainErrors.Forbidden("set_not_owned", "you are not allowed to %s the set: %s", action, setId)
+	}
+
+	return set, nil
+}
+
+func toSetResponse(set WordSet) response.SetResponse {
+	wordIds := set.Words
+	if wordIds == nil {
+		wordIds = []int{}
+	}
+
+	return response.SetResponse{
+		Id:          set.Id,
+		Name:        set.Name,
+		Description: set.Description,
+		Words:       wordIds,
+		CreatedAt:   set.CreatedAt,
 	}
 }
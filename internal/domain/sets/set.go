@@ -1,7 +1,36 @@
 package sets
 
-type WordSet struct{}
+import (
+	"strings"
+	"time"
 
-func NewWordSet() (*WordSet, error) {
-	return &WordSet{}, nil
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+var ErrSetNotFound = domainErrors.NotFound("set_not_found", "set is not found")
+
+type WordSet struct {
+	Id          string
+	Name        string
+	Description string
+	UserId      int
+	Words       []int // IDs of the words from user's vocab
+	CreatedAt   time.Time
+}
+
+func NewWordSet(name, description string, userId int) (*WordSet, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, domainErrors.InvalidField("invalid_set", "name", "name is required field")
+	}
+	if userId <= 0 {
+		return nil, domainErrors.InvalidField("invalid_set", "user_id", "invalid user ID")
+	}
+
+	return &WordSet{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		UserId:      userId,
+		Words:       []int{},
+		CreatedAt:   time.Now().UTC(),
+	}, nil
 }
@@ -0,0 +1,206 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Personal access tokens for scripts and integrations. The token is an opaque random string with
+	a prefix, so it can't be confused with a session token and is easy to find in leaked code, only
+	its hash is stored. The token works only with the routes of its scopes and never with /me or
+	/authentication, so it can't be used to change the account or to create more tokens.
+*/
+
+const (
+	AccessTokenPrefix = "pardo_pat_"
+
+	ScopeVocabRead  = "vocab:read"
+	ScopeVocabWrite = "vocab:write"
+	ScopeSetsManage = "sets:manage"
+
+	defaultAccessTokenTTLDays = 90
+	maxAccessTokens           = 50
+	lastUsedPrecision         = time.Minute // last use isn't written on every request
+)
+
+var (
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+	ErrTooManyAccessTokens = domainErrors.Conflict("too_many_access_tokens", "cannot create more than %d access tokens", maxAccessTokens)
+)
+
+var scopes = []string{ScopeVocabRead, ScopeVocabWrite, ScopeSetsManage}
+
+type AccessToken struct {
+	Id         int       `gorm:"type:int;primary_key"`
+	UserId     int       `gorm:"not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	TokenHash  string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Hint       string    `gorm:"type:varchar(8);not null"`   // last characters, to tell tokens apart
+	Scopes     string    `gorm:"type:varchar(255);not null"` // separated by spaces
+	CreatedAt  time.Time `gorm:"default:now()"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (t *AccessToken) IsActive(now time.Time) bool {
+	return t.Id != 0 && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+func (t *AccessToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(scopes, scope)
+}
+
+// IsAccessToken tells personal access tokens from session tokens
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, AccessTokenPrefix)
+}
+
+func (s *serviceImpl) CreateAccessToken(createRequest request.CreateAccessTokenRequest) (response.AccessTokenCreatedResponse, error) {
+	if err := s.Validate.Struct(createRequest); err != nil {
+		return response.AccessTokenCreatedResponse{}, err
+	}
+
+	tokenScopes := []string{}
+	for _, scope := range createRequest.Scopes {
+		if !IsValidScope(scope) {
+			return response.AccessTokenCreatedResponse{}, domainErrors.InvalidField("invalid_scope", "scopes", "unknown scope: %s", scope)
+		}
+		if !slices.Contains(tokenScopes, scope) {
+			tokenScopes = append(tokenScopes, scope)
+		}
+	}
+
+	now := time.Now().UTC()
+
+	active, err := s.AccessTokenRepository.FindActiveByUserId(createRequest.UserId, now)
+	if err != nil {
+		return response.AccessTokenCreatedResponse{}, err
+	}
+	if len(active) >= maxAccessTokens {
+		return response.AccessTokenCreatedResponse{}, ErrTooManyAccessTokens
+	}
+
+	secret, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return response.AccessTokenCreatedResponse{}, err
+	}
+	token := AccessTokenPrefix + secret
+
+	days := createRequest.ExpiresInDays
+	if days == 0 {
+		days = defaultAccessTokenTTLDays
+	}
+
+	record := AccessToken{
+		UserId:    createRequest.UserId,
+		Name:      strings.TrimSpace(createRequest.Name),
+		TokenHash: utils.HashToken(token),
+		Hint:      token[len(token)-4:],
+		Scopes:    strings.Join(tokenScopes, " "),
+		CreatedAt: now,
+		ExpiresAt: now.AddDate(0, 0, days),
+	}
+
+	if err = s.AccessTokenRepository.Save(record); err != nil {
+		return response.AccessTokenCreatedResponse{}, err
+	}
+
+	record, err = s.AccessTokenRepository.FindByHash(record.TokenHash)
+	if err != nil {
+		return response.AccessTokenCreatedResponse{}, err
+	}
+
+	s.recordEvent(EventAccessTokenCreated, record.UserId, createRequest.Client, fmt.Sprintf("access token %d %q, scopes %s", record.Id, record.Name, record.Scopes))
+
+	return response.AccessTokenCreatedResponse{
+		AccessTokenResponse: toAccessTokenResponse(record),
+		Token:               token,
+	}, nil
+}
+
+func (s *serviceImpl) GetAccessTokens(userId int) ([]response.AccessTokenResponse, error) {
+	tokens, err := s.AccessTokenRepository.FindActiveByUserId(userId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]response.AccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, toAccessTokenResponse(token))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) RevokeAccessToken(revokeRequest request.RevokeAccessTokenRequest) error {
+	revoked, err := s.AccessTokenRepository.Revoke(revokeRequest.UserId, revokeRequest.TokenId, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domainErrors.NotFound("access_token_not_found", "access token is not found: %d", revokeRequest.TokenId)
+	}
+
+	s.recordEvent(EventTokenRevoked, revokeRequest.UserId, revokeRequest.Client, fmt.Sprintf("access token %d", revokeRequest.TokenId))
+	return nil
+}
+
+// AuthenticateAccessToken returns the user and scopes of the token
+func (s *serviceImpl) AuthenticateAccessToken(token string) (int, []string, error) {
+	if !IsAccessToken(token) {
+		return 0, nil, ErrInvalidAccessToken
+	}
+
+	record, err := s.AccessTokenRepository.FindByHash(utils.HashToken(token))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	now := time.Now().UTC()
+	if !record.IsActive(now) {
+		return 0, nil, ErrInvalidAccessToken
+	}
+
+	// Tokens of deleted and suspended users stop working, unlike sessions they are not revoked
+	user, err := s.Repository.FindById(record.UserId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return 0, nil, err
+	}
+	if err != nil || user.SuspendedAt != nil {
+		return 0, nil, ErrInvalidAccessToken
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) > lastUsedPrecision {
+		if err = s.AccessTokenRepository.MarkUsed(record.Id, now); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return record.UserId, record.ScopeList(), nil
+}
+
+func toAccessTokenResponse(token AccessToken) response.AccessTokenResponse {
+	return response.AccessTokenResponse{
+		Id:         token.Id,
+		Name:       token.Name,
+		Hint:       token.Hint,
+		Scopes:     token.ScopeList(),
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
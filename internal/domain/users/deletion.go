@@ -0,0 +1,109 @@
+package users
+
+import (
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Account deletion. The account is soft deleted first: it's hidden from all queries and its sessions
+	are closed, but the owner can restore it during the grace period. Then it's purged together with
+	words and sets (see account.StartPurger).
+*/
+
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+var ErrAccountNotRestorable = domainErrors.NotFound("account_not_restorable", "account is not found or cannot be restored anymore")
+
+func (s *serviceImpl) DeleteAccount(deleteRequest request.DeleteAccountRequest) (response.AccountDeletionResponse, error) {
+	if err := s.Validate.Struct(deleteRequest); err != nil {
+		return response.AccountDeletionResponse{}, err
+	}
+
+	user, err := s.findUser(deleteRequest.UserId)
+	if err != nil {
+		return response.AccountDeletionResponse{}, err
+	}
+
+	if err = s.PasswordHasher.Verify(user.Password, strings.TrimSpace(deleteRequest.Password)); err != nil {
+		return response.AccountDeletionResponse{}, ErrWrongPassword
+	}
+
+	now := time.Now().UTC()
+
+	// Sessions are closed before the user is hidden, while they can still be found
+	if err = s.revokeAllSessions(user.Id); err != nil {
+		return response.AccountDeletionResponse{}, err
+	}
+	if err = s.PasswordResetRepository.InvalidateByUserId(user.Id, now); err != nil {
+		return response.AccountDeletionResponse{}, err
+	}
+
+	if err = s.Repository.Delete(user.Id); err != nil {
+		return response.AccountDeletionResponse{}, err
+	}
+
+	s.recordEvent(EventAccountDeleted, user.Id, deleteRequest.Client, "")
+
+	return response.AccountDeletionResponse{
+		DeletedAt: now,
+		PurgeAt:   now.Add(s.deletionGracePeriod()),
+	}, nil
+}
+
+func (s *serviceImpl) RestoreAccount(restoreRequest request.RestoreAccountRequest) error {
+	if err := s.Validate.Struct(restoreRequest); err != nil {
+		return err
+	}
+
+	// Restore checks the password too, so it shares the lockout with login
+	email := strings.TrimSpace(restoreRequest.Email)
+	failuresKey := loginFailuresKey(email)
+	if err := s.checkLockout(failuresKey); err != nil {
+		s.recordLoginFailure(0, email, restoreRequest.Client, "restore locked")
+		return err
+	}
+
+	user, err := s.Repository.FindDeletedByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	// Same error for unknown email and wrong password, as on login
+	if user.Id == 0 || !user.DeletedAt.Valid ||
+		s.PasswordHasher.Verify(user.Password, strings.TrimSpace(restoreRequest.Password)) != nil {
+		s.recordLoginFailure(user.Id, email, restoreRequest.Client, "restore with wrong email or password")
+
+		if lockErr := s.registerFailure(failuresKey); lockErr != nil {
+			return lockErr
+		}
+		return ErrAccountNotRestorable
+	}
+
+	if err = s.CounterStore.Reset(failuresKey); err != nil {
+		return err
+	}
+
+	// Purge may not have run yet, but the grace period is over
+	if time.Now().After(user.DeletedAt.Time.Add(s.deletionGracePeriod())) {
+		return ErrAccountNotRestorable
+	}
+
+	return s.Repository.Restore(user.Id)
+}
+
+func (s *serviceImpl) deletionGracePeriod() time.Duration {
+	return DeletionGracePeriod(s.Config.AccountDeletionGracePeriod)
+}
+
+// DeletionGracePeriod returns configured grace period or the default one
+func DeletionGracePeriod(configured time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	return DefaultDeletionGracePeriod
+}
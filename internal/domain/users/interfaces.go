@@ -1,21 +1,130 @@
 package users
 
 import (
+	"time"
+
 	"mono_pardo/pkg/data/request"
 	"mono_pardo/pkg/data/response"
 )
 
 type Service interface {
-	Login(user request.LoginRequest) (string, error)
+	Login(user request.LoginRequest) (response.LoginResponse, error)
+	Refresh(refreshRequest request.RefreshTokenRequest) (response.LoginResponse, error)
+	GetSessions(userId int) ([]response.SessionResponse, error)
+	RevokeSession(revokeRequest request.RevokeSessionRequest) error
+	Logout(logoutRequest request.LogoutRequest) error
+	LogoutEverywhere(logoutRequest request.LogoutEverywhereRequest) error
+	VerifyEmail(verifyRequest request.VerifyEmailRequest) error
+	ResendVerification(userId int) error
+	ForgotPassword(forgotRequest request.ForgotPasswordRequest) error
+	ResetPassword(resetRequest request.ResetPasswordRequest) error
+	UpdateProfile(updateRequest request.UpdateProfileRequest) (response.UserResponse, error)
+	ChangePassword(changeRequest request.ChangePasswordRequest) error
+	ChangeEmail(changeRequest request.ChangeEmailRequest) error
+	ConfirmEmailChange(confirmRequest request.ConfirmEmailChangeRequest) error
+	DeleteAccount(deleteRequest request.DeleteAccountRequest) (response.AccountDeletionResponse, error)
+	RestoreAccount(restoreRequest request.RestoreAccountRequest) error
+	EnrollTwoFactor(enrollRequest request.EnrollTwoFactorRequest) (response.TwoFactorEnrollmentResponse, error)
+	ConfirmTwoFactor(confirmRequest request.ConfirmTwoFactorRequest) (response.RecoveryCodesResponse, error)
+	DisableTwoFactor(disableRequest request.DisableTwoFactorRequest) error
+	VerifyMFA(mfaRequest request.MFALoginRequest) (response.LoginResponse, error)
+	StartOIDCLogin(startRequest request.OIDCStartRequest) (response.OIDCStartResponse, error)
+	FinishOIDCLogin(callbackRequest request.OIDCCallbackRequest) (response.LoginResponse, error)
+	CreateAccessToken(createRequest request.CreateAccessTokenRequest) (response.AccessTokenCreatedResponse, error)
+	GetAccessTokens(userId int) ([]response.AccessTokenResponse, error)
+	RevokeAccessToken(revokeRequest request.RevokeAccessTokenRequest) error
+	AuthenticateAccessToken(token string) (int, []string, error) // user and scopes of the token
 	Register(user request.CreateUserRequest) error
 	GetUserId(token string) (int, error)
+	GetRole(token string) (string, error)
+	GetJWKS() response.JWKSResponse
+	GetSecurityEvents(eventsRequest request.SecurityEventsRequest) (response.SecurityEventsResponse, error)
+	RecordSecurityEvent(event SecurityEvent) // used by middlewares and admin service
 	FindUser(userId int) (response.UserResponse, error)
 }
 
 type Repository interface {
-	Save(user User) error
-	Delete(usersId int)
-	FindById(usersId int) (User, error)
-	FindAll() []User
-	FindByEmail(email string) (User, error)
+	Save(user User) error // ErrEmailInUse if the email is taken
+	UpdatePassword(userId int, hashedPassword string) error
+	UpdateUsername(userId int, username string) error
+	UpdateEmail(userId int, email string) error // marks the email verified, ErrEmailInUse if the email is taken
+	UpdateRole(userId int, role string) error
+	MarkVerified(userId int) error
+	UpdateSuspendedAt(userId int, suspendedAt *time.Time) error // nil lifts the suspension
+	Delete(usersId int) error                                   // soft delete, the account can be restored until it's purged, ErrUserNotFound if there is no user
+	Restore(userId int) error
+	Purge(userId int) error                             // removes the user with tokens, security events and counters permanently
+	FindDeletedByEmail(email string) (User, error)      // Id is 0 if there is no deleted user
+	FindDeletedBefore(before time.Time) ([]User, error) // users to purge
+	FindById(usersId int) (User, error)                 // ErrUserNotFound if there is no user
+	FindAll() ([]User, error)
+	FindPage(query UserQuery) ([]User, int64, error)
+	FindByEmail(email string) (User, error) // ErrUserNotFound if there is no user
+}
+
+type RefreshTokenRepository interface {
+	Save(token RefreshToken) error
+	FindByHash(tokenHash string) (RefreshToken, error) // Id is 0 if token doesn't exist
+	FindActiveByUserId(userId int, now time.Time) ([]RefreshToken, error)
+	MarkRotated(tokenId int, at time.Time) (bool, error) // false if the token was already rotated
+	RevokeFamily(familyId string) error
+}
+
+type PasswordResetRepository interface {
+	Save(token PasswordResetToken) error
+	FindByHash(tokenHash string) (PasswordResetToken, error) // Id is 0 if token doesn't exist
+	CountCreatedSince(userId int, since time.Time) (int64, error)
+	MarkUsed(tokenId int, at time.Time) (bool, error) // false if the token was already used
+	InvalidateByUserId(userId int, at time.Time) error
+}
+
+type TwoFactorRepository interface {
+	Save(twoFactor TwoFactor) error             // replaces enrolment which isn't confirmed yet
+	FindByUserId(userId int) (TwoFactor, error) // UserId is 0 if the user didn't enrol
+	Enable(userId int, at time.Time, counter int64) error
+	UseCounter(userId int, counter int64) (bool, error) // false if a code of this or a later period was used
+	Delete(userId int) error                            // removes recovery codes too
+	ReplaceRecoveryCodes(userId int, codes []RecoveryCode) error
+	UseRecoveryCode(userId int, codeHash string, at time.Time) (bool, error) // false if the code is unknown or used
+}
+
+type AccessTokenRepository interface {
+	Save(token AccessToken) error
+	FindByHash(tokenHash string) (AccessToken, error) // Id is 0 if token doesn't exist
+	FindActiveByUserId(userId int, now time.Time) ([]AccessToken, error)
+	MarkUsed(tokenId int, at time.Time) error
+	Revoke(userId, tokenId int, at time.Time) (bool, error) // false if the user has no such active token
+}
+
+type IdentityRepository interface {
+	Save(identity Identity) error
+	FindBySubject(provider, subject string) (Identity, error) // Id is 0 if the identity isn't linked
+}
+
+// OIDCProvider is a client of OpenID Connect provider registered for the app
+type OIDCProvider interface {
+	AuthorizationURL(state, nonce, codeChallenge string) (string, error)
+	Exchange(code, codeVerifier string) (OIDCClaims, error) // claims of the verified ID token
+}
+
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// CounterStore counts events in fixed windows, used for login lockout and rate limiting
+type CounterStore interface {
+	Increment(key string, window time.Duration) (int, time.Time, error) // count in the current window and its end
+	Get(key string) (int, time.Time, error)                             // 0 if the window is over
+	Reset(key string) error
+}
+
+type RevocationStore interface {
+	Revoke(id string, expiresAt time.Time) error
+	IsRevoked(ids ...string) (bool, error) // true if any of ids is revoked
+}
+
+// SecurityEventRepository is append-only
+type SecurityEventRepository interface {
+	Save(event SecurityEvent) error
+	FindPage(query SecurityEventQuery) ([]SecurityEvent, int64, error) // the newest first
 }
@@ -0,0 +1,85 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+	Brute-force protection. Failed logins are counted per email in a fixed window, whether the email
+	is registered or not, so that lockout doesn't tell which emails exist. After LoginMaxFailures
+	failures the email is locked until the window ends, successful login resets the counter.
+*/
+
+const (
+	defaultLoginMaxFailures     = 5
+	defaultLoginLockoutDuration = 15 * time.Minute
+	loginFailuresKeyPrefix      = "login_failures:"
+)
+
+// Counter is a record of the Postgres CounterStore
+type Counter struct {
+	Key     string    `gorm:"type:varchar(255);primary_key"`
+	Count   int       `gorm:"not null"`
+	ResetAt time.Time `gorm:"not null;index"` // end of the window, the counter starts from 0 after it
+}
+
+// LockedError is returned on login to the locked account
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed logins, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// checkLockout returns LockedError if the key (email or user for 2FA codes) has too many failures
+func (s *serviceImpl) checkLockout(key string) error {
+	count, resetAt, err := s.CounterStore.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if count >= s.loginMaxFailures() {
+		return &LockedError{RetryAfter: time.Until(resetAt)}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) registerFailure(key string) error {
+	count, resetAt, err := s.CounterStore.Increment(key, s.loginLockoutDuration())
+	if err != nil {
+		return err
+	}
+
+	if count >= s.loginMaxFailures() {
+		return &LockedError{RetryAfter: time.Until(resetAt)}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) loginMaxFailures() int {
+	if s.Config.LoginMaxFailures > 0 {
+		return s.Config.LoginMaxFailures
+	}
+	return defaultLoginMaxFailures
+}
+
+func (s *serviceImpl) loginLockoutDuration() time.Duration {
+	if s.Config.LoginLockoutDuration > 0 {
+		return s.Config.LoginLockoutDuration
+	}
+	return defaultLoginLockoutDuration
+}
+
+func loginFailuresKey(email string) string {
+	return loginFailuresKeyPrefix + strings.ToLower(strings.TrimSpace(email))
+}
+
+// CounterKeys are the counters of the user, they are removed when the account is purged
+func CounterKeys(user User) []string {
+	return []string{loginFailuresKey(user.Email), mfaFailuresKey(user.Id)}
+}
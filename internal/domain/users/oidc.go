@@ -0,0 +1,227 @@
+package users
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Login with OpenID Connect providers, authorization code flow with PKCE. Start returns the URL of
+	the provider and a signed flow token with state, nonce and code verifier, which the client keeps
+	and sends back with the code, so nothing is stored until the login is finished.
+
+	External identity is linked to the user by (provider, subject). On the first login it's linked to
+	the user with the same email, only if both the provider and the user verified it, otherwise
+	anyone could take over an account by registering its email at some provider. New users are
+	created with a random password, which can be set later with password reset.
+*/
+
+const (
+	oidcFlowPurpose = "oidc_flow"
+	oidcFlowTTL     = 10 * time.Minute
+)
+
+var (
+	ErrUnknownProvider          = domainErrors.NotFound("provider_not_found", "unknown identity provider")
+	ErrInvalidOIDCLogin         = domainErrors.Invalid("invalid_oidc_login", "invalid or expired login, please start again")
+	ErrProviderEmailNotVerified = domainErrors.Forbidden("provider_email_not_verified", "email is not verified by the identity provider")
+	ErrLinkUnverifiedEmail      = domainErrors.Conflict("account_exists", "account with this email exists, verify the email or log in with password first")
+	ErrOIDCAccountDeleted       = domainErrors.Invalid("account_deleted", "account with this email is deleted, restore it to log in")
+)
+
+// Identity is an account of the user at the identity provider
+type Identity struct {
+	Id        int       `gorm:"type:int;primary_key"`
+	UserId    int       `gorm:"not null;index"`
+	Provider  string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_identities_subject"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_identities_subject"`
+	Email     string    `gorm:"type:varchar(255)"` // email at the provider when the identity was linked
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+// OIDCClaims are claims of the verified ID token
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nonce         string
+}
+
+func (s *serviceImpl) StartOIDCLogin(startRequest request.OIDCStartRequest) (response.OIDCStartResponse, error) {
+	provider, ok := s.OIDCProviders[startRequest.Provider]
+	if !ok {
+		return response.OIDCStartResponse{}, ErrUnknownProvider
+	}
+
+	var values [3]string
+	for i := range values {
+		value, err := utils.GenerateOpaqueToken()
+		if err != nil {
+			return response.OIDCStartResponse{}, err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	authorizationURL, err := provider.AuthorizationURL(state, nonce, utils.PKCEChallenge(verifier))
+	if err != nil {
+		return response.OIDCStartResponse{}, err
+	}
+
+	flowToken, err := utils.GenerateSignedToken(oidcFlowPurpose, oidcFlowTTL, map[string]interface{}{
+		"provider": startRequest.Provider,
+		"state":    state,
+		"nonce":    nonce,
+		"verifier": verifier,
+	}, s.Config.TokenSecret)
+	if err != nil {
+		return response.OIDCStartResponse{}, err
+	}
+
+	return response.OIDCStartResponse{AuthorizationURL: authorizationURL, FlowToken: flowToken}, nil
+}
+
+func (s *serviceImpl) FinishOIDCLogin(callbackRequest request.OIDCCallbackRequest) (response.LoginResponse, error) {
+	if err := s.Validate.Struct(callbackRequest); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	provider, ok := s.OIDCProviders[callbackRequest.Provider]
+	if !ok {
+		return response.LoginResponse{}, ErrUnknownProvider
+	}
+
+	flow, err := utils.ValidateSignedToken(oidcFlowPurpose, callbackRequest.FlowToken, s.Config.TokenSecret)
+	if err != nil {
+		return response.LoginResponse{}, ErrInvalidOIDCLogin
+	}
+
+	state, _ := flow["state"].(string)
+	nonce, _ := flow["nonce"].(string)
+	verifier, _ := flow["verifier"].(string)
+
+	// State binds the callback to the browser which started the login
+	if flow["provider"] != callbackRequest.Provider || !equalSecrets(state, callbackRequest.State) {
+		return response.LoginResponse{}, ErrInvalidOIDCLogin
+	}
+
+	claims, err := provider.Exchange(callbackRequest.Code, verifier)
+	if err != nil {
+		// Details of the provider stay in the log, the client can only start again
+		log.Printf("login with %s failed: %v\n", callbackRequest.Provider, err)
+		return response.LoginResponse{}, ErrInvalidOIDCLogin
+	}
+
+	// ID token was issued for this login, not replayed from another one
+	if !equalSecrets(nonce, claims.Nonce) {
+		return response.LoginResponse{}, ErrInvalidOIDCLogin
+	}
+
+	user, err := s.linkIdentity(callbackRequest.Provider, claims)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	if user.SuspendedAt != nil {
+		return response.LoginResponse{}, ErrAccountSuspended
+	}
+
+	twoFactor, err := s.TwoFactorRepository.FindByUserId(user.Id)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if twoFactor.IsEnabled() {
+		return s.mfaChallenge(user, strings.TrimSpace(callbackRequest.Device))
+	}
+
+	return s.startSession(user, strings.TrimSpace(callbackRequest.Device), "oidc "+callbackRequest.Provider, callbackRequest.Client)
+}
+
+// linkIdentity returns the user of the identity, linking or creating the user on the first login
+func (s *serviceImpl) linkIdentity(provider string, claims OIDCClaims) (User, error) {
+	identity, err := s.IdentityRepository.FindBySubject(provider, claims.Subject)
+	if err != nil {
+		return User{}, err
+	}
+
+	if identity.Id != 0 {
+		user, err := s.Repository.FindById(identity.UserId)
+		if errors.Is(err, ErrUserNotFound) {
+			return User{}, ErrAccountNotRestorable
+		}
+		return user, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return User{}, ErrProviderEmailNotVerified
+	}
+
+	user, err := s.Repository.FindByEmail(claims.Email)
+	if err == nil && !user.IsVerified {
+		return User{}, ErrLinkUnverifiedEmail
+	}
+	if errors.Is(err, ErrUserNotFound) {
+		if user, err = s.createOIDCUser(claims); err != nil {
+			return User{}, err
+		}
+	} else if err != nil {
+		return User{}, err
+	}
+
+	err = s.IdentityRepository.Save(Identity{
+		UserId:   user.Id,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *serviceImpl) createOIDCUser(claims OIDCClaims) (User, error) {
+	deleted, err := s.Repository.FindDeletedByEmail(claims.Email)
+	if err != nil {
+		return User{}, err
+	}
+	if deleted.Id != 0 {
+		return User{}, ErrOIDCAccountDeleted
+	}
+
+	password, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return User{}, err
+	}
+
+	username := strings.TrimSpace(claims.Name)
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	newUser, err := NewUser(username, claims.Email, password, s.PasswordHasher)
+	if err != nil {
+		return User{}, err
+	}
+	newUser.IsVerified = true
+
+	if err = s.Repository.Save(*newUser); err != nil {
+		return User{}, err
+	}
+
+	return s.Repository.FindByEmail(newUser.Email)
+}
+
+func equalSecrets(expected, actual string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
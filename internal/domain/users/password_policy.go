@@ -0,0 +1,56 @@
+package users
+
+import (
+	_ "embed"
+	"strings"
+	"unicode/utf8"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+/*
+	Password policy for new passwords (registration, reset and change). Length is what makes a
+	password hard to guess, so there are no rules about character classes, but passwords which
+	are tried first by attackers are rejected. Existing passwords keep working until they are changed.
+*/
+
+const defaultPasswordMinLength = 8
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+var commonPasswords = loadCommonPasswords(commonPasswordsList)
+
+func loadCommonPasswords(list string) map[string]struct{} {
+	passwords := map[string]struct{}{}
+	for _, password := range strings.Split(list, "\n") {
+		if password = strings.TrimSpace(password); password != "" {
+			passwords[strings.ToLower(password)] = struct{}{}
+		}
+	}
+	return passwords
+}
+
+// checkPasswordPolicy returns a validation error of the field, which has the password
+func (s *serviceImpl) checkPasswordPolicy(field, password, email string) error {
+	minLength := s.Config.PasswordMinLength
+	if minLength <= 0 {
+		minLength = defaultPasswordMinLength
+	}
+
+	if utf8.RuneCountInString(password) < minLength {
+		return domainErrors.InvalidField("weak_password", field, "password must be at least %d characters long", minLength)
+	}
+
+	if strings.EqualFold(password, strings.TrimSpace(email)) {
+		return domainErrors.InvalidField("weak_password", field, "password must not be the same as the email")
+	}
+
+	if !s.Config.PasswordAllowCommon {
+		if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+			return domainErrors.InvalidField("weak_password", field, "password is too common, please choose another one")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+)
+
+/*
+	Password reset. The emailed token is an opaque random string, only its hash is stored,
+	it can be used once and only before it expires. Forgot password never tells whether the email
+	is registered: the response is the same and the email is sent in the background.
+*/
+
+const (
+	defaultPasswordResetTTL    = time.Hour
+	maxPasswordResetsPerWindow = 3 // emails per user, further requests are ignored
+	passwordResetWindow        = time.Hour
+	passwordResetEmailSubject  = "Reset your password"
+	passwordResetEmailPath     = "/reset-password"
+)
+
+var ErrInvalidResetToken = domainErrors.Invalid("invalid_reset_token", "invalid or expired reset token")
+
+type PasswordResetToken struct {
+	Id        int       `gorm:"type:int;primary_key"`
+	UserId    int       `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"default:now()"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+// NewPasswordResetToken returns the token to send to the user and its record to store
+func NewPasswordResetToken(userId int, ttl time.Duration) (string, *PasswordResetToken, error) {
+	if userId <= 0 {
+		return "", nil, errors.New("invalid user ID")
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return token, &PasswordResetToken{
+		UserId:    userId,
+		TokenHash: utils.HashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+func (t *PasswordResetToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+func (s *serviceImpl) ForgotPassword(forgotRequest request.ForgotPasswordRequest) error {
+	if err := s.Validate.Struct(forgotRequest); err != nil {
+		return err
+	}
+
+	// Unknown email is not an error for the caller, same as on login
+	user, err := s.Repository.FindByEmail(strings.TrimSpace(forgotRequest.Email))
+	if err != nil || user.Id == 0 {
+		return nil
+	}
+
+	// Sending takes time only for existing users, so it must not delay the response
+	go func() {
+		if err := s.sendPasswordReset(user); err != nil {
+			log.Printf("password reset of user %d: %v\n", user.Id, err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *serviceImpl) ResetPassword(resetRequest request.ResetPasswordRequest) error {
+	if err := s.Validate.Struct(resetRequest); err != nil {
+		return err
+	}
+
+	token, err := s.PasswordResetRepository.FindByHash(utils.HashToken(resetRequest.Token))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if token.Id == 0 || !token.IsActive(now) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.Repository.FindById(token.UserId)
+	if errors.Is(err, ErrUserNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	// The link stays valid if the password is rejected
+	if err = s.checkPasswordPolicy("password", strings.TrimSpace(resetRequest.Password), user.Email); err != nil {
+		return err
+	}
+
+	// Two concurrent requests with the same token: only one of them can use it
+	used, err := s.PasswordResetRepository.MarkUsed(token.Id, now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := s.PasswordHasher.Hash(strings.TrimSpace(resetRequest.Password))
+	if err != nil {
+		return err
+	}
+
+	if err = s.Repository.UpdatePassword(user.Id, hashedPassword); err != nil {
+		return err
+	}
+
+	// Other links from the same period must not work anymore
+	if err = s.PasswordResetRepository.InvalidateByUserId(user.Id, now); err != nil {
+		return err
+	}
+
+	s.recordEvent(EventPasswordChanged, user.Id, resetRequest.Client, "reset")
+
+	// Whoever knew the old password may still be logged in
+	return s.revokeAllSessions(user.Id)
+}
+
+func (s *serviceImpl) sendPasswordReset(user User) error {
+	now := time.Now().UTC()
+
+	sent, err := s.PasswordResetRepository.CountCreatedSince(user.Id, now.Add(-passwordResetWindow))
+	if err != nil {
+		return err
+	}
+	if sent >= maxPasswordResetsPerWindow {
+		return fmt.Errorf("too many reset requests, email is not sent")
+	}
+
+	ttl := s.Config.PasswordResetExpiresIn
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
+
+	token, record, err := NewPasswordResetToken(user.Id, ttl)
+	if err != nil {
+		return err
+	}
+
+	if err = s.PasswordResetRepository.Save(*record); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", s.Config.AppURL, passwordResetEmailPath, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\n\nsomeone asked to reset the password of your account. "+
+			"If it was you, open the link below to choose a new one:\n\n%s\n\n"+
+			"The link is valid for %s. If you didn't ask for it, just ignore this email.\n",
+		user.Username, link, ttl)
+
+	if err = s.Mailer.Send(user.Email, passwordResetEmailSubject, body); err != nil {
+		return errors.New("cannot send password reset email")
+	}
+
+	return nil
+}
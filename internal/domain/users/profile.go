@@ -0,0 +1,224 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Profile of the logged-in user. A new email is applied only after it's confirmed: the link is sent
+	to the new address and contains both emails, so it stops working once the email is changed.
+*/
+
+const (
+	changeEmailPurpose  = "change_email"
+	changeEmailSubject  = "Confirm your new email"
+	changeEmailPath     = "/confirm-email"
+	emailChangedSubject = "Your email was changed"
+)
+
+var (
+	ErrWrongPassword     = domainErrors.Invalid("wrong_password", "current password is wrong")
+	ErrEmailInUse        = domainErrors.Conflict("email_in_use", "email is already in use")
+	ErrInvalidChangeLink = domainErrors.Invalid("invalid_change_link", "invalid or expired confirmation link")
+)
+
+func (s *serviceImpl) UpdateProfile(updateRequest request.UpdateProfileRequest) (response.UserResponse, error) {
+	if updateRequest.Username == nil {
+		return response.UserResponse{}, domainErrors.Invalid("no_updates", "no updates provided")
+	}
+
+	if err := s.Validate.Struct(updateRequest); err != nil {
+		return response.UserResponse{}, err
+	}
+
+	user, err := s.findUser(updateRequest.UserId)
+	if err != nil {
+		return response.UserResponse{}, err
+	}
+
+	username := strings.TrimSpace(*updateRequest.Username)
+	if username == "" {
+		return response.UserResponse{}, domainErrors.InvalidField("invalid_update", "username", "empty value not allowed for field: username")
+	}
+	if err = s.Repository.UpdateUsername(user.Id, username); err != nil {
+		return response.UserResponse{}, err
+	}
+
+	return s.FindUser(user.Id)
+}
+
+func (s *serviceImpl) ChangePassword(changeRequest request.ChangePasswordRequest) error {
+	if err := s.Validate.Struct(changeRequest); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(changeRequest.UserId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.PasswordHasher.Verify(user.Password, strings.TrimSpace(changeRequest.CurrentPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if err = s.checkPasswordPolicy("new_password", strings.TrimSpace(changeRequest.NewPassword), user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.PasswordHasher.Hash(strings.TrimSpace(changeRequest.NewPassword))
+	if err != nil {
+		return err
+	}
+
+	if err = s.Repository.UpdatePassword(user.Id, hashedPassword); err != nil {
+		return err
+	}
+
+	s.recordEvent(EventPasswordChanged, user.Id, changeRequest.Client, "")
+
+	now := time.Now().UTC()
+	if err = s.PasswordResetRepository.InvalidateByUserId(user.Id, now); err != nil {
+		return err
+	}
+
+	// Other sessions are closed, the one which changed the password stays open
+	var currentSession string
+	if claims, err := utils.ValidateToken(changeRequest.Token, s.Keys); err == nil {
+		currentSession = claims.SessionId
+	}
+
+	tokens, err := s.RefreshTokenRepository.FindActiveByUserId(user.Id, now)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if token.FamilyId == currentSession {
+			continue
+		}
+		if err = s.revokeSession(token.FamilyId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) ChangeEmail(changeRequest request.ChangeEmailRequest) error {
+	if err := s.Validate.Struct(changeRequest); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(changeRequest.UserId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.PasswordHasher.Verify(user.Password, strings.TrimSpace(changeRequest.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	email := strings.TrimSpace(changeRequest.Email)
+	if !isValidEmail(email) {
+		return domainErrors.InvalidField("invalid_email", "email", "invalid email format")
+	}
+	if email == user.Email {
+		return domainErrors.InvalidField("invalid_email", "email", "new email is the same as the current one")
+	}
+	if err = s.checkEmailFree(email, user.Id); err != nil {
+		return err
+	}
+
+	ttl := s.Config.EmailVerificationExpiresIn
+	if ttl <= 0 {
+		ttl = defaultVerificationTTL
+	}
+
+	token, err := utils.GenerateSignedToken(changeEmailPurpose, ttl, map[string]interface{}{
+		"sub":   user.Id,
+		"email": email,
+		"old":   user.Email,
+	}, s.Config.TokenSecret)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", s.Config.AppURL, changeEmailPath, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\n\nplease confirm your new email by opening the link below:\n\n%s\n\nThe link is valid for %s.\n",
+		user.Username, link, ttl)
+
+	if err = s.Mailer.Send(email, changeEmailSubject, body); err != nil {
+		log.Printf("cannot send email change confirmation to user %d: %v\n", user.Id, err)
+		return errors.New("cannot send confirmation email")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) ConfirmEmailChange(confirmRequest request.ConfirmEmailChangeRequest) error {
+	if err := s.Validate.Struct(confirmRequest); err != nil {
+		return err
+	}
+
+	claims, err := utils.ValidateSignedToken(changeEmailPurpose, confirmRequest.Token, s.Config.TokenSecret)
+	if err != nil {
+		return ErrInvalidChangeLink
+	}
+
+	userId, err := strconv.Atoi(fmt.Sprint(claims["sub"]))
+	if err != nil {
+		return ErrInvalidChangeLink
+	}
+
+	user, err := s.Repository.FindById(userId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+
+	// Email was changed since the link was sent, or the link was already used
+	if err != nil || user.Email != claims["old"] {
+		return ErrInvalidChangeLink
+	}
+
+	email := fmt.Sprint(claims["email"])
+	if err = s.checkEmailFree(email, user.Id); err != nil {
+		return err
+	}
+
+	if err = s.Repository.UpdateEmail(user.Id, email); err != nil {
+		return err
+	}
+
+	// Owner of the old address should know if it wasn't them
+	body := fmt.Sprintf("Hi %s,\n\nthe email of your account was changed to %s.\n", user.Username, email)
+	if err = s.Mailer.Send(user.Email, emailChangedSubject, body); err != nil {
+		log.Printf("cannot notify user %d about email change: %v\n", user.Id, err)
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) findUser(userId int) (User, error) {
+	return s.Repository.FindById(userId)
+}
+
+func (s *serviceImpl) checkEmailFree(email string, userId int) error {
+	existing, err := s.Repository.FindByEmail(email)
+	if err == nil && existing.Id != 0 && existing.Id != userId {
+		return ErrEmailInUse
+	}
+
+	return nil
+}
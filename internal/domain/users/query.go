@@ -0,0 +1,9 @@
+package users
+
+type UserQuery struct {
+	Search    string // part of username or email
+	Role      string // empty means any role
+	Suspended *bool  // nil means that filter is not applied
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,86 @@
+package users
+
+import (
+	"errors"
+	"time"
+
+	"mono_pardo/internal/utils"
+)
+
+/*
+	Refresh tokens are opaque random strings, only their hashes are stored.
+	Every login starts a new family (one family = one device session), every refresh rotates
+	the token: the used one is marked as rotated and a new one of the same family is issued.
+	Presenting already rotated token means that it was stolen, so the whole family is revoked.
+*/
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session is revoked")
+)
+
+type RefreshToken struct {
+	Id        int        `gorm:"type:int;primary_key"`
+	UserId    int        `gorm:"not null;index"`
+	FamilyId  string     `gorm:"type:varchar(64);not null;index"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Device    string     `gorm:"type:varchar(255);default:''"`
+	StartedAt time.Time  `gorm:"not null"` // login time of the family, kept on rotation
+	CreatedAt time.Time  `gorm:"default:now()"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	RotatedAt *time.Time // set when the token was exchanged for a new one
+	RevokedAt *time.Time
+}
+
+// NewRefreshToken returns the token to send to the client and its record to store
+func NewRefreshToken(userId int, device string, ttl time.Duration) (string, *RefreshToken, error) {
+	if userId <= 0 {
+		return "", nil, errors.New("invalid user ID")
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	familyId, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return token, &RefreshToken{
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: utils.HashToken(token),
+		Device:    device,
+		StartedAt: now,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// Rotate issues the next token of the same family
+func (t *RefreshToken) Rotate(ttl time.Duration) (string, *RefreshToken, error) {
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return token, &RefreshToken{
+		UserId:    t.UserId,
+		FamilyId:  t.FamilyId,
+		TokenHash: utils.HashToken(token),
+		Device:    t.Device,
+		StartedAt: t.StartedAt,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RotatedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
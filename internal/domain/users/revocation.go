@@ -0,0 +1,16 @@
+package users
+
+import "time"
+
+/*
+	Access tokens are stateless JWTs, so to invalidate one before "exp" its id has to be remembered
+	until it expires. Two kinds of ids are revoked:
+		jti - single access token, on logout
+		sid - every access token of the session (refresh token family), on logout from the device,
+		      logout everywhere and refresh token reuse
+*/
+
+type RevokedToken struct {
+	Id        string    `gorm:"type:varchar(64);primary_key"` // jti or sid
+	ExpiresAt time.Time `gorm:"not null;index"`               // after this time the record is useless
+}
@@ -0,0 +1,51 @@
+package users
+
+import (
+	"strings"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+/*
+	Role is stored on the user and copied to the access token, so it's checked without a query.
+	Tokens are short-lived and sessions are closed when the role is changed, so a token never keeps
+	the old role for long.
+*/
+
+const (
+	RoleUser    = "user"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
+var ErrAccountSuspended = domainErrors.Forbidden("account_suspended", "account is suspended")
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleTeacher, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// BootstrapAdmins gives admin role to existing users with the emails, so that the first admin
+// doesn't have to be created in the database by hand
+func BootstrapAdmins(repository Repository, emails []string) error {
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+
+		user, err := repository.FindByEmail(email)
+		if err != nil || user.Id == 0 || user.Role == RoleAdmin {
+			continue
+		}
+
+		if err = repository.UpdateRole(user.Id, RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
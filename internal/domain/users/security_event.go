@@ -0,0 +1,168 @@
+package users
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Security log of authentication and account events, to answer who accessed the account and when.
+	It's append-only: events are never changed, and deleted only together with the purged account. Failing
+	to write an event doesn't fail the action, because the user can't do anything about it.
+*/
+
+const (
+	EventLoginSucceeded     = "login_succeeded"
+	EventLoginFailed        = "login_failed"
+	EventRegistered         = "registered"
+	EventPasswordChanged    = "password_changed"
+	EventTokenRevoked       = "token_revoked"
+	EventAccessTokenCreated = "access_token_created"
+	EventAccessTokenDenied  = "access_token_denied"
+	EventRoleChanged        = "role_changed"
+	EventAccountDeleted     = "account_deleted"
+	EventSuspended          = "suspended"
+	EventUnsuspended        = "unsuspended"
+
+	DefaultSecurityEventsLimit = 50
+	MaxSecurityEventsLimit     = 200
+)
+
+var eventTypes = []string{
+	EventLoginSucceeded, EventLoginFailed, EventRegistered, EventPasswordChanged, EventTokenRevoked,
+	EventAccessTokenCreated, EventAccessTokenDenied, EventRoleChanged, EventAccountDeleted,
+	EventSuspended, EventUnsuspended,
+}
+
+type SecurityEvent struct {
+	Id        int       `gorm:"type:int;primary_key"`
+	UserId    int       `gorm:"not null;index"` // 0 if the account is unknown, e.g. login with wrong email
+	ActorId   int       `gorm:"not null"`       // admin who did it, 0 if it's the user
+	Type      string    `gorm:"type:varchar(32);not null;index"`
+	Details   string    `gorm:"type:varchar(255);not null"`
+	IP        string    `gorm:"type:varchar(45);not null;index"` // remote address, or X-Forwarded-For set by TRUSTED_PROXIES
+	UserAgent string    `gorm:"type:varchar(255);not null"`      // sent by the client, not verified
+	RequestId string    `gorm:"type:varchar(64);not null"`
+	CreatedAt time.Time `gorm:"default:now();index"`
+}
+
+type SecurityEventQuery struct {
+	UserId *int
+	Type   string
+	IP     string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+func NewSecurityEvent(eventType string, userId int, client request.ClientInfo, details string) SecurityEvent {
+	return SecurityEvent{
+		UserId:    userId,
+		Type:      eventType,
+		Details:   truncate(details, 255),
+		IP:        truncate(client.IP, 45),
+		UserAgent: truncate(client.UserAgent, 255),
+		RequestId: truncate(client.RequestId, 64),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func IsValidEventType(eventType string) bool {
+	return slices.Contains(eventTypes, eventType)
+}
+
+// ValidateSecurityEventsPage returns the limit to use
+func ValidateSecurityEventsPage(eventType string, limit, offset int) (int, error) {
+	if eventType != "" && !IsValidEventType(eventType) {
+		return 0, domainErrors.InvalidField("invalid_query", "type", "unknown event type: %s", eventType)
+	}
+	if offset < 0 {
+		return 0, domainErrors.InvalidField("invalid_query", "offset", "invalid offset")
+	}
+	if limit <= 0 {
+		return DefaultSecurityEventsLimit, nil
+	}
+	if limit > MaxSecurityEventsLimit {
+		return 0, domainErrors.InvalidField("invalid_query", "limit", "limit must be at most %d", MaxSecurityEventsLimit)
+	}
+
+	return limit, nil
+}
+
+func (s *serviceImpl) RecordSecurityEvent(event SecurityEvent) {
+	if err := s.SecurityEventRepository.Save(event); err != nil {
+		log.Printf("security event %s of user %d: %v\n", event.Type, event.UserId, err)
+	}
+}
+
+func (s *serviceImpl) recordEvent(eventType string, userId int, client request.ClientInfo, details string) {
+	s.RecordSecurityEvent(NewSecurityEvent(eventType, userId, client, details))
+}
+
+// recordLoginFailure names the email when the account is unknown, so attempts to guess accounts can be found
+func (s *serviceImpl) recordLoginFailure(userId int, email string, client request.ClientInfo, reason string) {
+	if userId == 0 {
+		reason = fmt.Sprintf("%s: %s", reason, email)
+	}
+	s.recordEvent(EventLoginFailed, userId, client, reason)
+}
+
+// GetSecurityEvents returns events of the user, the newest first
+func (s *serviceImpl) GetSecurityEvents(eventsRequest request.SecurityEventsRequest) (response.SecurityEventsResponse, error) {
+	limit, err := ValidateSecurityEventsPage(eventsRequest.Type, eventsRequest.Limit, eventsRequest.Offset)
+	if err != nil {
+		return response.SecurityEventsResponse{}, err
+	}
+
+	events, total, err := s.SecurityEventRepository.FindPage(SecurityEventQuery{
+		UserId: &eventsRequest.UserId,
+		Type:   eventsRequest.Type,
+		Limit:  limit,
+		Offset: eventsRequest.Offset,
+	})
+	if err != nil {
+		return response.SecurityEventsResponse{}, err
+	}
+
+	return ToSecurityEventsResponse(events, total, limit, eventsRequest.Offset), nil
+}
+
+func ToSecurityEventsResponse(events []SecurityEvent, total int64, limit, offset int) response.SecurityEventsResponse {
+	res := response.SecurityEventsResponse{
+		Events: make([]response.SecurityEventResponse, 0, len(events)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	for _, event := range events {
+		res.Events = append(res.Events, response.SecurityEventResponse{
+			Id:        event.Id,
+			UserId:    event.UserId,
+			ActorId:   event.ActorId,
+			Type:      event.Type,
+			Details:   event.Details,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			RequestId: event.RequestId,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return strings.ToValidUTF8(value[:length], "")
+}
@@ -6,7 +6,9 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	domainErrors "mono_pardo/internal/domain/errors"
 	"mono_pardo/internal/utils"
 	"mono_pardo/pkg/config"
 	"mono_pardo/pkg/data/request"
@@ -15,60 +17,355 @@ import (
 	"github.com/go-playground/validator"
 )
 
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// ErrInvalidCredentials is the same for unknown email and wrong password
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// Deps are everything the service needs, a new dependency is a new field here
+type Deps struct {
+	Config                  config.Config
+	Keys                    *utils.KeySet // signs and verifies access tokens
+	PasswordHasher          utils.PasswordHasher
+	Validate                *validator.Validate
+	Repository              Repository
+	RefreshTokenRepository  RefreshTokenRepository
+	RevocationStore         RevocationStore
+	PasswordResetRepository PasswordResetRepository
+	CounterStore            CounterStore
+	TwoFactorRepository     TwoFactorRepository
+	IdentityRepository      IdentityRepository
+	AccessTokenRepository   AccessTokenRepository
+	SecurityEventRepository SecurityEventRepository
+	OIDCProviders           map[string]OIDCProvider // by name, which is used in the URL
+	Mailer                  Mailer
+}
+
 type serviceImpl struct {
-	Config     config.Config
-	Validate   *validator.Validate
-	Repository Repository
+	Deps
+}
+
+func NewServiceImpl(deps Deps) Service {
+	return &serviceImpl{Deps: deps}
 }
 
-func NewServiceImpl(
-	config config.Config,
-	validate *validator.Validate,
-	repository Repository) Service {
-	return &serviceImpl{
-		Config:     config,
-		Validate:   validate,
-		Repository: repository,
+func (s *serviceImpl) Login(user request.LoginRequest) (response.LoginResponse, error) {
+	if err := s.Validate.Struct(user); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	email := strings.TrimSpace(user.Email)
+	failuresKey := loginFailuresKey(email)
+	if err := s.checkLockout(failuresKey); err != nil {
+		s.recordLoginFailure(0, email, user.Client, "locked")
+		return response.LoginResponse{}, err
+	}
+
+	foundUser, err := s.Repository.FindByEmail(email)
+	if err == nil {
+		err = s.PasswordHasher.Verify(foundUser.Password, strings.TrimSpace(user.Password))
+	}
+	if errors.Is(err, ErrUserNotFound) || errors.Is(err, utils.ErrPasswordMismatch) {
+		s.recordLoginFailure(foundUser.Id, email, user.Client, "wrong email or password")
+
+		// The last allowed failure locks the account right away
+		if lockErr := s.registerFailure(failuresKey); lockErr != nil {
+			return response.LoginResponse{}, lockErr
+		}
+		return response.LoginResponse{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	if err = s.CounterStore.Reset(failuresKey); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	if s.PasswordHasher.NeedsRehash(foundUser.Password) {
+		s.rehashPassword(foundUser, strings.TrimSpace(user.Password))
+	}
+
+	if foundUser.SuspendedAt != nil {
+		s.recordLoginFailure(foundUser.Id, email, user.Client, "account suspended")
+		return response.LoginResponse{}, ErrAccountSuspended
 	}
+
+	twoFactor, err := s.TwoFactorRepository.FindByUserId(foundUser.Id)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if twoFactor.IsEnabled() {
+		return s.mfaChallenge(foundUser, strings.TrimSpace(user.Device))
+	}
+
+	return s.startSession(foundUser, strings.TrimSpace(user.Device), "password", user.Client)
 }
 
-func (s *serviceImpl) Login(user request.LoginRequest) (string, error) {
-	foundUser, err := s.Repository.FindByEmail(strings.TrimSpace(user.Email))
+// rehashPassword upgrades the hash to the current algorithm, the password is known only on login.
+// Login doesn't fail if it can't be done, it's tried again next time
+func (s *serviceImpl) rehashPassword(user User, password string) {
+	hashedPassword, err := s.PasswordHasher.Hash(password)
+	if err == nil {
+		err = s.Repository.UpdatePassword(user.Id, hashedPassword)
+	}
 	if err != nil {
-		return "", err
+		log.Printf("password rehash of user %d: %v\n", user.Id, err)
 	}
+}
 
-	if err = utils.VerifyPassword(foundUser.Password, strings.TrimSpace(user.Password)); err != nil {
-		return "", err
+// startSession finishes login, method is how the user was authenticated
+func (s *serviceImpl) startSession(user User, device, method string, client request.ClientInfo) (response.LoginResponse, error) {
+	refreshToken, record, err := NewRefreshToken(user.Id, device, s.refreshTokenTTL())
+	if err != nil {
+		return response.LoginResponse{}, err
 	}
 
-	token, err := utils.GenerateToken(s.Config.TokenExpiresIn, foundUser.Id, s.Config.TokenSecret)
+	res, err := s.issueTokens(refreshToken, *record, user)
 	if err != nil {
-		return "", err
+		return res, err
+	}
+
+	s.recordEvent(EventLoginSucceeded, user.Id, client, method)
+	return res, nil
+}
+
+func (s *serviceImpl) Refresh(refreshRequest request.RefreshTokenRequest) (response.LoginResponse, error) {
+	if err := s.Validate.Struct(refreshRequest); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	current, err := s.RefreshTokenRepository.FindByHash(utils.HashToken(refreshRequest.RefreshToken))
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if current.Id == 0 {
+		return response.LoginResponse{}, ErrInvalidRefreshToken
+	}
+
+	now := time.Now().UTC()
+
+	if current.RotatedAt != nil {
+		return response.LoginResponse{}, s.revokeReusedFamily(current, refreshRequest.Client)
+	}
+	if !current.IsActive(now) {
+		return response.LoginResponse{}, ErrInvalidRefreshToken
+	}
+
+	// Two concurrent requests with the same token: only one of them can rotate it
+	rotated, err := s.RefreshTokenRepository.MarkRotated(current.Id, now)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !rotated {
+		return response.LoginResponse{}, s.revokeReusedFamily(current, refreshRequest.Client)
+	}
+
+	// Role could be changed since the last refresh, and the account could be suspended or deleted
+	user, err := s.Repository.FindById(current.UserId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return response.LoginResponse{}, err
+	}
+	if err != nil || user.SuspendedAt != nil {
+		return response.LoginResponse{}, ErrInvalidRefreshToken
+	}
+
+	refreshToken, next, err := current.Rotate(s.refreshTokenTTL())
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	return s.issueTokens(refreshToken, *next, user)
+}
+
+func (s *serviceImpl) GetSessions(userId int) ([]response.SessionResponse, error) {
+	sessions := []response.SessionResponse{}
+
+	tokens, err := s.RefreshTokenRepository.FindActiveByUserId(userId, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// Family has only one active token at a time, so every token is a separate session
+	for _, token := range tokens {
+		sessions = append(sessions, response.SessionResponse{
+			Id:         token.FamilyId,
+			Device:     token.Device,
+			StartedAt:  token.StartedAt,
+			LastUsedAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+func (s *serviceImpl) RevokeSession(revokeRequest request.RevokeSessionRequest) error {
+	tokens, err := s.RefreshTokenRepository.FindActiveByUserId(revokeRequest.UserId, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if token.FamilyId != revokeRequest.SessionId {
+			continue
+		}
+		if err = s.revokeSession(token.FamilyId); err != nil {
+			return err
+		}
+
+		s.recordEvent(EventTokenRevoked, revokeRequest.UserId, revokeRequest.Client, "session "+token.FamilyId)
+		return nil
+	}
+
+	return domainErrors.NotFound("session_not_found", "session is not found: %s", revokeRequest.SessionId)
+}
+
+func (s *serviceImpl) Logout(logoutRequest request.LogoutRequest) error {
+	claims, err := utils.ValidateToken(logoutRequest.Token, s.Keys)
+	if err != nil {
+		return errors.New("cannot validate token")
+	}
+
+	if claims.Id != "" {
+		if err = s.RevocationStore.Revoke(claims.Id, claims.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	details := "logout"
+	if claims.SessionId != "" {
+		if err = s.revokeSession(claims.SessionId); err != nil {
+			return err
+		}
+		details = "session " + claims.SessionId + ", logout"
+	}
+
+	userId, _ := strconv.Atoi(fmt.Sprint(claims.Subject))
+	s.recordEvent(EventTokenRevoked, userId, logoutRequest.Client, details)
+	return nil
+}
+
+func (s *serviceImpl) LogoutEverywhere(logoutRequest request.LogoutEverywhereRequest) error {
+	if err := s.revokeAllSessions(logoutRequest.UserId); err != nil {
+		return err
+	}
+
+	event := NewSecurityEvent(EventTokenRevoked, logoutRequest.UserId, logoutRequest.Client, "all sessions")
+	event.ActorId = logoutRequest.AdminId
+	s.RecordSecurityEvent(event)
+	return nil
+}
+
+func (s *serviceImpl) revokeAllSessions(userId int) error {
+	tokens, err := s.RefreshTokenRepository.FindActiveByUserId(userId, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if err = s.revokeSession(token.FamilyId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) issueTokens(refreshToken string, record RefreshToken, user User) (response.LoginResponse, error) {
+	if err := s.RefreshTokenRepository.Save(record); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	token, err := utils.GenerateToken(s.Config.TokenExpiresIn, record.UserId, record.FamilyId, user.Role, s.Keys)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	return response.LoginResponse{
+		TokenType:    "Bearer",
+		Token:        token,
+		ExpiresIn:    int64(s.Config.TokenExpiresIn.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// revokeReusedFamily handles token which was already exchanged: either the client or an attacker
+// holds a stolen copy, and we can't tell which one, so the whole session is closed
+func (s *serviceImpl) revokeReusedFamily(token RefreshToken, client request.ClientInfo) error {
+	log.Printf("refresh token reuse detected: user %d, family %s\n", token.UserId, token.FamilyId)
+
+	if err := s.revokeSession(token.FamilyId); err != nil {
+		return err
+	}
+	s.recordEvent(EventTokenRevoked, token.UserId, client, "session "+token.FamilyId+", refresh token reused")
+
+	return ErrRefreshTokenReused
+}
+
+// revokeSession closes the session and invalidates access tokens which were already issued for it
+func (s *serviceImpl) revokeSession(familyId string) error {
+	if err := s.RefreshTokenRepository.RevokeFamily(familyId); err != nil {
+		return err
+	}
+
+	// Access token of the session can't outlive the latest refresh by more than its lifetime
+	return s.RevocationStore.Revoke(familyId, time.Now().UTC().Add(s.Config.TokenExpiresIn))
+}
+
+func (s *serviceImpl) refreshTokenTTL() time.Duration {
+	if s.Config.RefreshTokenExpiresIn > 0 {
+		return s.Config.RefreshTokenExpiresIn
 	}
-	return token, nil
+	return defaultRefreshTokenTTL
 }
 
 func (s *serviceImpl) Register(user request.CreateUserRequest) error {
-	newUser, err := NewUser(user.Username, user.Email, user.Password)
+	if err := s.Validate.Struct(user); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordPolicy("password", strings.TrimSpace(user.Password), user.Email); err != nil {
+		return err
+	}
+
+	newUser, err := NewUser(user.Username, user.Email, user.Password, s.PasswordHasher)
 	if err != nil {
 		return err
 	}
 
+	if err = s.checkEmailFree(newUser.Email, 0); err != nil {
+		return err
+	}
+
+	// Email of a deleted account is still taken until the account is purged,
+	// Save returns ErrEmailInUse for it and for a concurrent registration
 	if err = s.Repository.Save(*newUser); err != nil {
 		return err
 	}
 
+	savedUser, err := s.Repository.FindByEmail(newUser.Email)
+	if err != nil {
+		return err
+	}
+
+	s.recordEvent(EventRegistered, savedUser.Id, user.Client, "")
+
+	// Account is created anyway, user can ask to send the email again
+	if err = s.sendVerification(savedUser); err != nil {
+		log.Printf("registration of user %d: %v\n", savedUser.Id, err)
+	}
+
 	return nil
 }
 
 func (s *serviceImpl) GetUserId(token string) (int, error) {
-	user, err := utils.ValidateToken(token, s.Config.TokenSecret)
+	claims, err := s.validateToken(token)
 	if err != nil {
-		return 0, errors.New("cannot validate token")
+		return 0, err
 	}
 
-	userId, err := strconv.Atoi(fmt.Sprint(user))
+	userId, err := strconv.Atoi(fmt.Sprint(claims.Subject))
 	if err != nil {
 		return 0, fmt.Errorf("failed to get id: %w\n", err)
 	}
@@ -76,6 +373,41 @@ func (s *serviceImpl) GetUserId(token string) (int, error) {
 	return userId, nil
 }
 
+// GetRole returns role the token was issued for, tokens issued before roles were introduced belong to users
+func (s *serviceImpl) GetRole(token string) (string, error) {
+	claims, err := s.validateToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	if claims.Role == "" {
+		return RoleUser, nil
+	}
+	return claims.Role, nil
+}
+
+func (s *serviceImpl) validateToken(token string) (utils.TokenClaims, error) {
+	claims, err := utils.ValidateToken(token, s.Keys)
+	if err != nil {
+		return claims, errors.New("cannot validate token")
+	}
+
+	revoked, err := s.RevocationStore.IsRevoked(claims.Id, claims.SessionId)
+	if err != nil {
+		return claims, err
+	}
+	if revoked {
+		return claims, errors.New("token is revoked")
+	}
+
+	return claims, nil
+}
+
+// GetJWKS returns public keys, which verify access tokens
+func (s *serviceImpl) GetJWKS() response.JWKSResponse {
+	return s.Keys.JWKS()
+}
+
 func (s *serviceImpl) FindUser(userId int) (response.UserResponse, error) {
 	user, err := s.Repository.FindById(userId)
 	if err != nil {
@@ -83,8 +415,17 @@ func (s *serviceImpl) FindUser(userId int) (response.UserResponse, error) {
 		return response.UserResponse{}, err
 	}
 
+	return ToUserResponse(user), nil
+}
+
+func ToUserResponse(user User) response.UserResponse {
 	return response.UserResponse{
-		Email:    user.Email,
-		Username: user.Username,
-	}, nil
+		Id:         user.Id,
+		Email:      user.Email,
+		Username:   user.Username,
+		IsVerified: user.IsVerified,
+		Role:       user.Role,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+	}
 }
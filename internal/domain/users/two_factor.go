@@ -0,0 +1,290 @@
+package users
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Two-factor authentication with TOTP. Enrolment stores the secret, which starts working only after
+	the user confirms it with the first code, then single-use recovery codes are issued. Login to
+	the account with 2FA returns a short-lived challenge, which is exchanged for tokens with a code.
+	Each TOTP code is accepted once, failed codes are counted the same way as failed logins.
+*/
+
+const (
+	totpIssuer            = "Pardo"
+	mfaChallengePurpose   = "mfa_challenge"
+	mfaChallengeTTL       = 5 * time.Minute
+	mfaFailuresKeyPrefix  = "mfa_failures:"
+	recoveryCodesCount    = 10
+	recoveryCodeHalfChars = 5
+)
+
+var (
+	ErrTwoFactorEnabled    = domainErrors.Conflict("two_factor_enabled", "two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled = domainErrors.Conflict("two_factor_not_enabled", "two-factor authentication is not enabled")
+	ErrInvalidMFACode      = domainErrors.Invalid("invalid_mfa_code", "invalid authentication code")
+	ErrInvalidMFAChallenge = domainErrors.Invalid("invalid_mfa_challenge", "invalid or expired mfa challenge, please log in again")
+)
+
+type TwoFactor struct {
+	UserId      int        `gorm:"primary_key;autoIncrement:false"`
+	Secret      string     `gorm:"type:varchar(64);not null"`
+	EnabledAt   *time.Time // nil until the secret is confirmed with the first code
+	LastCounter int64      `gorm:"not null;default:0"` // time counter of the last accepted code
+	CreatedAt   time.Time  `gorm:"default:now()"`
+}
+
+func (t *TwoFactor) IsEnabled() bool {
+	return t.UserId != 0 && t.EnabledAt != nil
+}
+
+type RecoveryCode struct {
+	Id       int    `gorm:"type:int;primary_key"`
+	UserId   int    `gorm:"not null;index"`
+	CodeHash string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UsedAt   *time.Time
+}
+
+func (s *serviceImpl) EnrollTwoFactor(enrollRequest request.EnrollTwoFactorRequest) (response.TwoFactorEnrollmentResponse, error) {
+	if err := s.Validate.Struct(enrollRequest); err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+
+	user, err := s.findUser(enrollRequest.UserId)
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+
+	if err = s.PasswordHasher.Verify(user.Password, strings.TrimSpace(enrollRequest.Password)); err != nil {
+		return response.TwoFactorEnrollmentResponse{}, ErrWrongPassword
+	}
+
+	current, err := s.TwoFactorRepository.FindByUserId(user.Id)
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+	if current.IsEnabled() {
+		return response.TwoFactorEnrollmentResponse{}, ErrTwoFactorEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+
+	// Unconfirmed enrolment is replaced
+	if err = s.TwoFactorRepository.Save(TwoFactor{UserId: user.Id, Secret: secret}); err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+
+	return response.TwoFactorEnrollmentResponse{
+		Secret: secret,
+		URI:    utils.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *serviceImpl) ConfirmTwoFactor(confirmRequest request.ConfirmTwoFactorRequest) (response.RecoveryCodesResponse, error) {
+	if err := s.Validate.Struct(confirmRequest); err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+
+	twoFactor, err := s.TwoFactorRepository.FindByUserId(confirmRequest.UserId)
+	if err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+	if twoFactor.UserId == 0 {
+		return response.RecoveryCodesResponse{}, domainErrors.Conflict("two_factor_not_enrolled", "two-factor authentication is not enrolled")
+	}
+	if twoFactor.IsEnabled() {
+		return response.RecoveryCodesResponse{}, ErrTwoFactorEnabled
+	}
+
+	counter, ok := utils.ValidateTOTP(twoFactor.Secret, confirmRequest.Code, time.Now())
+	if !ok {
+		return response.RecoveryCodesResponse{}, ErrInvalidMFACode
+	}
+
+	if err = s.TwoFactorRepository.Enable(twoFactor.UserId, time.Now().UTC(), counter); err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+
+	return s.issueRecoveryCodes(twoFactor.UserId)
+}
+
+func (s *serviceImpl) DisableTwoFactor(disableRequest request.DisableTwoFactorRequest) error {
+	if err := s.Validate.Struct(disableRequest); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(disableRequest.UserId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.PasswordHasher.Verify(user.Password, strings.TrimSpace(disableRequest.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	twoFactor, err := s.TwoFactorRepository.FindByUserId(user.Id)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err = s.verifySecondFactor(twoFactor, disableRequest.Code); err != nil {
+		return err
+	}
+
+	return s.TwoFactorRepository.Delete(user.Id)
+}
+
+// VerifyMFA finishes login to the account with 2FA
+func (s *serviceImpl) VerifyMFA(mfaRequest request.MFALoginRequest) (response.LoginResponse, error) {
+	if err := s.Validate.Struct(mfaRequest); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	claims, err := utils.ValidateSignedToken(mfaChallengePurpose, mfaRequest.MFAToken, s.Config.TokenSecret)
+	if err != nil {
+		return response.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	userId, err := strconv.Atoi(fmt.Sprint(claims["sub"]))
+	if err != nil {
+		return response.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	failuresKey := mfaFailuresKey(userId)
+	if err = s.checkLockout(failuresKey); err != nil {
+		s.recordEvent(EventLoginFailed, userId, mfaRequest.Client, "2fa locked")
+		return response.LoginResponse{}, err
+	}
+
+	user, err := s.Repository.FindById(userId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return response.LoginResponse{}, err
+	}
+	if err != nil || user.SuspendedAt != nil {
+		return response.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	twoFactor, err := s.TwoFactorRepository.FindByUserId(user.Id)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !twoFactor.IsEnabled() {
+		return response.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	if err = s.verifySecondFactor(twoFactor, mfaRequest.Code); err != nil {
+		s.recordEvent(EventLoginFailed, user.Id, mfaRequest.Client, "wrong 2fa code")
+
+		if lockErr := s.registerFailure(failuresKey); lockErr != nil {
+			return response.LoginResponse{}, lockErr
+		}
+		return response.LoginResponse{}, err
+	}
+
+	if err = s.CounterStore.Reset(failuresKey); err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	device, _ := claims["device"].(string)
+	return s.startSession(user, device, "2fa", mfaRequest.Client)
+}
+
+// mfaChallenge is returned by login instead of tokens when 2FA is enabled
+func (s *serviceImpl) mfaChallenge(user User, device string) (response.LoginResponse, error) {
+	token, err := utils.GenerateSignedToken(mfaChallengePurpose, mfaChallengeTTL, map[string]interface{}{
+		"sub":    user.Id,
+		"device": device,
+	}, s.Config.TokenSecret)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+
+	return response.LoginResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int64(mfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+// verifySecondFactor accepts TOTP code or unused recovery code
+func (s *serviceImpl) verifySecondFactor(twoFactor TwoFactor, code string) error {
+	if counter, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		// The code could be intercepted, so it can't be used twice
+		fresh, err := s.TwoFactorRepository.UseCounter(twoFactor.UserId, counter)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.TwoFactorRepository.UseRecoveryCode(twoFactor.UserId, utils.HashToken(normalizeRecoveryCode(code)), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) issueRecoveryCodes(userId int) (response.RecoveryCodesResponse, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	records := make([]RecoveryCode, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return response.RecoveryCodesResponse{}, err
+		}
+
+		codes = append(codes, code)
+		records = append(records, RecoveryCode{UserId: userId, CodeHash: utils.HashToken(normalizeRecoveryCode(code))})
+	}
+
+	if err := s.TwoFactorRepository.ReplaceRecoveryCodes(userId, records); err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+
+	return response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// generateRecoveryCode returns code like "k3m9x-2qpfa", which is easy to type from a printout
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating recovery code failed: %w", err)
+	}
+
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+	return code[:recoveryCodeHalfChars] + "-" + code[recoveryCodeHalfChars:2*recoveryCodeHalfChars], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+func mfaFailuresKey(userId int) string {
+	return mfaFailuresKeyPrefix + strconv.Itoa(userId)
+}
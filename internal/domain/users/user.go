@@ -1,22 +1,37 @@
 package users
 
 import (
-	"errors"
 	"regexp"
 	"strings"
+	"time"
 
+	domainErrors "mono_pardo/internal/domain/errors"
 	"mono_pardo/internal/utils"
+
+	"gorm.io/gorm"
 )
 
+var ErrUserNotFound = domainErrors.NotFound("user_not_found", "user is not found")
+
 type User struct {
 	Id       int    `gorm:"type:int;primary_key"`
 	Username string `gorm:"type:varchar(255);not null"`
 	Email    string `gorm:"uniqueIndex;not null"`
 	Password string `gorm:"not null"`
+
+	IsVerified bool      `gorm:"default:false"` // user confirmed the email
+	CreatedAt  time.Time `gorm:"default:now()"`
+	UpdatedAt  time.Time `gorm:"default:now()"`
+
+	Role        string     `gorm:"type:varchar(16);not null;default:'user'"`
+	SuspendedAt *time.Time // suspended user can't log in
+
+	// Deleted account is hidden from all queries and purged after DefaultDeletionGracePeriod
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
-func NewUser(username, email, password string) (*User, error) {
-	hashedPassword, err := utils.HashPassword(strings.TrimSpace(password))
+func NewUser(username, email, password string, hasher utils.PasswordHasher) (*User, error) {
+	hashedPassword, err := hasher.Hash(strings.TrimSpace(password))
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +40,14 @@ func NewUser(username, email, password string) (*User, error) {
 	validEmail := strings.TrimSpace(email)
 
 	if !isValidEmail(validEmail) {
-		return nil, errors.New("invalid email format")
+		return nil, domainErrors.InvalidField("invalid_email", "email", "invalid email format")
 	}
 
 	return &User{
 		Username: validUsername,
 		Email:    validEmail,
 		Password: hashedPassword,
+		Role:     RoleUser,
 	}, nil
 }
 
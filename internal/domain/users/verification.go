@@ -0,0 +1,103 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+)
+
+/*
+	Email verification. The link contains a signed token with user id and the email it was sent to,
+	so it stops working once the email is verified or changed, which makes it one-time.
+*/
+
+const (
+	verifyEmailPurpose       = "verify_email"
+	defaultVerificationTTL   = 24 * time.Hour
+	verificationEmailSubject = "Confirm your email"
+	verificationEmailPath    = "/verify-email"
+)
+
+var (
+	ErrAlreadyVerified         = domainErrors.Conflict("already_verified", "email is already verified")
+	ErrInvalidVerificationLink = domainErrors.Invalid("invalid_verification_link", "invalid or expired verification link")
+)
+
+func (s *serviceImpl) VerifyEmail(verifyRequest request.VerifyEmailRequest) error {
+	if err := s.Validate.Struct(verifyRequest); err != nil {
+		return err
+	}
+
+	claims, err := utils.ValidateSignedToken(verifyEmailPurpose, verifyRequest.Token, s.Config.TokenSecret)
+	if err != nil {
+		return ErrInvalidVerificationLink
+	}
+
+	userId, err := strconv.Atoi(fmt.Sprint(claims["sub"]))
+	if err != nil {
+		return ErrInvalidVerificationLink
+	}
+
+	user, err := s.Repository.FindById(userId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+
+	// Link was sent to another email, which user has changed since then
+	if err != nil || user.Email != claims["email"] {
+		return ErrInvalidVerificationLink
+	}
+
+	if user.IsVerified {
+		return ErrAlreadyVerified
+	}
+
+	return s.Repository.MarkVerified(user.Id)
+}
+
+func (s *serviceImpl) ResendVerification(userId int) error {
+	user, err := s.findUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if user.IsVerified {
+		return ErrAlreadyVerified
+	}
+
+	return s.sendVerification(user)
+}
+
+func (s *serviceImpl) sendVerification(user User) error {
+	ttl := s.Config.EmailVerificationExpiresIn
+	if ttl <= 0 {
+		ttl = defaultVerificationTTL
+	}
+
+	token, err := utils.GenerateSignedToken(verifyEmailPurpose, ttl, map[string]interface{}{
+		"sub":   user.Id,
+		"email": user.Email,
+	}, s.Config.TokenSecret)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", s.Config.AppURL, verificationEmailPath, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\n\nplease confirm your email by opening the link below:\n\n%s\n\nThe link is valid for %s.\n",
+		user.Username, link, ttl)
+
+	if err = s.Mailer.Send(user.Email, verificationEmailSubject, body); err != nil {
+		log.Printf("cannot send verification email to user %d: %v\n", user.Id, err)
+		return errors.New("cannot send verification email")
+	}
+
+	return nil
+}
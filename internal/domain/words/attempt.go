@@ -0,0 +1,38 @@
+package words
+
+import (
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+const ReviewExercise = "review"
+
+// TrainingAttempt is an append-only log record of a single exercise result
+type TrainingAttempt struct {
+	Id             int       `gorm:"type:int;primary_key"`
+	UserId         int       `gorm:"not null;index"`
+	WordId         int       `gorm:"not null;index:idx_attempt_word_created,priority:1"`
+	Exercise       string    `gorm:"type:varchar(32);not null"`
+	IsCorrect      bool      `gorm:"not null"`
+	ResponseTimeMs int       `gorm:"default:0"`
+	CreatedAt      time.Time `gorm:"default:now();index:idx_attempt_word_created,priority:2"`
+}
+
+func NewTrainingAttempt(userId, wordId int, exercise string, isCorrect bool, responseTimeMs int) (*TrainingAttempt, error) {
+	if exercise == "" {
+		return nil, domainErrors.InvalidField("invalid_attempt", "exercise", "exercise is required field")
+	}
+	if responseTimeMs < 0 {
+		return nil, domainErrors.InvalidField("invalid_attempt", "response_time_ms", "response time cannot be negative")
+	}
+
+	return &TrainingAttempt{
+		UserId:         userId,
+		WordId:         wordId,
+		Exercise:       exercise,
+		IsCorrect:      isCorrect,
+		ResponseTimeMs: responseTimeMs,
+		CreatedAt:      time.Now().UTC(),
+	}, nil
+}
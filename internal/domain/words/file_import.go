@@ -0,0 +1,75 @@
+package words
+
+import (
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+type importServiceImpl struct {
+	VocabService Service
+	Parser       FileParser
+}
+
+func NewImportServiceImpl(vocabService Service, parser FileParser) ImportService {
+	return &importServiceImpl{
+		VocabService: vocabService,
+		Parser:       parser,
+	}
+}
+
+func (s *importServiceImpl) ImportCSV(importRequest request.ImportCSVRequest) (response.ImportReport, error) {
+	rows, err := s.Parser.ParseDelimited(importRequest)
+	if err != nil {
+		return response.ImportReport{}, err
+	}
+
+	return s.VocabService.ImportWords(request.ImportWordsRequest{
+		UserId:     importRequest.UserId,
+		Rows:       rows,
+		OnConflict: importRequest.OnConflict,
+		DryRun:     importRequest.DryRun,
+	})
+}
+
+func (s *importServiceImpl) ImportAnki(importRequest request.ImportAnkiRequest) (response.AnkiImportReport, error) {
+	rows, media, err := s.Parser.ParseAnki(importRequest)
+	if err != nil {
+		return response.AnkiImportReport{}, err
+	}
+
+	report, err := s.VocabService.ImportWords(request.ImportWordsRequest{
+		UserId:     importRequest.UserId,
+		Rows:       rows,
+		OnConflict: importRequest.OnConflict,
+		DryRun:     importRequest.DryRun,
+	})
+	if err != nil {
+		return response.AnkiImportReport{}, err
+	}
+
+	return response.AnkiImportReport{ImportReport: report, Media: media}, nil
+}
+
+func (s *importServiceImpl) ImportKindle(importRequest request.ImportKindleRequest) (response.KindleImportReport, error) {
+	if importRequest.From != nil && importRequest.To != nil && importRequest.From.After(*importRequest.To) {
+		return response.KindleImportReport{}, domainErrors.InvalidField("invalid_period", "to", "'from' must be before 'to'")
+	}
+
+	rows, books, err := s.Parser.ParseKindle(importRequest)
+	if err != nil {
+		return response.KindleImportReport{}, err
+	}
+
+	report, err := s.VocabService.ImportWords(request.ImportWordsRequest{
+		UserId:     importRequest.UserId,
+		Rows:       rows,
+		OnConflict: importRequest.OnConflict,
+		DryRun:     importRequest.DryRun,
+	})
+	if err != nil {
+		return response.KindleImportReport{}, err
+	}
+
+	return response.KindleImportReport{ImportReport: report, Books: books}, nil
+}
@@ -0,0 +1,182 @@
+package words
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+/*
+	Bulk import of words. Every row is validated with the same rules as a single word (NewWord).
+	Words which already exist in user's vocab (idx_user_word) are resolved with conflict strategy:
+		skip      - keep existing word untouched
+		overwrite - replace definition, tags and notes of existing word
+		merge     - append new definition to existing one and join tags and notes
+	In dry-run mode nothing is saved, but report is the same as for real import.
+	Rows marked as learned (e.g. mature cards from Anki) skip trainings and go straight to review queue.
+*/
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictMerge     = "merge"
+
+	MaxImportRows = 10000
+)
+
+var conflictResolutions = map[string]string{
+	ConflictSkip:      "skipped",
+	ConflictOverwrite: "overwritten",
+	ConflictMerge:     "merged",
+}
+
+func (s *serviceImpl) ImportWords(importRequest request.ImportWordsRequest) (response.ImportReport, error) {
+	onConflict := importRequest.OnConflict
+	if onConflict == "" {
+		onConflict = ConflictSkip
+	}
+	if _, ok := conflictResolutions[onConflict]; !ok {
+		return response.ImportReport{}, domainErrors.InvalidField("invalid_import", "on_conflict", "invalid conflict strategy: %s", onConflict)
+	}
+
+	if len(importRequest.Rows) > MaxImportRows {
+		return response.ImportReport{}, domainErrors.Invalid("too_many_rows", "cannot import more than %d rows at once", MaxImportRows)
+	}
+
+	report := response.ImportReport{
+		DryRun:     importRequest.DryRun,
+		Total:      len(importRequest.Rows),
+		Errors:     []response.ImportRowError{},
+		Duplicates: []response.ImportDuplicate{},
+	}
+
+	// line where the word was met first, to catch duplicates inside the file
+	seen := make(map[string]int)
+	now := time.Now().UTC()
+
+	type importRow struct {
+		line int
+		word *Word
+	}
+	rows := make([]importRow, 0, len(importRequest.Rows))
+
+	for _, row := range importRequest.Rows {
+		newWord, err := NewWord(row.Word, row.Definition, importRequest.UserId)
+		if err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, response.ImportRowError{Line: row.Line, Word: row.Word, Message: err.Error()})
+			continue
+		}
+		newWord.Tags = NormalizeTags(row.Tags)
+		newWord.Notes = strings.TrimSpace(row.Notes)
+		newWord.Context = strings.TrimSpace(row.Context)
+		newWord.Source = strings.TrimSpace(row.Source)
+		if row.IsLearned {
+			newWord.ScheduleImported(row.IntervalDays, now)
+		}
+
+		if line, ok := seen[newWord.Word]; ok {
+			report.Failed++
+			report.Errors = append(report.Errors, response.ImportRowError{
+				Line:    row.Line,
+				Word:    newWord.Word,
+				Message: fmt.Sprintf("duplicate of the word on line %d", line),
+			})
+			continue
+		}
+		seen[newWord.Word] = row.Line
+
+		rows = append(rows, importRow{line: row.Line, word: newWord})
+	}
+
+	// Existing words are found with one query instead of one per row
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.word.Word)
+	}
+	existingWords, err := s.Repository.FindByWords(importRequest.UserId, names)
+	if err != nil {
+		return response.ImportReport{}, err
+	}
+	existingByWord := make(map[string]Word, len(existingWords))
+	for _, word := range existingWords {
+		existingByWord[word.Word] = word
+	}
+
+	// Rows are saved one by one, so a failed row is reported and the rest are still imported
+	for _, row := range rows {
+		newWord := row.word
+
+		existing, ok := existingByWord[newWord.Word]
+		if !ok {
+			if !importRequest.DryRun {
+				if _, err = s.Repository.Save(*newWord); err != nil {
+					report.Failed++
+					report.Errors = append(report.Errors, response.ImportRowError{Line: row.line, Word: newWord.Word, Message: err.Error()})
+					continue
+				}
+			}
+			report.Created++
+			continue
+		}
+
+		report.Duplicates = append(report.Duplicates, response.ImportDuplicate{
+			Line:       row.line,
+			Word:       newWord.Word,
+			Resolution: conflictResolutions[onConflict],
+		})
+
+		if onConflict == ConflictSkip {
+			report.Skipped++
+			continue
+		}
+
+		if !importRequest.DryRun {
+			update := request.WordUpdate{WordId: existing.Id, Updates: resolveConflict(existing, *newWord, onConflict)}
+			if err = s.Repository.Update(update); err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, response.ImportRowError{Line: row.line, Word: newWord.Word, Message: err.Error()})
+				continue
+			}
+		}
+		report.Updated++
+	}
+
+	return report, nil
+}
+
+func resolveConflict(existing, imported Word, onConflict string) []request.FieldUpdate {
+	if onConflict == ConflictOverwrite {
+		return []request.FieldUpdate{
+			{Field: "definition", Value: imported.Definition},
+			{Field: "tags", Value: imported.Tags},
+			{Field: "notes", Value: imported.Notes},
+			{Field: "context", Value: imported.Context},
+			{Field: "source", Value: imported.Source},
+		}
+	}
+
+	return []request.FieldUpdate{
+		{Field: "definition", Value: mergeText(existing.Definition, imported.Definition, "; ")},
+		{Field: "tags", Value: NormalizeTags(existing.Tags + " " + imported.Tags)},
+		{Field: "notes", Value: mergeText(existing.Notes, imported.Notes, "\n")},
+		{Field: "context", Value: mergeText(existing.Context, imported.Context, "\n")},
+		{Field: "source", Value: mergeText(existing.Source, imported.Source, "; ")},
+	}
+}
+
+// mergeText appends addition to the text unless text already contains it
+func mergeText(text, addition, separator string) string {
+	switch {
+	case addition == "" || strings.Contains(strings.ToLower(text), strings.ToLower(addition)):
+		return text
+	case text == "":
+		return addition
+	default:
+		return text + separator + addition
+	}
+}
@@ -1,6 +1,8 @@
 package words
 
 import (
+	"time"
+
 	"mono_pardo/pkg/data/request"
 	"mono_pardo/pkg/data/response"
 )
@@ -8,21 +10,53 @@ import (
 type Service interface {
 	CreateWord(createWordRequest request.CreateWordRequest) error
 	DeleteWord(deleteWordRequest request.DeleteWordRequest) error
-	GetWords(vocabRequest request.VocabRequest) ([]response.VocabResponse, error)
+	GetWords(vocabRequest request.VocabRequest) (response.VocabPageResponse, error)
+	GetAllWords(vocabRequest request.VocabRequest) ([]response.VocabResponse, error)
 	FindWord(findWordRequest request.FindWordRequest) (response.VocabResponse, error)
 	UpdateWord(updateWordRequest request.UpdateWordRequest) error
+	ReviewWord(reviewWordRequest request.ReviewWordRequest) (response.VocabResponse, error)
+	GetDueWords(dueWordsRequest request.DueWordsRequest) ([]response.VocabResponse, error)
+	GetHistory(historyRequest request.HistoryRequest) (response.HistoryResponse, error)
+	SearchWords(searchRequest request.SearchWordsRequest) ([]response.VocabResponse, error)
+	ImportWords(importRequest request.ImportWordsRequest) (response.ImportReport, error)
+	ExportWords(exportRequest request.ExportWordsRequest) ([]response.VocabResponse, error)
 	updateWordStatus(wordId int) error
 	validateWordUpdates(updates []request.WordUpdate) error
 }
 
+// ImportService imports files of other apps, FileParser reads them and ImportWords saves the rows
+type ImportService interface {
+	ImportCSV(importRequest request.ImportCSVRequest) (response.ImportReport, error)
+	ImportAnki(importRequest request.ImportAnkiRequest) (response.AnkiImportReport, error)
+	ImportKindle(importRequest request.ImportKindleRequest) (response.KindleImportReport, error)
+}
+
+// FileParser reads files of other apps into import rows, errors are about the file itself
+type FileParser interface {
+	ParseDelimited(parseRequest request.ImportCSVRequest) ([]request.ImportRow, error)
+	ParseAnki(parseRequest request.ImportAnkiRequest) ([]request.ImportRow, []response.ImportMedia, error)
+	ParseKindle(parseRequest request.ImportKindleRequest) ([]request.ImportRow, []response.KindleBook, error)
+}
+
 type Repository interface {
 	// Add(word Word) (int, error)
-	Save(word Word) error
+	Save(word Word) (int, error)
 	Update(word request.WordUpdate) error
 	Delete(wordId int) error
+	DeleteByUserId(userId int) error // also removes training history
 	FindByUserId(userId int) ([]Word, error)
-	FindById(wordId int) (Word, error)
+	FindPage(query WordQuery) ([]Word, error)               // returns up to query.Limit+1 words
+	FindById(wordId int) (Word, error)                      // Id is 0 if the word doesn't exist
+	FindByIds(userId int, wordIds []int) ([]Word, error)    // only words owned by the user
+	FindByWord(userId int, word string) (Word, error)       // Id is 0 if user doesn't have the word
+	FindByWords(userId int, words []string) ([]Word, error) // only words the user has
+	FindDueByUserId(userId int, now time.Time, limit int) ([]Word, error)
+	Search(userId int, query string, limit int) ([]Word, error) // ordered by relevance
+	CountByUserIds(userIds []int) (map[int]VocabCount, error)   // users without words are missing
 
-	// utils
-	IsOwnerOfWord(userId int, wordId int) (bool, error)
+	// training history
+	SaveAttempt(attempt TrainingAttempt) error
+	UpdateWithAttempts(word request.WordUpdate, attempts []TrainingAttempt) error // both or nothing is saved
+	FindAttemptsByWordId(wordId int, limit int, offset int) ([]TrainingAttempt, int64, error)
+	FindAttemptsByUserId(userId int) ([]TrainingAttempt, error)
 }
@@ -0,0 +1,155 @@
+package words
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+var errInvalidCursor = domainErrors.InvalidField("invalid_query", "cursor", "invalid cursor")
+
+const (
+	SortByCreatedAt = "created_at"
+	SortByWord      = "word"
+	SortByStatus    = "is_learned"
+
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	MaxSearchLength    = 100
+)
+
+var sortFields = map[string]bool{SortByCreatedAt: true, SortByWord: true, SortByStatus: true}
+
+// WordQuery describes one page of user's vocab. Pagination is keyset based:
+// words are ordered by the sort field and then by id, cursor keeps both values of the last word.
+type WordQuery struct {
+	UserId     int
+	Limit      int // 0 means all words, without pagination
+	Sort       string
+	Descending bool
+	After      *Cursor
+
+	// filters, nil means that filter is not applied
+	IsLearned       *bool
+	Cards           *bool
+	WordTranslation *bool
+	Constructor     *bool
+	WordAudio       *bool
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+}
+
+type Cursor struct {
+	Sort       string `json:"s"`
+	Descending bool   `json:"d"`
+	Value      string `json:"v"`
+	Id         int    `json:"id"`
+}
+
+func NewWordQuery(userId, limit int, sort, order string) (*WordQuery, error) {
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return nil, domainErrors.InvalidField("invalid_query", "limit", "limit must be between 1 and %d", MaxPageLimit)
+	}
+
+	if sort == "" {
+		sort = SortByCreatedAt
+	}
+	if !sortFields[sort] {
+		return nil, domainErrors.InvalidField("invalid_query", "sort", "cannot sort by: %s", sort)
+	}
+
+	var descending bool
+	switch order {
+	case "", "asc":
+	case "desc":
+		descending = true
+	default:
+		return nil, domainErrors.InvalidField("invalid_query", "order", "invalid order: %s", order)
+	}
+
+	return &WordQuery{
+		UserId:     userId,
+		Limit:      limit,
+		Sort:       sort,
+		Descending: descending,
+	}, nil
+}
+
+// CursorValue returns typed value of the sort field stored in the cursor
+func (q *WordQuery) CursorValue() (interface{}, error) {
+	if q.After == nil {
+		return nil, errors.New("cursor is not set")
+	}
+
+	switch q.Sort {
+	case SortByCreatedAt:
+		return time.Parse(time.RFC3339Nano, q.After.Value)
+	case SortByStatus:
+		return q.After.Value == "true", nil
+	default:
+		return q.After.Value, nil
+	}
+}
+
+// NextCursor builds cursor which points right after the word
+func (q *WordQuery) NextCursor(word Word) string {
+	cursor := Cursor{Sort: q.Sort, Descending: q.Descending, Id: word.Id}
+
+	switch q.Sort {
+	case SortByCreatedAt:
+		cursor.Value = word.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByStatus:
+		cursor.Value = fmt.Sprint(word.IsLearned)
+	default:
+		cursor.Value = word.Word
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SetCursor decodes cursor received from client, it must be created with the same sorting
+func (q *WordQuery) SetCursor(encoded string) error {
+	if encoded == "" {
+		return nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errInvalidCursor
+	}
+
+	var cursor Cursor
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return errInvalidCursor
+	}
+
+	if cursor.Sort != q.Sort || cursor.Descending != q.Descending {
+		return domainErrors.InvalidField("invalid_query", "cursor", "cursor does not match sorting")
+	}
+
+	q.After = &cursor
+
+	if _, err = q.CursorValue(); err != nil {
+		return errInvalidCursor
+	}
+
+	return nil
+}
+
+// VocabCount is the size of user's vocabulary
+type VocabCount struct {
+	UserId  int
+	Total   int64
+	Learned int64
+}
@@ -0,0 +1,107 @@
+package words
+
+import (
+	"math"
+	"time"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+/*
+	Spaced repetition based on SuperMemo SM-2 algorithm.
+
+	Boolean trainings (cards, word_translation, constructor, word_audio) are the first-pass
+	learning stage. Once all of them are passed the word becomes learned and goes to the review
+	queue, where every review is graded by user with quality from 0 to 5:
+		5 - perfect response
+		4 - correct response after a hesitation
+		3 - correct response recalled with serious difficulty
+		2 - incorrect response; where the correct one seemed easy to recall
+		1 - incorrect response; the correct one remembered
+		0 - complete blackout
+*/
+
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	MinQuality        = 0
+	MaxQuality        = 5
+
+	// quality below this value means that user failed to recall the word
+	passingQuality = 3
+
+	day = 24 * time.Hour
+)
+
+// Review updates schedule of the word according to the quality of the answer
+func (w *Word) Review(quality int, now time.Time) error {
+	if quality < MinQuality || quality > MaxQuality {
+		return domainErrors.InvalidField("invalid_review", "quality", "quality must be between %d and %d", MinQuality, MaxQuality)
+	}
+
+	if w.EaseFactor == 0 {
+		w.EaseFactor = DefaultEaseFactor
+	}
+
+	if quality < passingQuality {
+		// Lapse: start repetitions from the beginning, the ease factor is lowered below
+		w.Repetitions = 0
+		w.IntervalDays = 1
+	} else {
+		switch w.Repetitions {
+		case 0:
+			w.IntervalDays = 1
+		case 1:
+			w.IntervalDays = 6
+		default:
+			w.IntervalDays = int(math.Round(float64(w.IntervalDays) * w.EaseFactor))
+		}
+		w.Repetitions++
+	}
+
+	// As in SM-2 the ease factor is updated after every answer, failed ones included
+	q := float64(MaxQuality - quality)
+	w.EaseFactor = w.EaseFactor + (0.1 - q*(0.08+q*0.02))
+	if w.EaseFactor < MinEaseFactor {
+		w.EaseFactor = MinEaseFactor
+	}
+
+	w.DueAt = now.Add(time.Duration(w.IntervalDays) * day)
+
+	return nil
+}
+
+// ScheduleFirstReview puts the word that has just passed all trainings to the review queue.
+// Passing trainings counts as the first successful repetition, so next review is in one day.
+func (w *Word) ScheduleFirstReview(now time.Time) {
+	if w.EaseFactor == 0 {
+		w.EaseFactor = DefaultEaseFactor
+	}
+
+	w.Repetitions = 1
+	w.IntervalDays = 1
+	w.DueAt = now.Add(day)
+}
+
+// ScheduleImported marks the word that was already studied elsewhere (e.g. in Anki) as learned
+// and continues its schedule from the known interval instead of starting from scratch.
+func (w *Word) ScheduleImported(intervalDays int, now time.Time) {
+	w.IsLearned = true
+	w.Cards = true
+	w.WordTranslation = true
+	w.Constructor = true
+	w.WordAudio = true
+
+	if w.EaseFactor == 0 {
+		w.EaseFactor = DefaultEaseFactor
+	}
+
+	if intervalDays < 1 {
+		intervalDays = 1
+	}
+
+	// Two repetitions, so the next review grows the interval by the ease factor
+	w.Repetitions = 2
+	w.IntervalDays = intervalDays
+	w.DueAt = now.Add(time.Duration(intervalDays) * day)
+}
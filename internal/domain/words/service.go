@@ -3,7 +3,9 @@ package words
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	domainErrors "mono_pardo/internal/domain/errors"
 	"mono_pardo/pkg/data/request"
 	"mono_pardo/pkg/data/response"
 
@@ -29,10 +31,18 @@ func (s *serviceImpl) CreateWord(createWordRequest request.CreateWordRequest) er
 		createWordRequest.UserId,
 	)
 	if err != nil {
-		return fmt.Errorf("invalid word data: %w", err)
+		return err
+	}
+
+	existing, err := s.Repository.FindByWord(newWord.UserId, newWord.Word)
+	if err != nil {
+		return err
+	}
+	if existing.Id != 0 {
+		return ErrWordExists
 	}
 
-	if err = s.Repository.Save(*newWord); err != nil {
+	if _, err = s.Repository.Save(*newWord); err != nil {
 		return err
 	}
 
@@ -40,10 +50,8 @@ func (s *serviceImpl) CreateWord(createWordRequest request.CreateWordRequest) er
 }
 
 func (s *serviceImpl) DeleteWord(deleteWordRequest request.DeleteWordRequest) error {
-	if isOwner, err := s.Repository.IsOwnerOfWord(deleteWordRequest.UserId, deleteWordRequest.WordId); err != nil {
+	if _, err := s.findOwnWord(deleteWordRequest.UserId, deleteWordRequest.WordId); err != nil {
 		return err
-	} else if !isOwner {
-		return fmt.Errorf("you are not allowed to delete the word %d", deleteWordRequest.WordId)
 	}
 
 	if err := s.Repository.Delete(deleteWordRequest.WordId); err != nil {
@@ -58,69 +66,162 @@ func (s *serviceImpl) FindWord(findWordRequest request.FindWordRequest) (respons
 	if err != nil {
 		return response.VocabResponse{}, err
 	}
+	if word.Id == 0 {
+		return response.VocabResponse{}, ErrWordNotFound
+	}
 
-	return response.VocabResponse{
-		Id:              word.Id,
-		Word:            word.Word,
-		Definition:      word.Definition,
-		CreatedAt:       word.CreatedAt,
-		IsLearned:       word.IsLearned,
-		Cards:           word.Cards,
-		WordTranslation: word.WordTranslation,
-		Constructor:     word.Constructor,
-		WordAudio:       word.WordAudio,
-	}, nil
+	return toVocabResponse(word), nil
 }
 
-func (s *serviceImpl) GetWords(vocabRequest request.VocabRequest) ([]response.VocabResponse, error) {
-	var vocabResponse []response.VocabResponse
+func (s *serviceImpl) GetWords(vocabRequest request.VocabRequest) (response.VocabPageResponse, error) {
+	query, err := NewWordQuery(vocabRequest.UserId, vocabRequest.Limit, vocabRequest.Sort, vocabRequest.Order)
+	if err != nil {
+		return response.VocabPageResponse{}, err
+	}
+
+	if err = query.SetCursor(vocabRequest.Cursor); err != nil {
+		return response.VocabPageResponse{}, err
+	}
+
+	query.IsLearned = vocabRequest.IsLearned
+	query.Cards = vocabRequest.Cards
+	query.WordTranslation = vocabRequest.WordTranslation
+	query.Constructor = vocabRequest.Constructor
+	query.WordAudio = vocabRequest.WordAudio
+	query.CreatedAfter = vocabRequest.CreatedAfter
+	query.CreatedBefore = vocabRequest.CreatedBefore
+
+	words, err := s.Repository.FindPage(*query)
+	if err != nil {
+		return response.VocabPageResponse{}, err
+	}
+
+	vocabResponse := response.VocabPageResponse{Words: []response.VocabResponse{}}
+
+	if len(words) > query.Limit {
+		words = words[:query.Limit]
+		vocabResponse.HasMore = true
+		vocabResponse.NextCursor = query.NextCursor(words[len(words)-1])
+	}
+
+	for _, word := range words {
+		vocabResponse.Words = append(vocabResponse.Words, toVocabResponse(word))
+	}
+
+	return vocabResponse, nil
+}
+
+// GetAllWords is GET /vocab of clients which don't paginate yet, it applies the same sorting and filters
+func (s *serviceImpl) GetAllWords(vocabRequest request.VocabRequest) ([]response.VocabResponse, error) {
+	query, err := NewWordQuery(vocabRequest.UserId, 0, vocabRequest.Sort, vocabRequest.Order)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit = 0
+
+	query.IsLearned = vocabRequest.IsLearned
+	query.Cards = vocabRequest.Cards
+	query.WordTranslation = vocabRequest.WordTranslation
+	query.Constructor = vocabRequest.Constructor
+	query.WordAudio = vocabRequest.WordAudio
+	query.CreatedAfter = vocabRequest.CreatedAfter
+	query.CreatedBefore = vocabRequest.CreatedBefore
 
-	words, err := s.Repository.FindByUserId(vocabRequest.UserId)
+	words, err := s.Repository.FindPage(*query)
 	if err != nil {
 		return nil, err
 	}
 
+	vocabResponse := []response.VocabResponse{}
 	for _, word := range words {
-		vocabResponse = append(vocabResponse, response.VocabResponse{
-			Id:              word.Id,
-			Word:            word.Word,
-			Definition:      word.Definition,
-			CreatedAt:       word.CreatedAt,
-			IsLearned:       word.IsLearned,
-			Cards:           word.Cards,
-			WordTranslation: word.WordTranslation,
-			Constructor:     word.Constructor,
-			WordAudio:       word.WordAudio,
-		})
+		vocabResponse = append(vocabResponse, toVocabResponse(word))
 	}
 
 	return vocabResponse, nil
 }
 
-func (s *serviceImpl) UpdateWord(updateWordRequest request.UpdateWordRequest) error {
-	trainingFields := map[string]bool{"cards": true, "constructor": true, "word_translation": true, "word_audio": true}
+func (s *serviceImpl) SearchWords(searchRequest request.SearchWordsRequest) ([]response.VocabResponse, error) {
+	query := strings.TrimSpace(searchRequest.Query)
+	if query == "" {
+		return nil, domainErrors.InvalidField("invalid_search", "q", "search query is required")
+	}
+	if len([]rune(query)) > MaxSearchLength {
+		return nil, domainErrors.InvalidField("invalid_search", "q", "search query cannot be longer than %d characters", MaxSearchLength)
+	}
+
+	limit := searchRequest.Limit
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit < 0 || limit > MaxSearchLimit {
+		return nil, domainErrors.InvalidField("invalid_search", "limit", "limit must be between 1 and %d", MaxSearchLimit)
+	}
 
+	words, err := s.Repository.Search(searchRequest.UserId, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabResponse := []response.VocabResponse{}
+	for _, word := range words {
+		vocabResponse = append(vocabResponse, toVocabResponse(word))
+	}
+
+	return vocabResponse, nil
+}
+
+func (s *serviceImpl) UpdateWord(updateWordRequest request.UpdateWordRequest) error {
 	if err := s.validateWordUpdates(updateWordRequest.Words); err != nil {
 		return err
 	}
 
 	for _, word := range updateWordRequest.Words {
-		if isOwner, err := s.Repository.IsOwnerOfWord(updateWordRequest.UserId, word.WordId); err != nil {
+		current, err := s.findOwnWord(updateWordRequest.UserId, word.WordId)
+		if err != nil {
 			return err
-		} else if !isOwner {
-			return fmt.Errorf("you are not allowed to update the word: %d", word.WordId)
 		}
 
-		if err := s.Repository.Update(word); err != nil {
-			return err
+		// Current results of the trainings, by name of the field
+		trainingResults := map[string]bool{
+			"cards":            current.Cards,
+			"constructor":      current.Constructor,
+			"word_translation": current.WordTranslation,
+			"word_audio":       current.WordAudio,
 		}
 
+		isTrained := false
+		attempts := []TrainingAttempt{}
+		changes := []request.FieldUpdate{}
 		for _, update := range word.Updates {
-			if trainingFields[update.Field] {
-				if err := s.updateWordStatus(word.WordId); err != nil {
-					return err
-				}
-				break
+			previous, isTraining := trainingResults[update.Field]
+			if !isTraining {
+				changes = append(changes, update)
+				continue
+			}
+			isTrained = true
+
+			// Every training result goes to the history, value of the field is the result of exercise
+			isCorrect := update.Value.(bool)
+			attempt, err := NewTrainingAttempt(updateWordRequest.UserId, word.WordId, update.Field, isCorrect, update.ResponseTimeMs)
+			if err != nil {
+				return err
+			}
+			attempts = append(attempts, *attempt)
+
+			// The field itself is written only when the result is different
+			if isCorrect != previous {
+				changes = append(changes, update)
+			}
+		}
+		word.Updates = changes
+
+		if err = s.Repository.UpdateWithAttempts(word, attempts); err != nil {
+			return err
+		}
+
+		if isTrained {
+			if err := s.updateWordStatus(word.WordId); err != nil {
+				return err
 			}
 		}
 	}
@@ -128,6 +229,125 @@ func (s *serviceImpl) UpdateWord(updateWordRequest request.UpdateWordRequest) er
 	return nil
 }
 
+func (s *serviceImpl) ReviewWord(reviewWordRequest request.ReviewWordRequest) (response.VocabResponse, error) {
+	if reviewWordRequest.Quality == nil {
+		return response.VocabResponse{}, domainErrors.InvalidField("invalid_review", "quality", "quality is required field")
+	}
+
+	word, err := s.findOwnWord(reviewWordRequest.UserId, reviewWordRequest.WordId)
+	if err != nil {
+		return response.VocabResponse{}, err
+	}
+
+	if !word.IsLearned {
+		return response.VocabResponse{}, domainErrors.Conflict("word_not_learned", "word %d has not passed all trainings yet", word.Id)
+	}
+
+	if reviewWordRequest.ResponseTimeMs < 0 {
+		return response.VocabResponse{}, domainErrors.InvalidField("invalid_review", "response_time_ms", "response time cannot be negative")
+	}
+
+	if err = word.Review(*reviewWordRequest.Quality, time.Now().UTC()); err != nil {
+		return response.VocabResponse{}, err
+	}
+
+	isCorrect := *reviewWordRequest.Quality >= passingQuality
+	attempt, err := NewTrainingAttempt(reviewWordRequest.UserId, word.Id, ReviewExercise, isCorrect, reviewWordRequest.ResponseTimeMs)
+	if err != nil {
+		return response.VocabResponse{}, err
+	}
+
+	req := request.WordUpdate{WordId: word.Id, Updates: scheduleUpdates(word)}
+	if err = s.Repository.UpdateWithAttempts(req, []TrainingAttempt{*attempt}); err != nil {
+		return response.VocabResponse{}, err
+	}
+
+	return toVocabResponse(word), nil
+}
+
+func (s *serviceImpl) GetDueWords(dueWordsRequest request.DueWordsRequest) ([]response.VocabResponse, error) {
+	vocabResponse := []response.VocabResponse{}
+
+	words, err := s.Repository.FindDueByUserId(dueWordsRequest.UserId, time.Now().UTC(), dueWordsRequest.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, word := range words {
+		vocabResponse = append(vocabResponse, toVocabResponse(word))
+	}
+
+	return vocabResponse, nil
+}
+
+func (s *serviceImpl) ExportWords(exportRequest request.ExportWordsRequest) ([]response.VocabResponse, error) {
+	vocabResponse := []response.VocabResponse{}
+
+	var words []Word
+	var err error
+	if exportRequest.WordIds == nil {
+		words, err = s.Repository.FindByUserId(exportRequest.UserId)
+	} else {
+		words, err = s.Repository.FindByIds(exportRequest.UserId, exportRequest.WordIds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, word := range words {
+		vocabResponse = append(vocabResponse, toVocabResponse(word))
+	}
+
+	return vocabResponse, nil
+}
+
+func (s *serviceImpl) GetHistory(historyRequest request.HistoryRequest) (response.HistoryResponse, error) {
+	if _, err := s.findOwnWord(historyRequest.UserId, historyRequest.WordId); err != nil {
+		return response.HistoryResponse{}, err
+	}
+
+	attempts, total, err := s.Repository.FindAttemptsByWordId(historyRequest.WordId, historyRequest.Limit, historyRequest.Offset)
+	if err != nil {
+		return response.HistoryResponse{}, err
+	}
+
+	historyResponse := response.HistoryResponse{
+		Attempts: []response.AttemptResponse{},
+		Total:    total,
+		Limit:    historyRequest.Limit,
+		Offset:   historyRequest.Offset,
+	}
+
+	for _, attempt := range attempts {
+		historyResponse.Attempts = append(historyResponse.Attempts, response.AttemptResponse{
+			Id:             attempt.Id,
+			WordId:         attempt.WordId,
+			Exercise:       attempt.Exercise,
+			IsCorrect:      attempt.IsCorrect,
+			ResponseTimeMs: attempt.ResponseTimeMs,
+			CreatedAt:      attempt.CreatedAt,
+		})
+	}
+
+	return historyResponse, nil
+}
+
+// findOwnWord returns the word if it belongs to the user
+func (s *serviceImpl) findOwnWord(userId, wordId int) (Word, error) {
+	word, err := s.Repository.FindById(wordId)
+	if err != nil {
+		return Word{}, err
+	}
+	if word.Id == 0 {
+		return Word{}, ErrWordNotFound
+	}
+	if word.UserId != userId {
+		return Word{}, domainErrors.Forbidden("word_not_owned", "you are not allowed to access the word: %d", wordId)
+	}
+
+	return word, nil
+}
+
 func (s *serviceImpl) updateWordStatus(wordId int) error {
 	word, err := s.Repository.FindById(wordId)
 	if err != nil {
@@ -144,6 +364,12 @@ func (s *serviceImpl) updateWordStatus(wordId int) error {
 			},
 		}
 
+		// Word has just passed all trainings, so it goes to the review queue
+		if isLearned {
+			word.ScheduleFirstReview(time.Now().UTC())
+			req.Updates = append(req.Updates, scheduleUpdates(word)...)
+		}
+
 		if err = s.Repository.Update(req); err != nil {
 			return fmt.Errorf("failed to update 'is_learned' status for word ID %d", wordId)
 		}
@@ -164,18 +390,22 @@ func (s *serviceImpl) validateWordUpdates(updates []request.WordUpdate) error {
 
 	for _, word := range updates {
 		if len(word.Updates) == 0 {
-			return fmt.Errorf("no updates provided for word ID: %d", word.WordId)
+			return domainErrors.InvalidField("invalid_update", "updates", "no updates provided for word ID: %d", word.WordId)
 		}
 
 		for _, update := range word.Updates {
 			field := strings.TrimSpace(update.Field)
 			if field == "" {
-				return fmt.Errorf("empty field name not allowed")
+				return domainErrors.InvalidField("invalid_update", "field", "empty field name not allowed")
 			}
 
 			expectedType, validField := allowedFields[field]
 			if !validField {
-				return fmt.Errorf("invalid field name: %s", field)
+				return domainErrors.InvalidField("invalid_update", "field", "invalid field name: %s", field)
+			}
+
+			if update.ResponseTimeMs < 0 {
+				return domainErrors.InvalidField("invalid_update", "response_time_ms", "response time cannot be negative for field: %s", field)
 			}
 
 			// Type validation based on field
@@ -183,15 +413,15 @@ func (s *serviceImpl) validateWordUpdates(updates []request.WordUpdate) error {
 			case "string":
 				strValue, ok := update.Value.(string)
 				if !ok {
-					return fmt.Errorf("field %s requires string value", field)
+					return domainErrors.InvalidField("invalid_update", field, "field %s requires string value", field)
 				}
 				if strings.TrimSpace(strValue) == "" {
-					return fmt.Errorf("empty value not allowed for field: %s", field)
+					return domainErrors.InvalidField("invalid_update", field, "empty value not allowed for field: %s", field)
 				}
 			case "bool":
 				_, ok := update.Value.(bool)
 				if !ok {
-					return fmt.Errorf("field %s requires boolean value", field)
+					return domainErrors.InvalidField("invalid_update", field, "field %s requires boolean value", field)
 				}
 			}
 		}
@@ -199,3 +429,34 @@ func (s *serviceImpl) validateWordUpdates(updates []request.WordUpdate) error {
 
 	return nil
 }
+
+func scheduleUpdates(word Word) []request.FieldUpdate {
+	return []request.FieldUpdate{
+		{Field: "ease_factor", Value: word.EaseFactor},
+		{Field: "interval_days", Value: word.IntervalDays},
+		{Field: "repetitions", Value: word.Repetitions},
+		{Field: "due_at", Value: word.DueAt},
+	}
+}
+
+func toVocabResponse(word Word) response.VocabResponse {
+	return response.VocabResponse{
+		Id:              word.Id,
+		Word:            word.Word,
+		Definition:      word.Definition,
+		CreatedAt:       word.CreatedAt,
+		Tags:            word.Tags,
+		Notes:           word.Notes,
+		Context:         word.Context,
+		Source:          word.Source,
+		IsLearned:       word.IsLearned,
+		Cards:           word.Cards,
+		WordTranslation: word.WordTranslation,
+		Constructor:     word.Constructor,
+		WordAudio:       word.WordAudio,
+		EaseFactor:      word.EaseFactor,
+		IntervalDays:    word.IntervalDays,
+		Repetitions:     word.Repetitions,
+		DueAt:           word.DueAt,
+	}
+}
@@ -1,34 +1,51 @@
 package words
 
 import (
-	"errors"
 	"strings"
 	"time"
+	"unicode"
+
+	domainErrors "mono_pardo/internal/domain/errors"
+)
+
+var (
+	ErrWordNotFound = domainErrors.NotFound("word_not_found", "word is not found")
+	ErrWordExists   = domainErrors.Conflict("word_exists", "word is already in the vocabulary")
 )
 
 type Word struct {
 	Id         int       `gorm:"type:int;primary_key"`
 	Word       string    `gorm:"type:varchar;not null;uniqueIndex:idx_user_word,priority:2,column:word"`
 	Definition string    `gorm:"type:varchar;not null"`
-	UserId     int       `gorm:"not null;uniqueIndex:idx_user_word,priority:1,column:user_id"`
-	CreatedAt  time.Time `gorm:"default:now()"`
+	UserId     int       `gorm:"not null;uniqueIndex:idx_user_word,priority:1,column:user_id;index:idx_user_created,priority:1"`
+	CreatedAt  time.Time `gorm:"default:now();index:idx_user_created,priority:2"`
+	Tags       string    `gorm:"type:varchar;default:''"` // separated by spaces
+	Notes      string    `gorm:"type:text;default:''"`
+	Context    string    `gorm:"type:text;default:''"`    // sentence where user met the word
+	Source     string    `gorm:"type:varchar;default:''"` // e.g. title of the book
 
 	IsLearned       bool `gorm:"default:false"` // status of the word
 	Cards           bool `gorm:"default:false"`
 	WordTranslation bool `gorm:"default:false"`
 	Constructor     bool `gorm:"default:false"`
 	WordAudio       bool `gorm:"default:false"`
+
+	// spaced repetition schedule, see scheduler.go
+	EaseFactor   float64   `gorm:"default:2.5"`
+	IntervalDays int       `gorm:"default:0"`
+	Repetitions  int       `gorm:"default:0"`
+	DueAt        time.Time `gorm:"default:now();index"`
 }
 
 func NewWord(word, definition string, userId int) (*Word, error) {
 	if strings.TrimSpace(word) == "" {
-		return nil, errors.New("word is required field")
+		return nil, domainErrors.InvalidField("invalid_word", "word", "word is required field")
 	}
 	if strings.TrimSpace(definition) == "" {
-		return nil, errors.New("definition is required field")
+		return nil, domainErrors.InvalidField("invalid_word", "definition", "definition is required field")
 	}
 	if userId <= 0 {
-		return nil, errors.New("invalid user ID")
+		return nil, domainErrors.InvalidField("invalid_word", "user_id", "invalid user ID")
 	}
 
 	return &Word{
@@ -37,3 +54,21 @@ func NewWord(word, definition string, userId int) (*Word, error) {
 		UserId:     userId,
 	}, nil
 }
+
+// NormalizeTags splits raw tags by commas, semicolons and spaces and joins unique ones with space
+func NormalizeTags(raw string) string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+
+	seen := make(map[string]bool)
+	tags := make([]string, 0, len(fields))
+	for _, tag := range fields {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return strings.Join(tags, " ")
+}
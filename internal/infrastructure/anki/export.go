@@ -0,0 +1,249 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mono_pardo/pkg/data/response"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	CollectionFile = "collection.anki2"
+	MediaFile      = "media"
+
+	LearnedTag  = "learned"
+	LearningTag = "learning"
+
+	modelName = "Pardo Word"
+)
+
+// Note is a single word exported to Anki, Id is used to keep note guid stable between exports
+type Note struct {
+	Id         int
+	Word       string
+	Definition string
+	Tags       []string
+}
+
+// Export builds .apkg archive with one deck and one note type with Word/Definition fields
+func Export(deckName string, notes []Note) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "anki-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	collectionPath := filepath.Join(dir, CollectionFile)
+	if err = writeCollection(collectionPath, deckName, notes); err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read collection: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{CollectionFile, collection},
+		// Words don't have media yet, but Anki requires media manifest in the archive
+		{MediaFile, []byte("{}")},
+	}
+
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create %s in archive: %w", file.name, err)
+		}
+		if _, err = writer.Write(file.data); err != nil {
+			return nil, fmt.Errorf("cannot write %s to archive: %w", file.name, err)
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		return nil, fmt.Errorf("cannot close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCollection(path, deckName string, notes []Note) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("cannot open collection: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("cannot start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range schema {
+		if _, err = tx.Exec(statement); err != nil {
+			return fmt.Errorf("cannot create collection schema: %w", err)
+		}
+	}
+
+	now := time.Now()
+	nowMs := now.UnixMilli()
+
+	// Ids in Anki are timestamps in milliseconds, deck and model use the same base
+	deckId := nowMs
+	modelId := nowMs + 1
+
+	models, err := json.Marshal(map[string]model{
+		strconv.FormatInt(modelId, 10): newModel(modelId, deckId, now.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+
+	decks, err := json.Marshal(map[string]deck{
+		strconv.Itoa(defaultDeckId):   newDeck(defaultDeckId, "Default", now.Unix()),
+		strconv.FormatInt(deckId, 10): newDeck(deckId, deckName, now.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		now.Unix(), nowMs, nowMs, schemaVersion, collectionConf, string(models), string(decks), deckConf,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot write collection: %w", err)
+	}
+
+	for i, note := range notes {
+		noteId := nowMs + int64(i) + 2
+		fields := []string{escapeField(note.Word), escapeField(note.Definition)}
+
+		_, err = tx.Exec(
+			`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			noteId, guid(note.Id), modelId, now.Unix(), joinTags(note.Tags),
+			strings.Join(fields, fieldSeparator), stripHTML(fields[0]), checksum(fields[0]),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot write note: %w", err)
+		}
+
+		// New card, due is a position in the queue of new cards
+		_, err = tx.Exec(
+			`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			noteId, noteId, deckId, now.Unix(), i+1,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot write card: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func newModel(id, deckId, mod int64) model {
+	return model{
+		Id:    id,
+		Name:  modelName,
+		Mod:   mod,
+		Usn:   -1,
+		Sortf: 0,
+		Did:   deckId,
+		Tmpls: []template{
+			{
+				Name: "Card 1",
+				Ord:  0,
+				Qfmt: "{{Word}}",
+				Afmt: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Definition}}",
+			},
+		},
+		Flds: []field{
+			{Name: "Word", Ord: 0, Font: "Arial", Size: 20, Media: []any{}},
+			{Name: "Definition", Ord: 1, Font: "Arial", Size: 20, Media: []any{}},
+		},
+		Css:       cardCss,
+		LatexPre:  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		LatexPost: "\\end{document}",
+		Req:       [][]any{{0, "any", []int{0}}},
+		Tags:      []string{},
+		Vers:      []any{},
+	}
+}
+
+// guid is stable for the word, so Anki updates notes instead of duplicating them on the next import
+func guid(id int) string {
+	return fmt.Sprintf("pardo-%d", id)
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+func stripHTML(value string) string {
+	return html.UnescapeString(htmlTags.ReplaceAllString(value, ""))
+}
+
+// checksum is first 8 hex digits of sha1 of the first field without html, Anki uses it to find duplicates
+func checksum(value string) int64 {
+	sum := sha1.Sum([]byte(stripHTML(value)))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+func escapeField(value string) string {
+	return strings.ReplaceAll(html.EscapeString(value), "\n", "<br>")
+}
+
+// joinTags formats tags as Anki does: separated and surrounded by spaces
+func joinTags(tags []string) string {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+
+	if len(cleaned) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(cleaned, " ") + " "
+}
+
+// NotesFromVocab converts user's words to notes, learned status goes to tags
+func NotesFromVocab(words []response.VocabResponse) []Note {
+	notes := make([]Note, 0, len(words))
+
+	for _, word := range words {
+		tags := []string{LearningTag}
+		if word.IsLearned {
+			tags[0] = LearnedTag
+		}
+
+		notes = append(notes, Note{
+			Id:         word.Id,
+			Word:       word.Word,
+			Definition: word.Definition,
+			Tags:       append(tags, strings.Fields(word.Tags)...),
+		})
+	}
+
+	return notes
+}
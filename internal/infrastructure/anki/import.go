@@ -0,0 +1,338 @@
+package anki
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+const (
+	// LearnedIntervalDays is the interval after which Anki considers card mature
+	LearnedIntervalDays = 21
+
+	// newer collection format (Anki 2.1.50+) compressed with zstd
+	compressedCollectionFile = "collection.anki21b"
+	// collection of Anki 2.1, takes precedence over collection.anki2 when both are present
+	collection21File = "collection.anki21"
+
+	maxCollectionSize = 512 << 20
+	reviewCardType    = 2
+)
+
+// Mapping holds names of note fields, empty name means first field for word and second one for definition
+type Mapping struct {
+	Word       string
+	Definition string
+}
+
+type Result struct {
+	Rows  []request.ImportRow
+	Media []response.ImportMedia
+}
+
+var (
+	soundRef   = regexp.MustCompile(`\[sound:([^\]]+)\]`)
+	imageRef   = regexp.MustCompile(`(?i)<img[^>]+src=["']?([^"'>\s]+)`)
+	lineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>|</li>`)
+)
+
+// Import reads notes of .apkg or .colpkg archive, every note becomes one row, line of the row is the note number
+func Import(file io.ReaderAt, size int64, mapping Mapping) (Result, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return Result{}, errors.New("file is not a valid Anki package")
+	}
+
+	entries := make(map[string]*zip.File)
+	for _, entry := range archive.File {
+		entries[entry.Name] = entry
+	}
+
+	var collection *zip.File
+	switch {
+	case entries[compressedCollectionFile] != nil:
+		return Result{}, errors.New("collection format of Anki 2.1.50+ is not supported, export the deck with 'Support older Anki versions' option")
+	case entries[collection21File] != nil:
+		collection = entries[collection21File]
+	case entries[CollectionFile] != nil:
+		collection = entries[CollectionFile]
+	default:
+		return Result{}, errors.New("file is not a valid Anki package: collection is missing")
+	}
+
+	mediaFiles, err := readMedia(entries[MediaFile])
+	if err != nil {
+		return Result{}, err
+	}
+
+	dir, err := os.MkdirTemp("", "anki-import-*")
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	collectionPath := filepath.Join(dir, CollectionFile)
+	if err = extract(collection, collectionPath); err != nil {
+		return Result{}, err
+	}
+
+	rows, err := readNotes(collectionPath, mapping)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Rows: rows, Media: mediaUsage(mediaFiles, rows)}, nil
+}
+
+// readMedia returns file names from media manifest, which maps numbers of files in the archive to their names
+func readMedia(entry *zip.File) ([]string, error) {
+	files := []string{}
+	if entry == nil {
+		return files, nil
+	}
+
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, errors.New("cannot read media manifest")
+	}
+	defer reader.Close()
+
+	manifest := make(map[string]string)
+	if err = json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return nil, errors.New("invalid media manifest")
+	}
+
+	for _, name := range manifest {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func extract(entry *zip.File, path string) error {
+	if entry.UncompressedSize64 > maxCollectionSize {
+		return errors.New("collection is too large")
+	}
+
+	reader, err := entry.Open()
+	if err != nil {
+		return errors.New("cannot read collection")
+	}
+	defer reader.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create collection file: %w", err)
+	}
+	defer file.Close()
+
+	// Size in zip header can't be trusted, so the copy is limited too
+	written, err := io.Copy(file, io.LimitReader(reader, maxCollectionSize+1))
+	if err != nil {
+		return errors.New("cannot read collection")
+	}
+	if written > maxCollectionSize {
+		return errors.New("collection is too large")
+	}
+
+	return nil
+}
+
+type noteType struct {
+	Name string `json:"name"`
+	Flds []struct {
+		Name string `json:"name"`
+		Ord  int    `json:"ord"`
+	} `json:"flds"`
+}
+
+func readNotes(path string, mapping Mapping) ([]request.ImportRow, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, errors.New("cannot open collection")
+	}
+	defer db.Close()
+
+	var models string
+	if err = db.QueryRow("SELECT models FROM col").Scan(&models); err != nil {
+		return nil, errors.New("invalid collection: note types are missing")
+	}
+
+	noteTypes := make(map[int64]noteType)
+	if err = json.Unmarshal([]byte(models), &noteTypes); err != nil {
+		return nil, errors.New("invalid collection: cannot read note types")
+	}
+
+	wordFields, err := fieldIndexes(noteTypes, mapping.Word, 0)
+	if err != nil {
+		return nil, err
+	}
+	definitionFields, err := fieldIndexes(noteTypes, mapping.Definition, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// The longest interval of review cards of the note, cards in learning have negative interval in seconds
+	rows, err := db.Query(`
+		SELECT n.mid, n.tags, n.flds, COALESCE(MAX(CASE WHEN c.type = ? THEN c.ivl END), 0)
+		FROM notes n
+		LEFT JOIN cards c ON c.nid = n.id
+		GROUP BY n.id
+		ORDER BY n.id`, reviewCardType)
+	if err != nil {
+		return nil, errors.New("invalid collection: cannot read notes")
+	}
+	defer rows.Close()
+
+	importRows := []request.ImportRow{}
+	for line := 1; rows.Next(); line++ {
+		var modelId int64
+		var tags, fields string
+		var interval int
+
+		if err = rows.Scan(&modelId, &tags, &fields, &interval); err != nil {
+			return nil, errors.New("invalid collection: cannot read notes")
+		}
+
+		values := strings.Split(fields, fieldSeparator)
+		row := request.ImportRow{
+			Line:       line,
+			Word:       fieldValue(values, wordFields, modelId),
+			Definition: fieldValue(values, definitionFields, modelId),
+		}
+
+		row.Tags, row.IsLearned = noteTags(tags)
+		if interval >= LearnedIntervalDays {
+			row.IsLearned = true
+		}
+		if row.IsLearned {
+			row.IntervalDays = interval
+		}
+
+		importRows = append(importRows, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.New("invalid collection: cannot read notes")
+	}
+
+	return importRows, nil
+}
+
+// fieldIndexes finds position of the field in every note type, note types without the field are left out
+func fieldIndexes(noteTypes map[int64]noteType, name string, defaultIndex int) (map[int64]int, error) {
+	indexes := make(map[int64]int)
+
+	for id, model := range noteTypes {
+		if name == "" {
+			indexes[id] = defaultIndex
+			continue
+		}
+
+		for _, fld := range model.Flds {
+			if strings.EqualFold(fld.Name, name) {
+				indexes[id] = fld.Ord
+				break
+			}
+		}
+	}
+
+	if name != "" && len(indexes) == 0 && len(noteTypes) > 0 {
+		return nil, fmt.Errorf("field %q is not found in note types", name)
+	}
+
+	return indexes, nil
+}
+
+func fieldValue(values []string, indexes map[int64]int, modelId int64) string {
+	index, ok := indexes[modelId]
+	if !ok || index >= len(values) {
+		return ""
+	}
+	return values[index]
+}
+
+// noteTags drops status tags set by Export, learned tag means the word was learned before
+func noteTags(tags string) (string, bool) {
+	isLearned := false
+	kept := []string{}
+
+	for _, tag := range strings.Fields(tags) {
+		switch strings.ToLower(tag) {
+		case LearnedTag:
+			isLearned = true
+		case LearningTag:
+		default:
+			kept = append(kept, tag)
+		}
+	}
+
+	return strings.Join(kept, " "), isLearned
+}
+
+// mediaUsage reports media files with words which reference them and turns html of rows to plain text
+func mediaUsage(files []string, rows []request.ImportRow) []response.ImportMedia {
+	usage := make(map[string][]string)
+
+	for i := range rows {
+		word := toText(rows[i].Word)
+		for _, field := range []string{rows[i].Word, rows[i].Definition} {
+			for _, ref := range mediaRefs(field) {
+				usage[ref] = append(usage[ref], word)
+			}
+		}
+
+		rows[i].Word = word
+		rows[i].Definition = toText(rows[i].Definition)
+	}
+
+	media := make([]response.ImportMedia, 0, len(files))
+	for _, file := range files {
+		words := usage[file]
+		if words == nil {
+			words = []string{}
+		}
+		media = append(media, response.ImportMedia{File: file, Words: words})
+	}
+
+	return media
+}
+
+func mediaRefs(field string) []string {
+	refs := []string{}
+	for _, regex := range []*regexp.Regexp{soundRef, imageRef} {
+		for _, match := range regex.FindAllStringSubmatch(field, -1) {
+			refs = append(refs, html.UnescapeString(match[1]))
+		}
+	}
+	return refs
+}
+
+// toText converts html of the field to plain text, media references are removed
+func toText(field string) string {
+	text := soundRef.ReplaceAllString(field, "")
+	text = lineBreaks.ReplaceAllString(text, "\n")
+	text = stripHTML(text)
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
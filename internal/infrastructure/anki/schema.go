@@ -0,0 +1,201 @@
+package anki
+
+/*
+	Schema of legacy Anki collection (collection.anki2, schema version 11).
+	Stock Anki still imports it, and it doesn't require zstd compression
+	that is used by the newer collection.anki21b format.
+*/
+
+const (
+	schemaVersion  = 11
+	fieldSeparator = "\x1f"
+
+	defaultDeckId = 1
+	deckConfId    = 1
+)
+
+var schema = []string{
+	`CREATE TABLE col (
+		id integer PRIMARY KEY,
+		crt integer NOT NULL,
+		mod integer NOT NULL,
+		scm integer NOT NULL,
+		ver integer NOT NULL,
+		dty integer NOT NULL,
+		usn integer NOT NULL,
+		ls integer NOT NULL,
+		conf text NOT NULL,
+		models text NOT NULL,
+		decks text NOT NULL,
+		dconf text NOT NULL,
+		tags text NOT NULL
+	)`,
+	`CREATE TABLE notes (
+		id integer PRIMARY KEY,
+		guid text NOT NULL,
+		mid integer NOT NULL,
+		mod integer NOT NULL,
+		usn integer NOT NULL,
+		tags text NOT NULL,
+		flds text NOT NULL,
+		sfld integer NOT NULL,
+		csum integer NOT NULL,
+		flags integer NOT NULL,
+		data text NOT NULL
+	)`,
+	`CREATE TABLE cards (
+		id integer PRIMARY KEY,
+		nid integer NOT NULL,
+		did integer NOT NULL,
+		ord integer NOT NULL,
+		mod integer NOT NULL,
+		usn integer NOT NULL,
+		type integer NOT NULL,
+		queue integer NOT NULL,
+		due integer NOT NULL,
+		ivl integer NOT NULL,
+		factor integer NOT NULL,
+		reps integer NOT NULL,
+		lapses integer NOT NULL,
+		left integer NOT NULL,
+		odue integer NOT NULL,
+		odid integer NOT NULL,
+		flags integer NOT NULL,
+		data text NOT NULL
+	)`,
+	`CREATE TABLE revlog (
+		id integer PRIMARY KEY,
+		cid integer NOT NULL,
+		usn integer NOT NULL,
+		ease integer NOT NULL,
+		ivl integer NOT NULL,
+		lastIvl integer NOT NULL,
+		factor integer NOT NULL,
+		time integer NOT NULL,
+		type integer NOT NULL
+	)`,
+	`CREATE TABLE graves (
+		usn integer NOT NULL,
+		oid integer NOT NULL,
+		type integer NOT NULL
+	)`,
+	`CREATE INDEX ix_notes_usn ON notes (usn)`,
+	`CREATE INDEX ix_cards_usn ON cards (usn)`,
+	`CREATE INDEX ix_revlog_usn ON revlog (usn)`,
+	`CREATE INDEX ix_cards_nid ON cards (nid)`,
+	`CREATE INDEX ix_cards_sched ON cards (did, queue, due)`,
+	`CREATE INDEX ix_revlog_cid ON revlog (cid)`,
+	`CREATE INDEX ix_notes_csum ON notes (csum)`,
+}
+
+type model struct {
+	Id        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Type      int        `json:"type"`
+	Mod       int64      `json:"mod"`
+	Usn       int        `json:"usn"`
+	Sortf     int        `json:"sortf"`
+	Did       int64      `json:"did"`
+	Tmpls     []template `json:"tmpls"`
+	Flds      []field    `json:"flds"`
+	Css       string     `json:"css"`
+	LatexPre  string     `json:"latexPre"`
+	LatexPost string     `json:"latexPost"`
+	Req       [][]any    `json:"req"`
+	Tags      []string   `json:"tags"`
+	Vers      []any      `json:"vers"`
+}
+
+type template struct {
+	Name  string `json:"name"`
+	Ord   int    `json:"ord"`
+	Qfmt  string `json:"qfmt"`
+	Afmt  string `json:"afmt"`
+	Did   *int64 `json:"did"`
+	Bqfmt string `json:"bqfmt"`
+	Bafmt string `json:"bafmt"`
+}
+
+type field struct {
+	Name   string `json:"name"`
+	Ord    int    `json:"ord"`
+	Sticky bool   `json:"sticky"`
+	Rtl    bool   `json:"rtl"`
+	Font   string `json:"font"`
+	Size   int    `json:"size"`
+	Media  []any  `json:"media"`
+}
+
+type deck struct {
+	Id               int64  `json:"id"`
+	Name             string `json:"name"`
+	Mod              int64  `json:"mod"`
+	Usn              int    `json:"usn"`
+	LrnToday         []int  `json:"lrnToday"`
+	RevToday         []int  `json:"revToday"`
+	NewToday         []int  `json:"newToday"`
+	TimeToday        []int  `json:"timeToday"`
+	Collapsed        bool   `json:"collapsed"`
+	BrowserCollapsed bool   `json:"browserCollapsed"`
+	Desc             string `json:"desc"`
+	Dyn              int    `json:"dyn"`
+	Conf             int64  `json:"conf"`
+	ExtendNew        int    `json:"extendNew"`
+	ExtendRev        int    `json:"extendRev"`
+}
+
+func newDeck(id int64, name string, mod int64) deck {
+	return deck{
+		Id:        id,
+		Name:      name,
+		Mod:       mod,
+		Usn:       -1,
+		LrnToday:  []int{0, 0},
+		RevToday:  []int{0, 0},
+		NewToday:  []int{0, 0},
+		TimeToday: []int{0, 0},
+		Conf:      deckConfId,
+		ExtendNew: 10,
+		ExtendRev: 50,
+	}
+}
+
+const collectionConf = `{
+	"activeDecks": [1],
+	"addToCur": true,
+	"collapseTime": 1200,
+	"curDeck": 1,
+	"dueCounts": true,
+	"estTimes": true,
+	"newBury": true,
+	"newSpread": 0,
+	"nextPos": 1,
+	"sortBackwards": false,
+	"sortType": "noteFld",
+	"timeLim": 0
+}`
+
+const deckConf = `{
+	"1": {
+		"id": 1,
+		"name": "Default",
+		"mod": 0,
+		"usn": 0,
+		"maxTaken": 60,
+		"autoplay": true,
+		"timer": 0,
+		"replayq": true,
+		"dyn": false,
+		"new": {"bury": true, "delays": [1, 10], "initialFactor": 2500, "ints": [1, 4, 7], "order": 1, "perDay": 20, "separate": true},
+		"lapse": {"delays": [10], "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+		"rev": {"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100}
+	}
+}`
+
+const cardCss = `.card {
+	font-family: arial;
+	font-size: 20px;
+	text-align: center;
+	color: black;
+	background-color: white;
+}`
@@ -0,0 +1,181 @@
+package delimited
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"mono_pardo/pkg/data/request"
+)
+
+// Mapping points to the columns by header name or by zero-based index.
+// Empty Tags or Notes means that the file doesn't have such column.
+type Mapping struct {
+	Word       string
+	Definition string
+	Tags       string
+	Notes      string
+}
+
+type Options struct {
+	Delimiter rune
+	HasHeader bool
+	Mapping   Mapping
+}
+
+// ParseDelimiter converts delimiter setting from request, comma is default one
+func ParseDelimiter(value string) (rune, error) {
+	switch value {
+	case "", ",", "comma":
+		return ',', nil
+	case "\t", "tab", "tsv":
+		return '\t', nil
+	case ";", "semicolon":
+		return ';', nil
+	case "|", "pipe":
+		return '|', nil
+	default:
+		return 0, fmt.Errorf("unsupported delimiter: %s", value)
+	}
+}
+
+// Parse reads CSV/TSV file into import rows. Rows that don't have mapped columns are returned
+// with empty values, so they are reported by the same validation as the rest of the words.
+func Parse(reader io.Reader, options Options) ([]request.ImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = options.Delimiter
+	csvReader.FieldsPerRecord = -1
+	// TSV files exported from spreadsheets don't quote fields and may start with empty column
+	isTSV := options.Delimiter == '\t'
+	csvReader.TrimLeadingSpace = !isTSV
+	csvReader.LazyQuotes = isTSV
+
+	var header []string
+	if options.HasHeader {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return []request.ImportRow{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read header: %w", err)
+		}
+		header = record
+	}
+
+	mapping := options.Mapping
+	if mapping.Word == "" && mapping.Definition == "" {
+		mapping = defaultMapping(header)
+	}
+
+	wordColumn, err := columnIndex(mapping.Word, header)
+	if err != nil {
+		return nil, err
+	}
+	definitionColumn, err := columnIndex(mapping.Definition, header)
+	if err != nil {
+		return nil, err
+	}
+	tagsColumn, err := optionalColumnIndex(mapping.Tags, header)
+	if err != nil {
+		return nil, err
+	}
+	notesColumn, err := optionalColumnIndex(mapping.Notes, header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []request.ImportRow{}
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file: %w", err)
+		}
+
+		line, _ := csvReader.FieldPos(0)
+
+		if isEmpty(record) {
+			continue
+		}
+
+		rows = append(rows, request.ImportRow{
+			Line:       line,
+			Word:       field(record, wordColumn),
+			Definition: field(record, definitionColumn),
+			Tags:       field(record, tagsColumn),
+			Notes:      field(record, notesColumn),
+		})
+	}
+
+	return rows, nil
+}
+
+// defaultMapping uses columns named as word fields, otherwise first two columns
+func defaultMapping(header []string) Mapping {
+	mapping := Mapping{Word: "0", Definition: "1"}
+
+	names := map[string]*string{
+		"word":       &mapping.Word,
+		"definition": &mapping.Definition,
+		"tags":       &mapping.Tags,
+		"notes":      &mapping.Notes,
+	}
+
+	for _, name := range header {
+		if target, ok := names[strings.ToLower(strings.TrimSpace(name))]; ok {
+			*target = name
+		}
+	}
+
+	return mapping
+}
+
+func columnIndex(column string, header []string) (int, error) {
+	if column == "" {
+		return 0, errors.New("word and definition columns are required")
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+			return i, nil
+		}
+	}
+
+	index, err := strconv.Atoi(column)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("unknown column: %s", column)
+	}
+
+	return index, nil
+}
+
+func optionalColumnIndex(column string, header []string) (int, error) {
+	if column == "" {
+		return -1, nil
+	}
+
+	return columnIndex(column, header)
+}
+
+func field(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[index])
+}
+
+func isEmpty(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,216 @@
+package kindle
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+
+	_ "modernc.org/sqlite"
+)
+
+/*
+	Import of Kindle Vocabulary Builder database (vocab.db).
+	Every looked up word is stored in WORDS, every lookup of it in LOOKUPS together with
+	the sentence (usage) and the book (BOOK_INFO). Kindle doesn't keep definitions, so the
+	usage sentence is used as definition until user changes it. It is also kept as context
+	of the word, and title of the book as its source.
+*/
+
+const (
+	// category of the word which user marked as mastered in Vocabulary Builder
+	masteredCategory = 100
+
+	maxDatabaseSize = 256 << 20
+)
+
+// Options filter lookups, empty Book and nil dates mean no filter
+type Options struct {
+	Book string // id, ASIN or title of the book
+	From *time.Time
+	To   *time.Time
+}
+
+type Result struct {
+	Rows  []request.ImportRow
+	Books []response.KindleBook // all books of the database, to choose from
+}
+
+type lookup struct {
+	word       string
+	stem       string
+	category   int
+	usage      string
+	bookId     string
+	bookAsin   string
+	bookTitle  string
+	authors    string
+	lookedUpAt time.Time
+}
+
+// Import reads vocab.db, words looked up several times are imported once with the latest lookup
+func Import(file io.Reader, options Options) (Result, error) {
+	dir, err := os.MkdirTemp("", "kindle-import-*")
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "vocab.db")
+	if err = save(file, path); err != nil {
+		return Result{}, err
+	}
+
+	lookups, err := readLookups(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Rows: toRows(lookups, options), Books: books(lookups)}, nil
+}
+
+func save(file io.Reader, path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create database file: %w", err)
+	}
+	defer out.Close()
+
+	written, err := io.Copy(out, io.LimitReader(file, maxDatabaseSize+1))
+	if err != nil {
+		return errors.New("cannot read file")
+	}
+	if written > maxDatabaseSize {
+		return errors.New("file is too large")
+	}
+
+	return nil
+}
+
+func readLookups(path string) ([]lookup, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, errors.New("file is not a valid Kindle vocabulary database")
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT w.word, COALESCE(w.stem, ''), COALESCE(w.category, 0), COALESCE(l.usage, ''),
+			COALESCE(b.id, ''), COALESCE(b.asin, ''), COALESCE(b.title, ''), COALESCE(b.authors, ''),
+			COALESCE(l.timestamp, 0)
+		FROM LOOKUPS l
+		JOIN WORDS w ON w.id = l.word_key
+		LEFT JOIN BOOK_INFO b ON b.id = l.book_key
+		ORDER BY l.timestamp`)
+	if err != nil {
+		return nil, errors.New("file is not a valid Kindle vocabulary database")
+	}
+	defer rows.Close()
+
+	lookups := []lookup{}
+	for rows.Next() {
+		var l lookup
+		var timestamp int64
+
+		err = rows.Scan(&l.word, &l.stem, &l.category, &l.usage, &l.bookId, &l.bookAsin, &l.bookTitle, &l.authors, &timestamp)
+		if err != nil {
+			return nil, errors.New("file is not a valid Kindle vocabulary database")
+		}
+
+		// Kindle stores time of the lookup in milliseconds
+		l.lookedUpAt = time.UnixMilli(timestamp).UTC()
+		lookups = append(lookups, l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.New("file is not a valid Kindle vocabulary database")
+	}
+
+	return lookups, nil
+}
+
+func (o Options) matches(l lookup) bool {
+	if o.Book != "" && o.Book != l.bookId && o.Book != l.bookAsin && !strings.EqualFold(o.Book, l.bookTitle) {
+		return false
+	}
+	if o.From != nil && l.lookedUpAt.Before(*o.From) {
+		return false
+	}
+	if o.To != nil && l.lookedUpAt.After(*o.To) {
+		return false
+	}
+	return true
+}
+
+// toRows keeps the latest lookup of every word, lookups are ordered by time
+func toRows(lookups []lookup, options Options) []request.ImportRow {
+	rows := []request.ImportRow{}
+	index := make(map[string]int)
+
+	for _, l := range lookups {
+		if !options.matches(l) {
+			continue
+		}
+
+		// stem is the dictionary form of the word, e.g. "run" for "running"
+		word := strings.TrimSpace(l.stem)
+		if word == "" {
+			word = strings.TrimSpace(l.word)
+		}
+
+		usage := strings.Join(strings.Fields(l.usage), " ")
+		row := request.ImportRow{
+			Word:       word,
+			Definition: usage,
+			Context:    usage,
+			Source:     l.bookTitle,
+			IsLearned:  l.category == masteredCategory,
+		}
+
+		key := strings.ToLower(word)
+		if i, ok := index[key]; ok {
+			row.Line = rows[i].Line
+			rows[i] = row
+			continue
+		}
+
+		row.Line = len(rows) + 1
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func books(lookups []lookup) []response.KindleBook {
+	byId := make(map[string]*response.KindleBook)
+
+	for _, l := range lookups {
+		if l.bookId == "" {
+			continue
+		}
+
+		book, ok := byId[l.bookId]
+		if !ok {
+			book = &response.KindleBook{Id: l.bookId, Asin: l.bookAsin, Title: l.bookTitle, Authors: l.authors}
+			byId[l.bookId] = book
+		}
+		book.Lookups++
+	}
+
+	result := make([]response.KindleBook, 0, len(byId))
+	for _, book := range byId {
+		result = append(result, *book)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Title < result[j].Title })
+
+	return result
+}
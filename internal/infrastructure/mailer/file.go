@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+)
+
+// fileMailerImpl is used in development and tests: every email is written to a separate .eml file
+// in Dir, or to the log if Dir is empty
+type fileMailerImpl struct {
+	Dir   string
+	From  string
+	count atomic.Int64
+}
+
+func NewFileMailerImpl(dir, from string) domain.Mailer {
+	return &fileMailerImpl{Dir: dir, From: from}
+}
+
+func (m *fileMailerImpl) Send(to, subject, body string) error {
+	message := buildMessage(m.From, to, subject, body)
+
+	if m.Dir == "" {
+		log.Printf("email to %s:\n%s\n", to, message)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create mail dir: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%04d.eml", time.Now().UnixNano(), m.count.Add(1))
+	if err := os.WriteFile(filepath.Join(m.Dir, name), message, 0o644); err != nil {
+		return fmt.Errorf("cannot write email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+	"mono_pardo/pkg/config"
+)
+
+type smtpMailerImpl struct {
+	Addr string
+	Auth smtp.Auth
+	From string
+}
+
+func NewSMTPMailerImpl(config *config.Config) domain.Mailer {
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return &smtpMailerImpl{
+		Addr: net.JoinHostPort(config.SMTPHost, config.SMTPPort),
+		Auth: auth,
+		From: config.MailFrom,
+	}
+}
+
+func (m *smtpMailerImpl) Send(to, subject, body string) error {
+	if err := smtp.SendMail(m.Addr, m.Auth, m.From, []string{to}, buildMessage(m.From, to, subject, body)); err != nil {
+		return fmt.Errorf("cannot send email: %w", err)
+	}
+
+	return nil
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	headers := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + sanitizeHeader(subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.ReplaceAll(body, "\n", "\r\n"))
+}
+
+// sanitizeHeader prevents injection of additional headers
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,248 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+	"mono_pardo/pkg/config"
+
+	"github.com/golang-jwt/jwt"
+)
+
+/*
+	OpenID Connect client: discovery, authorization code exchange and ID token verification. Endpoints
+	are discovered once, signing keys are fetched again when the provider starts using an unknown key.
+*/
+
+const (
+	discoveryPath       = "/.well-known/openid-configuration"
+	keysRefreshInterval = time.Minute // unknown key id can't make us fetch keys on every request
+	maxResponseSize     = 1 << 20
+)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type providerImpl struct {
+	Config config.OIDCProviderConfig
+	Client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          keySet
+	keysFetchedAt time.Time
+}
+
+func NewProviderImpl(providerConfig config.OIDCProviderConfig, client *http.Client) domain.OIDCProvider {
+	return &providerImpl{Config: providerConfig, Client: client}
+}
+
+func (p *providerImpl) AuthorizationURL(state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	authorizationURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	scopes := p.Config.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientId)
+	query.Set("redirect_uri", p.Config.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	return authorizationURL.String(), nil
+}
+
+func (p *providerImpl) Exchange(code, codeVerifier string) (domain.OIDCClaims, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return domain.OIDCClaims{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.Config.ClientId)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.OIDCClaims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.Config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.Config.ClientId), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	var token tokenResponse
+	status, err := p.do(req, &token)
+	if err != nil {
+		return domain.OIDCClaims{}, err
+	}
+	if status != http.StatusOK || token.Error != "" {
+		return domain.OIDCClaims{}, fmt.Errorf("code exchange failed: %d %s %s", status, token.Error, token.ErrorDescription)
+	}
+	if token.IdToken == "" {
+		return domain.OIDCClaims{}, errors.New("provider didn't return id token")
+	}
+
+	return p.verifyIdToken(token.IdToken, discovery)
+}
+
+func (p *providerImpl) verifyIdToken(idToken string, discovery discoveryDocument) (domain.OIDCClaims, error) {
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg()}}
+
+	tok, err := parser.Parse(idToken, func(jwtToken *jwt.Token) (interface{}, error) {
+		kid, _ := jwtToken.Header["kid"].(string)
+		return p.publicKey(kid, discovery)
+	})
+	if err != nil {
+		return domain.OIDCClaims{}, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	claims, ok := tok.Claims.(jwt.MapClaims)
+	if !ok || !tok.Valid {
+		return domain.OIDCClaims{}, errors.New("invalid id token claims")
+	}
+
+	if claims["iss"] != discovery.Issuer {
+		return domain.OIDCClaims{}, fmt.Errorf("id token is issued by another issuer: %v", claims["iss"])
+	}
+	if !hasAudience(claims, p.Config.ClientId) {
+		return domain.OIDCClaims{}, errors.New("id token is issued for another client")
+	}
+	if _, ok = claims["exp"]; !ok {
+		return domain.OIDCClaims{}, errors.New("id token has no expiration")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return domain.OIDCClaims{}, errors.New("id token has no subject")
+	}
+
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	nonce, _ := claims["nonce"].(string)
+
+	return domain.OIDCClaims{
+		Subject:       subject,
+		Email:         strings.TrimSpace(email),
+		EmailVerified: isTrue(claims["email_verified"]),
+		Name:          name,
+		Nonce:         nonce,
+	}, nil
+}
+
+func (p *providerImpl) discover() (discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return *p.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.Config.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return discoveryDocument{}, err
+	}
+
+	var discovery discoveryDocument
+	status, err := p.do(req, &discovery)
+	if err != nil {
+		return discoveryDocument{}, err
+	}
+	if status != http.StatusOK {
+		return discoveryDocument{}, fmt.Errorf("discovery failed: %d", status)
+	}
+
+	// Prevents a compromised discovery document from pointing to another issuer
+	if discovery.Issuer != p.Config.Issuer {
+		return discoveryDocument{}, fmt.Errorf("discovery returned another issuer: %s", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return discoveryDocument{}, errors.New("discovery document is incomplete")
+	}
+
+	p.discovery = &discovery
+	return discovery, nil
+}
+
+func (p *providerImpl) do(req *http.Request, target interface{}) (int, error) {
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request to identity provider failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(target); err != nil && res.StatusCode == http.StatusOK {
+		return res.StatusCode, fmt.Errorf("invalid response of identity provider: %w", err)
+	}
+
+	return res.StatusCode, nil
+}
+
+func hasAudience(claims jwt.MapClaims, clientId string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == clientId
+	case []interface{}:
+		found := false
+		for _, value := range aud {
+			if value == clientId {
+				found = true
+			}
+		}
+		// Token for several audiences must be issued to us
+		return found && (len(aud) == 1 || claims["azp"] == clientId)
+	}
+
+	return false
+}
+
+// isTrue handles providers which send boolean claims as strings
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+
+	return false
+}
@@ -1,6 +1,17 @@
 package sets
 
-import domain "mono_pardo/internal/domain/sets"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mono_pardo/internal/domain/sets"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
 
 /*
 	Use Mongo DB because of convinient $push $pull system
@@ -14,8 +25,198 @@ import domain "mono_pardo/internal/domain/sets"
 	The same in case of validation of foreign keys. FE just skips missing words
 */
 
-type repositoryImpl struct{}
+const (
+	collectionName = "sets"
+	queryTimeout   = 5 * time.Second
+)
+
+type setDocument struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	UserId      int                `bson:"user_id"`
+	Words       []int              `bson:"words"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+type repositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoRepositoryImpl(Db *mongo.Database) domain.Repository {
+	return &repositoryImpl{Collection: Db.Collection(collectionName)}
+}
+
+func (r *repositoryImpl) Save(set domain.WordSet) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := r.Collection.InsertOne(ctx, toDocument(set))
+	if err != nil {
+		return "", errors.New("cannot save set")
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", errors.New("cannot save set")
+	}
+
+	return id.Hex(), nil
+}
+
+func (r *repositoryImpl) Update(set domain.WordSet) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(set.Id)
+	if err != nil {
+		return fmt.Errorf("invalid set id: %s", set.Id)
+	}
+
+	update := bson.M{"$set": bson.M{"name": set.Name, "description": set.Description}}
+
+	if _, err = r.Collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("cannot update set: %s", set.Id)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) Delete(setId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(setId)
+	if err != nil {
+		return fmt.Errorf("invalid set id: %s", setId)
+	}
+
+	if _, err = r.Collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("cannot delete set: %s", setId)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) DeleteByUserId(userId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	if _, err := r.Collection.DeleteMany(ctx, bson.M{"user_id": userId}); err != nil {
+		return fmt.Errorf("cannot delete sets of the user: %d", userId)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) FindByUserId(userId int) ([]domain.WordSet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		return nil, errors.New("sets is not found")
+	}
+
+	var docs []setDocument
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, errors.New("sets is not found")
+	}
+
+	// Should return empty slice in case if user exists but have not created any sets yet.
+	sets := make([]domain.WordSet, 0, len(docs))
+	for _, doc := range docs {
+		sets = append(sets, toDomain(doc))
+	}
+
+	return sets, nil
+}
+
+func (r *repositoryImpl) FindById(setId string) (domain.WordSet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	// Malformed id can't belong to any set
+	id, err := primitive.ObjectIDFromHex(setId)
+	if err != nil {
+		return domain.WordSet{}, domain.ErrSetNotFound
+	}
+
+	var doc setDocument
+	err = r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.WordSet{}, domain.ErrSetNotFound
+	}
+	if err != nil {
+		return domain.WordSet{}, fmt.Errorf("cannot find set with id: %s", setId)
+	}
+
+	return toDomain(doc), nil
+}
+
+func (r *repositoryImpl) AddWord(setId string, wordId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(setId)
+	if err != nil {
+		return fmt.Errorf("invalid set id: %s", setId)
+	}
+
+	// Filter on "words" so the same word is never pushed twice
+	filter := bson.M{"_id": id, "words": bson.M{"$ne": wordId}}
+	update := bson.M{"$push": bson.M{"words": wordId}}
+
+	if _, err = r.Collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("cannot add word %d to set: %s", wordId, setId)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) RemoveWord(setId string, wordId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(setId)
+	if err != nil {
+		return fmt.Errorf("invalid set id: %s", setId)
+	}
+
+	update := bson.M{"$pull": bson.M{"words": wordId}}
+
+	if _, err = r.Collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("cannot remove word %d from set: %s", wordId, setId)
+	}
+
+	return nil
+}
+
+func toDocument(set domain.WordSet) setDocument {
+	id, _ := primitive.ObjectIDFromHex(set.Id)
+
+	words := set.Words
+	if words == nil {
+		words = []int{}
+	}
+
+	return setDocument{
+		Id:          id,
+		Name:        set.Name,
+		Description: set.Description,
+		UserId:      set.UserId,
+		Words:       words,
+		CreatedAt:   set.CreatedAt,
+	}
+}
 
-func NewMongoRepositoryImpl() domain.Repository {
-	return &repositoryImpl{}
+func toDomain(doc setDocument) domain.WordSet {
+	return domain.WordSet{
+		Id:          doc.Id.Hex(),
+		Name:        doc.Name,
+		Description: doc.Description,
+		UserId:      doc.UserId,
+		Words:       doc.Words,
+		CreatedAt:   doc.CreatedAt,
+	}
 }
@@ -0,0 +1,73 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+type accessTokenRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresAccessTokenRepositoryImpl(Db *gorm.DB) domain.AccessTokenRepository {
+	return &accessTokenRepositoryImpl{Db: Db}
+}
+
+func (r *accessTokenRepositoryImpl) Save(token domain.AccessToken) error {
+	if err := r.Db.Create(&token).Error; err != nil {
+		return errors.New("cannot save access token")
+	}
+
+	return nil
+}
+
+func (r *accessTokenRepositoryImpl) FindByHash(tokenHash string) (domain.AccessToken, error) {
+	var token domain.AccessToken
+
+	if err := r.Db.Where("token_hash = ?", tokenHash).Limit(1).Find(&token).Error; err != nil {
+		return token, errors.New("cannot find access token")
+	}
+
+	return token, nil
+}
+
+func (r *accessTokenRepositoryImpl) FindActiveByUserId(userId int, now time.Time) ([]domain.AccessToken, error) {
+	tokens := []domain.AccessToken{}
+
+	err := r.Db.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, now).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot find access tokens of the user: %d", userId)
+	}
+
+	return tokens, nil
+}
+
+func (r *accessTokenRepositoryImpl) MarkUsed(tokenId int, at time.Time) error {
+	err := r.Db.Model(&domain.AccessToken{}).
+		Where("id = ?", tokenId).
+		Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("cannot update access token: %d", tokenId)
+	}
+
+	return nil
+}
+
+func (r *accessTokenRepositoryImpl) Revoke(userId, tokenId int, at time.Time) (bool, error) {
+	result := r.Db.Model(&domain.AccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenId, userId).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot revoke access token: %d", tokenId)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,61 @@
+package users
+
+import (
+	"errors"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+// counterStoreImpl keeps counters in Postgres, so that they are shared by all instances of the app
+type counterStoreImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresCounterStoreImpl(Db *gorm.DB) domain.CounterStore {
+	return &counterStoreImpl{Db: Db}
+}
+
+func (r *counterStoreImpl) Increment(key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now().UTC()
+
+	// Single statement, so that concurrent requests don't lose increments
+	var counter domain.Counter
+	err := r.Db.Raw(`
+		INSERT INTO counters (key, count, reset_at) VALUES (@key, 1, @reset_at)
+		ON CONFLICT (key) DO UPDATE SET
+			count = CASE WHEN counters.reset_at <= @now THEN 1 ELSE counters.count + 1 END,
+			reset_at = CASE WHEN counters.reset_at <= @now THEN EXCLUDED.reset_at ELSE counters.reset_at END
+		RETURNING key, count, reset_at`,
+		map[string]interface{}{"key": key, "now": now, "reset_at": now.Add(window)},
+	).Scan(&counter).Error
+	if err != nil {
+		return 0, time.Time{}, errors.New("cannot increment counter")
+	}
+
+	return counter.Count, counter.ResetAt, nil
+}
+
+func (r *counterStoreImpl) Get(key string) (int, time.Time, error) {
+	var counter domain.Counter
+
+	err := r.Db.Where("key = ? AND reset_at > ?", key, time.Now().UTC()).Limit(1).Find(&counter).Error
+	if err != nil {
+		return 0, time.Time{}, errors.New("cannot get counter")
+	}
+
+	return counter.Count, counter.ResetAt, nil
+}
+
+func (r *counterStoreImpl) Reset(key string) error {
+	if err := r.Db.Where("key = ?", key).Delete(&domain.Counter{}).Error; err != nil {
+		return errors.New("cannot reset counter")
+	}
+
+	// Finished windows are not needed anymore
+	r.Db.Where("reset_at < ?", time.Now().UTC()).Delete(&domain.Counter{})
+
+	return nil
+}
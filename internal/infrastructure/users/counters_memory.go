@@ -0,0 +1,71 @@
+package users
+
+import (
+	"sync"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+)
+
+const maxMemoryCounters = 100000
+
+// memoryCounterStore keeps counters in memory, every instance of the app counts separately
+type memoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]domain.Counter
+}
+
+func NewMemoryCounterStore() domain.CounterStore {
+	return &memoryCounterStore{counters: make(map[string]domain.Counter)}
+}
+
+func (m *memoryCounterStore) Increment(key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[key]
+	if !ok || !now.Before(counter.ResetAt) {
+		m.prune(now)
+		counter = domain.Counter{Key: key, ResetAt: now.Add(window)}
+	}
+
+	counter.Count++
+	m.counters[key] = counter
+
+	return counter.Count, counter.ResetAt, nil
+}
+
+func (m *memoryCounterStore) Get(key string) (int, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[key]
+	if !ok || !time.Now().Before(counter.ResetAt) {
+		return 0, time.Time{}, nil
+	}
+
+	return counter.Count, counter.ResetAt, nil
+}
+
+func (m *memoryCounterStore) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.counters, key)
+	return nil
+}
+
+// prune drops finished windows when there are too many counters, must be called with the lock held
+func (m *memoryCounterStore) prune(now time.Time) {
+	if len(m.counters) < maxMemoryCounters {
+		return
+	}
+
+	for key, counter := range m.counters {
+		if !now.Before(counter.ResetAt) {
+			delete(m.counters, key)
+		}
+	}
+}
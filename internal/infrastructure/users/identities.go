@@ -0,0 +1,35 @@
+package users
+
+import (
+	"errors"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+type identityRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresIdentityRepositoryImpl(Db *gorm.DB) domain.IdentityRepository {
+	return &identityRepositoryImpl{Db: Db}
+}
+
+func (r *identityRepositoryImpl) Save(identity domain.Identity) error {
+	if err := r.Db.Create(&identity).Error; err != nil {
+		return errors.New("cannot save identity")
+	}
+
+	return nil
+}
+
+func (r *identityRepositoryImpl) FindBySubject(provider, subject string) (domain.Identity, error) {
+	var identity domain.Identity
+
+	if err := r.Db.Where("provider = ? AND subject = ?", provider, subject).Limit(1).Find(&identity).Error; err != nil {
+		return identity, errors.New("cannot find identity")
+	}
+
+	return identity, nil
+}
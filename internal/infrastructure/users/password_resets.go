@@ -0,0 +1,72 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+type passwordResetRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresPasswordResetRepositoryImpl(Db *gorm.DB) domain.PasswordResetRepository {
+	return &passwordResetRepositoryImpl{Db: Db}
+}
+
+func (r *passwordResetRepositoryImpl) Save(token domain.PasswordResetToken) error {
+	if err := r.Db.Create(&token).Error; err != nil {
+		return errors.New("cannot save reset token")
+	}
+
+	return nil
+}
+
+func (r *passwordResetRepositoryImpl) FindByHash(tokenHash string) (domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+
+	if err := r.Db.Where("token_hash = ?", tokenHash).Limit(1).Find(&token).Error; err != nil {
+		return token, errors.New("cannot find reset token")
+	}
+
+	return token, nil
+}
+
+func (r *passwordResetRepositoryImpl) CountCreatedSince(userId int, since time.Time) (int64, error) {
+	var count int64
+
+	err := r.Db.Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND created_at > ?", userId, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("cannot count reset tokens of the user: %d", userId)
+	}
+
+	return count, nil
+}
+
+func (r *passwordResetRepositoryImpl) MarkUsed(tokenId int, at time.Time) (bool, error) {
+	result := r.Db.Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", tokenId).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot use reset token: %d", tokenId)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *passwordResetRepositoryImpl) InvalidateByUserId(userId int, at time.Time) error {
+	err := r.Db.Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userId).
+		Update("used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("cannot invalidate reset tokens of the user: %d", userId)
+	}
+
+	return nil
+}
@@ -2,6 +2,9 @@ package users
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	domain "mono_pardo/internal/domain/users"
 
@@ -17,46 +20,232 @@ func NewPostgresRepositoryImpl(Db *gorm.DB) domain.Repository {
 }
 
 func (r *repositoryImpl) Save(user domain.User) error {
-	result := r.Db.Create(&user)
+	if err := r.Db.Create(&user).Error; err != nil {
+		if isUniqueViolation(r.Db, err) {
+			return domain.ErrEmailInUse
+		}
+		return errors.New("cannot save user")
+	}
+	return nil
+}
+
+func (r *repositoryImpl) UpdatePassword(userId int, hashedPassword string) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("password", hashedPassword).Error
+	if err != nil {
+		return fmt.Errorf("cannot update password of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) UpdateUsername(userId int, username string) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("username", username).Error
+	if err != nil {
+		return fmt.Errorf("cannot update username of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) UpdateEmail(userId int, email string) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Updates(map[string]interface{}{"email": email, "is_verified": true}).Error
+	if err != nil {
+		if isUniqueViolation(r.Db, err) {
+			return domain.ErrEmailInUse
+		}
+		return fmt.Errorf("cannot update email of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) MarkVerified(userId int) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("is_verified", true).Error
+	if err != nil {
+		return fmt.Errorf("cannot verify email of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) UpdateRole(userId int, role string) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("role", role).Error
+	if err != nil {
+		return fmt.Errorf("cannot update role of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) UpdateSuspendedAt(userId int, suspendedAt *time.Time) error {
+	err := r.Db.Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("suspended_at", suspendedAt).Error
+	if err != nil {
+		return fmt.Errorf("cannot update suspension of user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) Delete(usersId int) error {
+	result := r.Db.Where("id = ?", usersId).Delete(&domain.User{})
 	if result.Error != nil {
-		return errors.New("please use different email")
+		return fmt.Errorf("cannot delete user: %d", usersId)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
 	}
 	return nil
 }
 
-func (r *repositoryImpl) Delete(usersId int) {
+func (r *repositoryImpl) Restore(userId int) error {
+	err := r.Db.Unscoped().Model(&domain.User{}).
+		Where("id = ?", userId).
+		Update("deleted_at", nil).Error
+	if err != nil {
+		return fmt.Errorf("cannot restore user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) Purge(userId int) error {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Unscoped().Where("id = ?", userId).Limit(1).Find(&user).Error; err != nil {
+			return err
+		}
+		if user.Id == 0 {
+			return nil
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.TwoFactor{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.Identity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.AccessToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.SecurityEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("key IN ?", domain.CounterKeys(user)).Delete(&domain.Counter{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", userId).Delete(&domain.User{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot purge user: %d", userId)
+	}
+	return nil
+}
+
+func (r *repositoryImpl) FindDeletedByEmail(email string) (domain.User, error) {
 	var user domain.User
-	result := r.Db.Where("id = ?", usersId).Delete(&user)
+	result := r.Db.Unscoped().Where("email = ? AND deleted_at IS NOT NULL", email).Limit(1).Find(&user)
+	if result.Error != nil {
+		return user, errors.New("cannot find deleted user")
+	}
+	return user, nil
+}
+
+func (r *repositoryImpl) FindDeletedBefore(before time.Time) ([]domain.User, error) {
+	users := []domain.User{}
+	result := r.Db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Find(&users)
 	if result.Error != nil {
-		panic(result.Error)
+		return nil, errors.New("cannot find deleted users")
 	}
+	return users, nil
 }
 
-func (r *repositoryImpl) FindAll() []domain.User {
-	var user []domain.User
-	results := r.Db.Find(&user)
-	if results.Error != nil {
-		panic(results.Error)
+func (r *repositoryImpl) FindAll() ([]domain.User, error) {
+	users := []domain.User{}
+	if err := r.Db.Order("id").Find(&users).Error; err != nil {
+		return nil, errors.New("cannot find users")
 	}
-	return user
+	return users, nil
+}
+
+func (r *repositoryImpl) FindPage(query domain.UserQuery) ([]domain.User, int64, error) {
+	users := []domain.User{}
+	var total int64
+
+	db := r.Db.Model(&domain.User{})
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		db = db.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if query.Role != "" {
+		db = db.Where("role = ?", query.Role)
+	}
+	if query.Suspended != nil {
+		if *query.Suspended {
+			db = db.Where("suspended_at IS NOT NULL")
+		} else {
+			db = db.Where("suspended_at IS NULL")
+		}
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.New("cannot count users")
+	}
+
+	if err := db.Order("id").Limit(query.Limit).Offset(query.Offset).Find(&users).Error; err != nil {
+		return nil, 0, errors.New("cannot find users")
+	}
+
+	return users, total, nil
 }
 
 func (r *repositoryImpl) FindById(userId int) (domain.User, error) {
 	var user domain.User
-	result := r.Db.Find(&user, userId)
-	if result != nil {
-		return user, nil
-	} else {
-		return user, errors.New("user is not found")
+	result := r.Db.Limit(1).Find(&user, userId)
+	if result.Error != nil {
+		return user, fmt.Errorf("cannot find user: %d", userId)
+	}
+	if result.RowsAffected == 0 {
+		return user, domain.ErrUserNotFound
 	}
+	return user, nil
 }
 
 func (r *repositoryImpl) FindByEmail(email string) (domain.User, error) {
 	var user domain.User
 	result := r.Db.First(&user, "email = ?", email)
 
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
 	if result.Error != nil {
-		return user, errors.New("invalid email or Password")
+		return domain.User{}, errors.New("cannot find user by email")
 	}
 	return user, nil
 }
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+// isUniqueViolation is true if the error is about a taken value of a unique index, e.g. email
+func isUniqueViolation(db *gorm.DB, err error) bool {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,73 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresRefreshTokenRepositoryImpl(Db *gorm.DB) domain.RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{Db: Db}
+}
+
+func (r *refreshTokenRepositoryImpl) Save(token domain.RefreshToken) error {
+	if err := r.Db.Create(&token).Error; err != nil {
+		return errors.New("cannot save refresh token")
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) FindByHash(tokenHash string) (domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	if err := r.Db.Where("token_hash = ?", tokenHash).Limit(1).Find(&token).Error; err != nil {
+		return token, errors.New("cannot find refresh token")
+	}
+
+	return token, nil
+}
+
+func (r *refreshTokenRepositoryImpl) FindActiveByUserId(userId int, now time.Time) ([]domain.RefreshToken, error) {
+	tokens := []domain.RefreshToken{}
+
+	err := r.Db.
+		Where("user_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userId, now).
+		Order("started_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot find sessions of the user: %d", userId)
+	}
+
+	return tokens, nil
+}
+
+func (r *refreshTokenRepositoryImpl) MarkRotated(tokenId int, at time.Time) (bool, error) {
+	result := r.Db.Model(&domain.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL", tokenId).
+		Update("rotated_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot rotate refresh token: %d", tokenId)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeFamily(familyId string) error {
+	err := r.Db.Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update("revoked_at", time.Now().UTC()).Error
+	if err != nil {
+		return errors.New("cannot revoke session")
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package users
+
+import (
+	"sync"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+)
+
+const (
+	defaultRevocationCacheTTL = 30 * time.Second
+	maxCachedRevocations      = 100000
+)
+
+// cachedRevocationStore keeps results of the backing store in memory, so that not every request
+// goes to the database. Revocations are final, so ids revoked through this instance are cached
+// until they expire. Ids which are not revoked are cached only for ttl, this is the longest time it takes for
+// a revocation made by another instance to be noticed.
+type cachedRevocationStore struct {
+	store domain.RevocationStore
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	revoked map[string]time.Time // id -> expiration of the revocation
+	valid   map[string]time.Time // id -> time until which it is considered not revoked
+}
+
+func NewCachedRevocationStore(store domain.RevocationStore, ttl time.Duration) domain.RevocationStore {
+	if ttl <= 0 {
+		ttl = defaultRevocationCacheTTL
+	}
+
+	return &cachedRevocationStore{
+		store:   store,
+		ttl:     ttl,
+		revoked: make(map[string]time.Time),
+		valid:   make(map[string]time.Time),
+	}
+}
+
+func (c *cachedRevocationStore) Revoke(id string, expiresAt time.Time) error {
+	if err := c.store.Revoke(id, expiresAt); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expiresAt.After(c.revoked[id]) {
+		c.revoked[id] = expiresAt
+	}
+	delete(c.valid, id)
+	c.prune(time.Now())
+
+	return nil
+}
+
+func (c *cachedRevocationStore) IsRevoked(ids ...string) (bool, error) {
+	ids = nonEmpty(ids)
+	now := time.Now()
+
+	c.mu.Lock()
+	unknown := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if expiresAt, ok := c.revoked[id]; ok && now.Before(expiresAt) {
+			c.mu.Unlock()
+			return true, nil
+		}
+		if until, ok := c.valid[id]; !ok || !now.Before(until) {
+			unknown = append(unknown, id)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(unknown) == 0 {
+		return false, nil
+	}
+
+	// Store doesn't tell which of ids is revoked, so they are checked one by one
+	for _, id := range unknown {
+		revoked, err := c.store.IsRevoked(id)
+		if err != nil {
+			return false, err
+		}
+
+		c.mu.Lock()
+		if revoked {
+			// Exact expiration is unknown, so the revocation is cached as long as positive results are
+			c.revoked[id] = now.Add(c.ttl)
+		} else {
+			c.valid[id] = now.Add(c.ttl)
+		}
+		c.prune(now)
+		c.mu.Unlock()
+
+		if revoked {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// prune drops outdated entries when the cache grows too big, must be called with the lock held
+func (c *cachedRevocationStore) prune(now time.Time) {
+	if len(c.revoked)+len(c.valid) < maxCachedRevocations {
+		return
+	}
+
+	for id, expiresAt := range c.revoked {
+		if !now.Before(expiresAt) {
+			delete(c.revoked, id)
+		}
+	}
+	for id, until := range c.valid {
+		if !now.Before(until) {
+			delete(c.valid, id)
+		}
+	}
+
+	// Every entry is still fresh, forget results for not revoked ids, they are cheap to check again
+	if len(c.revoked)+len(c.valid) >= maxCachedRevocations {
+		c.valid = make(map[string]time.Time)
+	}
+}
@@ -0,0 +1,60 @@
+package users
+
+import (
+	"errors"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revocationStoreImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresRevocationStoreImpl(Db *gorm.DB) domain.RevocationStore {
+	return &revocationStoreImpl{Db: Db}
+}
+
+func (r *revocationStoreImpl) Revoke(id string, expiresAt time.Time) error {
+	revoked := domain.RevokedToken{Id: id, ExpiresAt: expiresAt}
+
+	err := r.Db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"expires_at": gorm.Expr("GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at)")}),
+	}).Create(&revoked).Error
+	if err != nil {
+		return errors.New("cannot revoke token")
+	}
+
+	// Expired records are not needed anymore, tokens with them are rejected by "exp" claim
+	r.Db.Where("expires_at < ?", time.Now().UTC()).Delete(&domain.RevokedToken{})
+
+	return nil
+}
+
+func (r *revocationStoreImpl) IsRevoked(ids ...string) (bool, error) {
+	ids = nonEmpty(ids)
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	if err := r.Db.Model(&domain.RevokedToken{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return false, errors.New("cannot check token revocation")
+	}
+
+	return count > 0, nil
+}
+
+func nonEmpty(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			result = append(result, id)
+		}
+	}
+	return result
+}
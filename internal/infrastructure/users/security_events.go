@@ -0,0 +1,57 @@
+package users
+
+import (
+	"errors"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+)
+
+type securityEventRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresSecurityEventRepositoryImpl(Db *gorm.DB) domain.SecurityEventRepository {
+	return &securityEventRepositoryImpl{Db: Db}
+}
+
+func (r *securityEventRepositoryImpl) Save(event domain.SecurityEvent) error {
+	if err := r.Db.Create(&event).Error; err != nil {
+		return errors.New("cannot save security event")
+	}
+
+	return nil
+}
+
+func (r *securityEventRepositoryImpl) FindPage(query domain.SecurityEventQuery) ([]domain.SecurityEvent, int64, error) {
+	events := []domain.SecurityEvent{}
+	var total int64
+
+	db := r.Db.Model(&domain.SecurityEvent{})
+	if query.UserId != nil {
+		db = db.Where("user_id = ?", *query.UserId)
+	}
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.IP != "" {
+		db = db.Where("ip = ?", query.IP)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at < ?", *query.To)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, errors.New("cannot count security events")
+	}
+
+	if err := db.Order("created_at DESC, id DESC").Limit(query.Limit).Offset(query.Offset).Find(&events).Error; err != nil {
+		return nil, 0, errors.New("cannot find security events")
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,106 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domain "mono_pardo/internal/domain/users"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type twoFactorRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPostgresTwoFactorRepositoryImpl(Db *gorm.DB) domain.TwoFactorRepository {
+	return &twoFactorRepositoryImpl{Db: Db}
+}
+
+func (r *twoFactorRepositoryImpl) Save(twoFactor domain.TwoFactor) error {
+	err := r.Db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_counter", "created_at"}),
+	}).Create(&twoFactor).Error
+	if err != nil {
+		return errors.New("cannot save two-factor settings")
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) FindByUserId(userId int) (domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+
+	if err := r.Db.Where("user_id = ?", userId).Limit(1).Find(&twoFactor).Error; err != nil {
+		return twoFactor, fmt.Errorf("cannot find two-factor settings of the user: %d", userId)
+	}
+
+	return twoFactor, nil
+}
+
+func (r *twoFactorRepositoryImpl) Enable(userId int, at time.Time, counter int64) error {
+	err := r.Db.Model(&domain.TwoFactor{}).
+		Where("user_id = ?", userId).
+		Updates(map[string]interface{}{"enabled_at": at, "last_counter": counter}).Error
+	if err != nil {
+		return fmt.Errorf("cannot enable two-factor authentication of the user: %d", userId)
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) UseCounter(userId int, counter int64) (bool, error) {
+	result := r.Db.Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND last_counter < ?", userId, counter).
+		Update("last_counter", counter)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot use code of the user: %d", userId)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepositoryImpl) Delete(userId int) error {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&domain.TwoFactor{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot disable two-factor authentication of the user: %d", userId)
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) ReplaceRecoveryCodes(userId int, codes []domain.RecoveryCode) error {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot save recovery codes of the user: %d", userId)
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) UseRecoveryCode(userId int, codeHash string, at time.Time) (bool, error) {
+	result := r.Db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot use recovery code of the user: %d", userId)
+	}
+
+	return result.RowsAffected == 1, nil
+}
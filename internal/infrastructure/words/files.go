@@ -0,0 +1,70 @@
+package words
+
+import (
+	domainErrors "mono_pardo/internal/domain/errors"
+	domain "mono_pardo/internal/domain/words"
+	"mono_pardo/internal/infrastructure/anki"
+	"mono_pardo/internal/infrastructure/delimited"
+	"mono_pardo/internal/infrastructure/kindle"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+// fileParserImpl reports every problem of the uploaded file as a validation error of the request
+type fileParserImpl struct{}
+
+func NewFileParserImpl() domain.FileParser {
+	return &fileParserImpl{}
+}
+
+func (p *fileParserImpl) ParseDelimited(parseRequest request.ImportCSVRequest) ([]request.ImportRow, error) {
+	delimiter, err := delimited.ParseDelimiter(parseRequest.Delimiter)
+	if err != nil {
+		return nil, domainErrors.InvalidField("invalid_import", "delimiter", "%s", err)
+	}
+
+	rows, err := delimited.Parse(parseRequest.File, delimited.Options{
+		Delimiter: delimiter,
+		HasHeader: parseRequest.HasHeader == nil || *parseRequest.HasHeader,
+		Mapping: delimited.Mapping{
+			Word:       parseRequest.WordColumn,
+			Definition: parseRequest.DefinitionColumn,
+			Tags:       parseRequest.TagsColumn,
+			Notes:      parseRequest.NotesColumn,
+		},
+	})
+	if err != nil {
+		return nil, invalidFile(err)
+	}
+
+	return rows, nil
+}
+
+func (p *fileParserImpl) ParseAnki(parseRequest request.ImportAnkiRequest) ([]request.ImportRow, []response.ImportMedia, error) {
+	result, err := anki.Import(parseRequest.File, parseRequest.Size, anki.Mapping{
+		Word:       parseRequest.WordField,
+		Definition: parseRequest.DefinitionField,
+	})
+	if err != nil {
+		return nil, nil, invalidFile(err)
+	}
+
+	return result.Rows, result.Media, nil
+}
+
+func (p *fileParserImpl) ParseKindle(parseRequest request.ImportKindleRequest) ([]request.ImportRow, []response.KindleBook, error) {
+	result, err := kindle.Import(parseRequest.File, kindle.Options{
+		Book: parseRequest.Book,
+		From: parseRequest.From,
+		To:   parseRequest.To,
+	})
+	if err != nil {
+		return nil, nil, invalidFile(err)
+	}
+
+	return result.Rows, result.Books, nil
+}
+
+func invalidFile(err error) error {
+	return domainErrors.Invalid("invalid_file", "%s", err)
+}
@@ -3,6 +3,7 @@ package words
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	domain "mono_pardo/internal/domain/words"
 	"mono_pardo/internal/utils"
@@ -29,6 +30,21 @@ func (r *repositoryImpl) Delete(wordId int) error {
 	return nil
 }
 
+// DeleteByUserId removes all words of the user together with their training history
+func (r *repositoryImpl) DeleteByUserId(userId int) error {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.TrainingAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&domain.Word{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete words of the user: %d", userId)
+	}
+
+	return nil
+}
+
 func (r *repositoryImpl) FindByUserId(userId int) ([]domain.Word, error) {
 	var words []domain.Word
 
@@ -40,6 +56,73 @@ func (r *repositoryImpl) FindByUserId(userId int) ([]domain.Word, error) {
 	return words, nil
 }
 
+func (r *repositoryImpl) FindByIds(userId int, wordIds []int) ([]domain.Word, error) {
+	words := []domain.Word{}
+
+	if len(wordIds) == 0 {
+		return words, nil
+	}
+
+	if err := r.Db.Where("user_id = ? AND id IN ?", userId, wordIds).Order("id").Find(&words).Error; err != nil {
+		return nil, errors.New("words is not found")
+	}
+
+	return words, nil
+}
+
+func (r *repositoryImpl) FindPage(query domain.WordQuery) ([]domain.Word, error) {
+	var words []domain.Word
+
+	db := r.Db.Where("user_id = ?", query.UserId)
+
+	filters := map[string]*bool{
+		"is_learned":       query.IsLearned,
+		"cards":            query.Cards,
+		"word_translation": query.WordTranslation,
+		"constructor":      query.Constructor,
+		"word_audio":       query.WordAudio,
+	}
+	for column, value := range filters {
+		if value != nil {
+			db = db.Where(column+" = ?", *value)
+		}
+	}
+
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at > ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+
+	// Sort field is validated in domain, so it is safe to put it to the query
+	direction, operator := "ASC", ">"
+	if query.Descending {
+		direction, operator = "DESC", "<"
+	}
+
+	if query.After != nil {
+		value, err := query.CursorValue()
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", query.Sort, operator), value, query.After.Id)
+	}
+
+	db = db.Order(fmt.Sprintf("%s %s, id %s", query.Sort, direction, direction))
+
+	// One extra word shows that there is a next page
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit + 1)
+	}
+
+	if err := db.Find(&words).Error; err != nil {
+		return nil, errors.New("words is not found")
+	}
+
+	return words, nil
+}
+
 func (r *repositoryImpl) FindById(wordId int) (domain.Word, error) {
 	var word domain.Word
 
@@ -50,12 +133,51 @@ func (r *repositoryImpl) FindById(wordId int) (domain.Word, error) {
 	return word, nil
 }
 
-func (r *repositoryImpl) Save(word domain.Word) error {
+func (r *repositoryImpl) FindByWord(userId int, word string) (domain.Word, error) {
+	var found domain.Word
+
+	if err := r.Db.Where("user_id = ? AND word = ?", userId, word).Find(&found).Error; err != nil {
+		return found, fmt.Errorf("cannot find word: %s", word)
+	}
+
+	return found, nil
+}
+
+func (r *repositoryImpl) FindByWords(userId int, words []string) ([]domain.Word, error) {
+	found := []domain.Word{}
+
+	if len(words) == 0 {
+		return found, nil
+	}
+
+	if err := r.Db.Where("user_id = ? AND word IN ?", userId, words).Find(&found).Error; err != nil {
+		return nil, errors.New("cannot find words")
+	}
+
+	return found, nil
+}
+
+func (r *repositoryImpl) FindDueByUserId(userId int, now time.Time, limit int) ([]domain.Word, error) {
+	var words []domain.Word
+
+	err := r.Db.
+		Where("user_id = ? AND is_learned = ? AND due_at <= ?", userId, true, now).
+		Order("due_at ASC").
+		Limit(limit).
+		Find(&words).Error
+	if err != nil {
+		return nil, errors.New("cannot find words due to review")
+	}
+
+	return words, nil
+}
+
+func (r *repositoryImpl) Save(word domain.Word) (int, error) {
 	if err := r.Db.Create(&word).Error; err != nil {
-		return errors.New("cannot save word")
+		return 0, errors.New("cannot save word")
 	}
 
-	return nil
+	return word.Id, nil
 }
 
 func (r *repositoryImpl) Update(wordUpdate request.WordUpdate) error {
@@ -71,14 +193,86 @@ func (r *repositoryImpl) Update(wordUpdate request.WordUpdate) error {
 	return nil
 }
 
-func (r *repositoryImpl) IsOwnerOfWord(userId int, wordId int) (bool, error) {
-	var word domain.Word
+func (r *repositoryImpl) SaveAttempt(attempt domain.TrainingAttempt) error {
+	if err := r.Db.Create(&attempt).Error; err != nil {
+		return errors.New("cannot save training attempt")
+	}
 
-	if err := r.Db.Where("id = ?", wordId).Find(&word).Error; err != nil {
-		return false, fmt.Errorf("cannot check who is owner of the word: %d", wordId)
+	return nil
+}
+
+// UpdateWithAttempts changes the word and saves its training history in one transaction
+func (r *repositoryImpl) UpdateWithAttempts(wordUpdate request.WordUpdate, attempts []domain.TrainingAttempt) error {
+	updateMap := utils.ConvertFieldUpdatesToMap(wordUpdate.Updates)
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		// Repeated training result doesn't change the word, only the history
+		if len(updateMap) > 0 {
+			if err := tx.Model(&domain.Word{}).Where("id = ?", wordUpdate.WordId).Updates(updateMap).Error; err != nil {
+				return err
+			}
+		}
+		if len(attempts) == 0 {
+			return nil
+		}
+		return tx.Create(&attempts).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update word: %d", wordUpdate.WordId)
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) FindAttemptsByUserId(userId int) ([]domain.TrainingAttempt, error) {
+	attempts := []domain.TrainingAttempt{}
+
+	if err := r.Db.Where("user_id = ?", userId).Order("created_at, id").Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("cannot find training history of the user: %d", userId)
+	}
+
+	return attempts, nil
+}
+
+func (r *repositoryImpl) FindAttemptsByWordId(wordId int, limit int, offset int) ([]domain.TrainingAttempt, int64, error) {
+	var attempts []domain.TrainingAttempt
+	var total int64
+
+	query := r.Db.Model(&domain.TrainingAttempt{}).Where("word_id = ?", wordId)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("cannot count training history of the word: %d", wordId)
+	}
+
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&attempts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot find training history of the word: %d", wordId)
+	}
+
+	return attempts, total, nil
+}
+
+func (r *repositoryImpl) CountByUserIds(userIds []int) (map[int]domain.VocabCount, error) {
+	counts := make(map[int]domain.VocabCount, len(userIds))
+	if len(userIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []domain.VocabCount
+	err := r.Db.Model(&domain.Word{}).
+		Select("user_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE is_learned) AS learned").
+		Where("user_id IN ?", userIds).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New("cannot count words")
+	}
+
+	for _, row := range rows {
+		counts[row.UserId] = row
 	}
 
-	return word.UserId == userId, nil
+	return counts, nil
 }
 
 // func (r *repositoryImpl) Add(word domain.Word) (int, error) {
@@ -0,0 +1,68 @@
+package words
+
+import (
+	"errors"
+	"strings"
+
+	domain "mono_pardo/internal/domain/words"
+
+	"gorm.io/gorm"
+)
+
+/*
+	Search is built on pg_trgm extension. GIN trigram indexes serve both
+	similarity operators (typo tolerance) and ILIKE patterns (prefix matching),
+	so search doesn't scan whole table for users with large vocabularies.
+*/
+
+var searchIndexes = []string{
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+	"CREATE INDEX IF NOT EXISTS idx_words_word_trgm ON words USING gin (word gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_words_definition_trgm ON words USING gin (definition gin_trgm_ops)",
+}
+
+// CreateSearchIndexes should be run after migration of words table
+func CreateSearchIndexes(db *gorm.DB) error {
+	for _, statement := range searchIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repositoryImpl) Search(userId int, query string, limit int) ([]domain.Word, error) {
+	var words []domain.Word
+
+	// Exact match goes first, then prefix match, then the most similar words
+	const searchQuery = `
+		SELECT * FROM words
+		WHERE user_id = @userId
+			AND (word ILIKE @prefix OR word % @query OR definition ILIKE @contains OR @query <% definition)
+		ORDER BY
+			(CASE WHEN lower(word) = lower(@query) THEN 2 ELSE 0 END) +
+			(CASE WHEN word ILIKE @prefix THEN 1 ELSE 0 END) +
+			similarity(word, @query) +
+			0.5 * word_similarity(@query, definition) DESC,
+			id ASC
+		LIMIT @limit`
+
+	err := r.Db.Raw(searchQuery, map[string]interface{}{
+		"userId":   userId,
+		"query":    query,
+		"prefix":   escapeLike(query) + "%",
+		"contains": "%" + escapeLike(query) + "%",
+		"limit":    limit,
+	}).Scan(&words).Error
+	if err != nil {
+		return nil, errors.New("cannot search words")
+	}
+
+	return words, nil
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
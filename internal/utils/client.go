@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"mono_pardo/pkg/data/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetClientInfo returns who sent the request, for the security log. IP comes from X-Forwarded-For
+// only behind TRUSTED_PROXIES, otherwise it's the remote address of the connection
+func GetClientInfo(ctx *gin.Context) request.ClientInfo {
+	return request.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.GetHeader("User-Agent"),
+		RequestId: ctx.GetString("requestId"),
+	}
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mono_pardo/pkg/data/response"
+
+	"github.com/golang-jwt/jwt"
+)
+
+/*
+	Access tokens are signed with an asymmetric key, so other services can verify them with the
+	public keys we publish as JWKS, and the key can be changed without logging everyone out.
+
+	Keys are PEM files in the key directory, the file name without ".pem" is the key id (kid).
+	A private key (PKCS#8, or PKCS#1 for RSA) signs and verifies tokens, a public key only
+	verifies them. The key is rotated in three steps:
+	  1. the new private key is added, it's published but tokens are still signed with the old one
+	  2. when the verifiers have fetched the new JWKS, the new key becomes TOKEN_SIGNING_KEY_ID
+	  3. when the tokens of the old key have expired, the old key is removed
+*/
+
+const minRSAKeyBits = 2048
+
+type SigningKey struct {
+	Id      string
+	Method  jwt.SigningMethod
+	Private interface{} // nil if the key only verifies tokens
+	Public  interface{}
+}
+
+type KeySet struct {
+	signing *SigningKey
+	keys    map[string]*SigningKey
+}
+
+// LoadKeySet reads all keys of the directory, signingKeyId can be empty if there is only one private key
+func LoadKeySet(dir string, signingKeyId string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no keys in %s", dir)
+	}
+
+	keys := make([]SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(signingKeyId, keys...)
+}
+
+func NewKeySet(signingKeyId string, keys ...SigningKey) (*KeySet, error) {
+	set := &KeySet{keys: map[string]*SigningKey{}}
+
+	for i := range keys {
+		key := &keys[i]
+		if _, ok := set.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key: %s", key.Id)
+		}
+		set.keys[key.Id] = key
+
+		if key.Private == nil || (signingKeyId != "" && key.Id != signingKeyId) {
+			continue
+		}
+		if set.signing != nil {
+			return nil, errors.New("signing key id is required when there are several private keys")
+		}
+		set.signing = key
+	}
+
+	if set.signing == nil {
+		return nil, fmt.Errorf("no private key to sign tokens: %s", signingKeyId)
+	}
+
+	return set, nil
+}
+
+// NewHMACKeySet signs tokens with the shared secret, when no key directory is configured.
+// Such tokens can't be verified by other services, so the key isn't published
+func NewHMACKeySet(secret string) *KeySet {
+	key := &SigningKey{Method: jwt.SigningMethodHS256, Private: []byte(secret), Public: []byte(secret)}
+	return &KeySet{signing: key, keys: map[string]*SigningKey{"": key}}
+}
+
+func ParseSigningKey(id string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("key %s is not PEM encoded", id)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported PEM block of key %s: %s", id, block.Type)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("invalid key %s: %w", id, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return SigningKey{}, fmt.Errorf("RSA key %s is shorter than %d bits", id, minRSAKeyBits)
+		}
+		return SigningKey{Id: id, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return SigningKey{}, fmt.Errorf("RSA key %s is shorter than %d bits", id, minRSAKeyBits)
+		}
+		return SigningKey{Id: id, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{Id: id, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return SigningKey{Id: id, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	}
+
+	return SigningKey{}, fmt.Errorf("unsupported type of key %s, only RSA and Ed25519 are supported", id)
+}
+
+// sign signs the token with the current key and names the key in the header
+func (k *KeySet) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.Method, claims)
+	if k.signing.Id != "" {
+		token.Header["kid"] = k.signing.Id
+	}
+
+	return token.SignedString(k.signing.Private)
+}
+
+// verificationKey is the key func of the parser, algorithm of the token must be the algorithm of
+// its key, otherwise e.g. a public RSA key could be used as HMAC secret
+func (k *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected method: %s", token.Header["alg"])
+	}
+
+	return key.Public, nil
+}
+
+// JWKS returns public keys, which can verify tokens, sorted by id
+func (k *KeySet) JWKS() response.JWKSResponse {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	res := response.JWKSResponse{Keys: []response.JSONWebKey{}}
+	for _, id := range ids {
+		key := k.keys[id]
+
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			res.Keys = append(res.Keys, response.JSONWebKey{
+				Kid: key.Id,
+				Kty: "RSA",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			res.Keys = append(res.Keys, response.JSONWebKey{
+				Kid: key.Id,
+				Kty: "OKP",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return res
+}
@@ -1,20 +1,202 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+/*
+	Passwords are hashed in a self-describing format ($argon2id$v=19$m=...,t=...,p=...$salt$hash or
+	$2a$cost$... for bcrypt), so the algorithm and its parameters can change over time: new passwords
+	get the current hasher, old hashes are still verified by the hasher which made them and are
+	replaced the next time the user logs in.
+*/
+
+var ErrPasswordMismatch = errors.New("password doesn't match")
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encodedHash, password string) error // ErrPasswordMismatch if the password is wrong
+	Identifies(encodedHash string) bool        // the hash was made by this algorithm
+	NeedsRehash(encodedHash string) bool       // the hash isn't made by the current algorithm and parameters
+}
+
+// DefaultArgon2idParams are the second recommended option of RFC 9106, which needs 64 MiB of memory
+var DefaultArgon2idParams = Argon2idParams{Memory: 64 * 1024, Iterations: 3, Parallelism: 4}
+
+// DefaultPasswordHasher hashes new passwords with argon2id and still verifies bcrypt hashes
+var DefaultPasswordHasher = NewPasswordHasher(Argon2idHasher{Params: DefaultArgon2idParams}, BcryptHasher{Cost: bcrypt.DefaultCost})
+
 func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return DefaultPasswordHasher.Hash(password)
+}
+
+func VerifyPassword(hashedPassword string, candidatePassword string) error {
+	return DefaultPasswordHasher.Verify(hashedPassword, candidatePassword)
+}
+
+type passwordHashers struct {
+	current PasswordHasher
+	legacy  []PasswordHasher
+}
+
+// NewPasswordHasher hashes with the current hasher and verifies hashes of all of them
+func NewPasswordHasher(current PasswordHasher, legacy ...PasswordHasher) PasswordHasher {
+	return &passwordHashers{current: current, legacy: legacy}
+}
+
+func (h *passwordHashers) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *passwordHashers) Verify(encodedHash, password string) error {
+	hasher, err := h.find(encodedHash)
+	if err != nil {
+		return err
+	}
+
+	return hasher.Verify(encodedHash, password)
+}
+
+func (h *passwordHashers) Identifies(encodedHash string) bool {
+	_, err := h.find(encodedHash)
+	return err == nil
+}
+
+func (h *passwordHashers) NeedsRehash(encodedHash string) bool {
+	return !h.current.Identifies(encodedHash) || h.current.NeedsRehash(encodedHash)
+}
+
+func (h *passwordHashers) find(encodedHash string) (PasswordHasher, error) {
+	if h.current.Identifies(encodedHash) {
+		return h.current, nil
+	}
+	for _, hasher := range h.legacy {
+		if hasher.Identifies(encodedHash) {
+			return hasher, nil
+		}
+	}
+
+	return nil, errors.New("unknown password hash format")
+}
+
+type Argon2idParams struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+type Argon2idHasher struct {
+	Params Argon2idParams
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("could not hash password %w", err)
+	}
+
+	p := h.Params
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h Argon2idHasher) Verify(encodedHash, password string) error {
+	p, salt, key, err := decodeArgon2id(encodedHash)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+func (h Argon2idHasher) Identifies(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+func (h Argon2idHasher) NeedsRehash(encodedHash string) bool {
+	p, _, _, err := decodeArgon2id(encodedHash)
+	return err != nil || p != h.Params
+}
+
+func decodeArgon2id(encodedHash string) (Argon2idParams, []byte, []byte, error) {
+	var p Argon2idParams
+	invalid := errors.New("invalid argon2id hash")
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, invalid
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, invalid
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, invalid
+	}
+	if p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, invalid
+	}
 
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, invalid
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, invalid
+	}
+
+	return p, salt, key, nil
+}
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
 	if err != nil {
 		return "", fmt.Errorf("could not hash password %w", err)
 	}
+
 	return string(hashedPassword), nil
 }
 
-func VerifyPassword(hashedPassword string, candidatePassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(candidatePassword))
+func (h BcryptHasher) Verify(encodedHash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+
+	return err
+}
+
+func (h BcryptHasher) Identifies(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") || strings.HasPrefix(encodedHash, "$2b$") || strings.HasPrefix(encodedHash, "$2y$")
+}
+
+func (h BcryptHasher) NeedsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost != h.Cost
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+/*
+	Signed tokens for links sent to users (e.g. email verification). Key of every purpose is
+	derived from the token secret, so such a token can't be used as access token and vice versa.
+*/
+
+func GenerateSignedToken(purpose string, ttl time.Duration, payload map[string]interface{}, secretKey string) (string, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	now := time.Now().UTC()
+	claims := token.Claims.(jwt.MapClaims)
+
+	for key, value := range payload {
+		claims[key] = value
+	}
+	claims["pur"] = purpose
+	claims["exp"] = now.Add(ttl).Unix()
+	claims["iat"] = now.Unix()
+
+	tokenString, err := token.SignedString(purposeKey(purpose, secretKey))
+	if err != nil {
+		return "", fmt.Errorf("generating signed token failed: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+func ValidateSignedToken(purpose string, token string, secretKey string) (map[string]interface{}, error) {
+	tok, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
+		if _, ok := jwtToken.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected method: %s", jwtToken.Header["alg"])
+		}
+
+		return purposeKey(purpose, secretKey), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := tok.Claims.(jwt.MapClaims)
+	if !ok || !tok.Valid || claims["pur"] != purpose {
+		return nil, fmt.Errorf("invalid token claim")
+	}
+
+	return claims, nil
+}
+
+func purposeKey(purpose, secretKey string) []byte {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -9,19 +9,32 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateToken(ttl time.Duration, payload interface{}, secretJWTKey string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+type TokenClaims struct {
+	Subject   interface{}
+	Id        string // jti, unique id of the token
+	SessionId string // id of the refresh token family the token was issued for
+	Role      string
+	ExpiresAt time.Time
+}
+
+func GenerateToken(ttl time.Duration, payload interface{}, sessionId string, role string, keys *KeySet) (string, error) {
+	jti, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
 
 	now := time.Now().UTC()
-	claims := token.Claims.(jwt.MapClaims)
+	claims := jwt.MapClaims{}
 
 	claims["sub"] = payload
+	claims["jti"] = jti
+	claims["sid"] = sessionId
+	claims["role"] = role
 	claims["exp"] = now.Add(ttl).Unix()
 	claims["iat"] = now.Unix()
 	claims["nbf"] = now.Unix()
 
-	tokenString, err := token.SignedString([]byte(secretJWTKey))
-
+	tokenString, err := keys.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("generating JWT Token failed: %w", err)
 	}
@@ -29,24 +42,26 @@ func GenerateToken(ttl time.Duration, payload interface{}, secretJWTKey string)
 	return tokenString, nil
 }
 
-func ValidateToken(token string, signedJWTKey string) (interface{}, error) {
-	tok, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
-		if _, ok := jwtToken.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected method: %s", jwtToken.Header["alg"])
-		}
-
-		return []byte(signedJWTKey), nil
-	})
+func ValidateToken(token string, keys *KeySet) (TokenClaims, error) {
+	tok, err := jwt.Parse(token, keys.verificationKey)
 	if err != nil {
-		return nil, fmt.Errorf("invalidate token: %w", err)
+		return TokenClaims{}, fmt.Errorf("invalidate token: %w", err)
 	}
 
 	claims, ok := tok.Claims.(jwt.MapClaims)
 	if !ok || !tok.Valid {
-		return nil, fmt.Errorf("invalid token claim")
+		return TokenClaims{}, fmt.Errorf("invalid token claim")
+	}
+
+	result := TokenClaims{Subject: claims["sub"]}
+	result.Id, _ = claims["jti"].(string)
+	result.SessionId, _ = claims["sid"].(string)
+	result.Role, _ = claims["role"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = time.Unix(int64(exp), 0).UTC()
 	}
 
-	return claims["sub"], nil
+	return result, nil
 }
 
 func GetToken(ctx *gin.Context) (string, error) {
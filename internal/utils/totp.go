@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+	Time-based one-time passwords (RFC 6238) with parameters supported by all authenticator apps:
+	SHA-1, 6 digits and 30 seconds period.
+*/
+
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+	totpSkew   = 1 // codes of the previous and the next period are accepted because of clock drift
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns 160 bits secret encoded as base32, as authenticator apps expect it
+func GenerateTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating secret failed: %w", err)
+	}
+
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TOTPURI returns otpauth URI which is shown as QR code to be scanned by authenticator app
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// GenerateTOTP returns the code of the period which contains the time
+func GenerateTOTP(key []byte, at time.Time, period time.Duration, digits int, algorithm func() hash.Hash) string {
+	counter := uint64(at.Unix() / int64(period.Seconds()))
+	return hotp(key, counter, digits, algorithm)
+}
+
+// ValidateTOTP checks the code with default parameters and returns its time counter, which is used
+// to reject the same code when it's presented again
+func ValidateTOTP(secret, code string, at time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return 0, false
+	}
+
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := at.Unix() / int64(TOTPPeriod.Seconds())
+	for counter := current - totpSkew; counter <= current+totpSkew; counter++ {
+		expected := hotp(key, uint64(counter), TOTPDigits, sha1.New)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// hotp is HMAC-based one-time password from RFC 4226
+func hotp(key []byte, counter uint64, digits int, algorithm func() hash.Hash) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(algorithm, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -20,3 +24,20 @@ func ConnectionDB(config *Config) *gorm.DB {
 	fmt.Println("🚀 Connected Successfully to the Database")
 	return db
 }
+
+func ConnectionMongo(config *Config) *mongo.Database {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoURI))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = client.Ping(ctx, nil); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("🚀 Connected Successfully to the Mongo Database")
+	return client.Database(config.MongoDB)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -17,9 +18,79 @@ type Config struct {
 	DBTestName string `mapstructure:"POSTGRES_DB_TEST"`
 	DBPort     string `mapstructure:"POSTGRES_PORT"`
 
+	MongoURI    string `mapstructure:"MONGO_URI"`
+	MongoDB     string `mapstructure:"MONGO_DB"`
+	MongoTestDB string `mapstructure:"MONGO_DB_TEST"`
+
 	TokenSecret    string        `mapstructure:"TOKEN_SECRET"`
 	TokenExpiresIn time.Duration `mapstructure:"TOKEN_EXPIRED_IN"`
 	TokenMaxAge    int           `mapstructure:"TOKEN_MAXAGE"`
+
+	// Access tokens are signed with the keys of TOKEN_KEYS_DIR (<kid>.pem files, RSA or Ed25519),
+	// TOKEN_SIGNING_KEY_ID picks the signing key when there are several private keys.
+	// Tokens are signed with TOKEN_SECRET if the directory isn't set
+	TokenKeysDir      string `mapstructure:"TOKEN_KEYS_DIR"`
+	TokenSigningKeyId string `mapstructure:"TOKEN_SIGNING_KEY_ID"`
+
+	// New passwords are at least PASSWORD_MIN_LENGTH characters (8 by default) and not in the list of
+	// common passwords, unless PASSWORD_ALLOW_COMMON is set. They are hashed with argon2id,
+	// changed parameters are applied to existing hashes on login
+	PasswordMinLength         int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordAllowCommon       bool   `mapstructure:"PASSWORD_ALLOW_COMMON"`
+	PasswordArgon2Memory      uint32 `mapstructure:"PASSWORD_ARGON2_MEMORY"` // KiB
+	PasswordArgon2Iterations  uint32 `mapstructure:"PASSWORD_ARGON2_ITERATIONS"`
+	PasswordArgon2Parallelism uint8  `mapstructure:"PASSWORD_ARGON2_PARALLELISM"`
+
+	RefreshTokenExpiresIn time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRED_IN"`
+	RevocationCacheTTL    time.Duration `mapstructure:"REVOCATION_CACHE_TTL"`
+
+	// Login is locked for LOGIN_LOCKOUT_DURATION after LOGIN_MAX_FAILURES failures,
+	// every client IP can make AUTH_RATE_LIMIT requests to /authentication per minute.
+	// COUNTER_STORE is "postgres" (shared by all instances) or "memory"
+	LoginMaxFailures     int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginLockoutDuration time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	AuthRateLimit        int           `mapstructure:"AUTH_RATE_LIMIT"`
+	CounterStore         string        `mapstructure:"COUNTER_STORE"`
+
+	// Comma-separated IPs or CIDRs of reverse proxies whose X-Forwarded-For header is trusted.
+	// Client IP of rate limits and the security log is the remote address if it's empty
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+
+	// Link in emails is APP_URL + path, e.g. https://pardo.app/verify-email?token=...
+	AppURL                     string        `mapstructure:"APP_URL"`
+	EmailVerificationExpiresIn time.Duration `mapstructure:"EMAIL_VERIFICATION_EXPIRED_IN"`
+	RequireVerifiedEmail       bool          `mapstructure:"REQUIRE_VERIFIED_EMAIL"` // blocks /vocab and /sets for unverified users
+	PasswordResetExpiresIn     time.Duration `mapstructure:"PASSWORD_RESET_EXPIRED_IN"`
+
+	// Deleted accounts can be restored during the grace period, then they are purged
+	AccountDeletionGracePeriod time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	AccountPurgeInterval       time.Duration `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+
+	// Comma-separated emails of existing users who get admin role on start
+	AdminEmails string `mapstructure:"ADMIN_EMAILS"`
+
+	// Comma-separated names of OpenID Connect providers, e.g. "google,gitlab". Every provider is
+	// configured with OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL and _SCOPES
+	OIDCProviderNames string                        `mapstructure:"OIDC_PROVIDERS"`
+	OIDCProviders     map[string]OIDCProviderConfig `mapstructure:"-"`
+
+	// Without SMTP_HOST emails are written to MAIL_DIR, or to the log with MAIL_LOG=true. Both are
+	// for development only: the log gets verification and password reset links
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailDir      string `mapstructure:"MAIL_DIR"`
+	MailLog      bool   `mapstructure:"MAIL_LOG"`
+}
+
+type OIDCProviderConfig struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectURL  string   // callback page of the frontend, registered at the provider
+	Scopes       []string // "openid email profile" if empty
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -35,5 +106,32 @@ func LoadConfig(path string) (config Config, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	config.OIDCProviders = loadOIDCProviders(config.OIDCProviderNames)
 	return
 }
+
+func loadOIDCProviders(names string) map[string]OIDCProviderConfig {
+	providers := map[string]OIDCProviderConfig{}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers[name] = OIDCProviderConfig{
+			Issuer:       viper.GetString(prefix + "ISSUER"),
+			ClientId:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:  viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(viper.GetString(prefix + "SCOPES")),
+		}
+	}
+
+	return providers
+}
@@ -0,0 +1,36 @@
+package request
+
+import "time"
+
+type AdminUsersRequest struct {
+	Search    string `form:"search"` // part of username or email
+	Role      string `form:"role"`
+	Suspended *bool  `form:"suspended"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+type SetRoleRequest struct {
+	AdminId int
+	UserId  int
+	Role    string     `validate:"required" json:"role"`
+	Client  ClientInfo `json:"-"`
+}
+
+// AdminUserRequest is an action of the admin on the user: suspend, unsuspend or delete
+type AdminUserRequest struct {
+	AdminId int
+	UserId  int
+	Client  ClientInfo `json:"-"`
+}
+
+// AdminSecurityEventsRequest filters the security log, user_id=0 finds events of unknown accounts
+type AdminSecurityEventsRequest struct {
+	UserId *int       `form:"user_id"`
+	Type   string     `form:"type"`
+	IP     string     `form:"ip"`
+	From   *time.Time `form:"from"` // RFC 3339
+	To     *time.Time `form:"to"`
+	Limit  int        `form:"limit"`
+	Offset int        `form:"offset"`
+}
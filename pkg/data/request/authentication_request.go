@@ -1,12 +1,84 @@
 package request
 
+// ClientInfo tells who sent the request, it's written to the security log
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	RequestId string
+}
+
 type CreateUserRequest struct {
-	Username string `validate:"required,min=2,max=100" json:"username"`
-	Email    string `validate:"required,min=2,max=100" json:"email"`
-	Password string `validate:"required,min=2,max=100" json:"password"`
+	Username string     `validate:"required,min=2,max=100" json:"username"`
+	Email    string     `validate:"required,min=2,max=100" json:"email"`
+	Password string     `validate:"required,max=100" json:"password"` // checked by the password policy
+	Client   ClientInfo `json:"-"`
 }
 
 type LoginRequest struct {
-	Email    string `validate:"required,max=200,min=2" json:"email"`
-	Password string `validate:"required,min=2,max=100" json:"password"`
+	Email    string     `validate:"required,max=200,min=2" json:"email"`
+	Password string     `validate:"required,min=2,max=100" json:"password"`
+	Device   string     `validate:"max=255" json:"device"` // name of the session, User-Agent by default
+	Client   ClientInfo `json:"-"`
+}
+
+type MFALoginRequest struct {
+	MFAToken string     `validate:"required" json:"mfa_token"`
+	Code     string     `validate:"required,max=20" json:"code"` // TOTP or recovery code
+	Client   ClientInfo `json:"-"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `validate:"required" json:"token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `validate:"required,max=200,min=2" json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string     `validate:"required" json:"token"`
+	Password string     `validate:"required,max=100" json:"password"` // checked by the password policy
+	Client   ClientInfo `json:"-"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string     `validate:"required" json:"refresh_token"`
+	Client       ClientInfo `json:"-"`
+}
+
+type LogoutRequest struct {
+	Token  string     // access token of the session to close
+	Client ClientInfo `json:"-"`
+}
+
+type RevokeSessionRequest struct {
+	UserId    int
+	SessionId string
+	Client    ClientInfo `json:"-"`
+}
+
+type LogoutEverywhereRequest struct {
+	UserId  int
+	AdminId int        // admin who closes sessions of the user, 0 if it's the user
+	Client  ClientInfo `json:"-"`
+}
+
+type OIDCStartRequest struct {
+	Provider string
+}
+
+type OIDCCallbackRequest struct {
+	Provider  string
+	Code      string     `validate:"required" json:"code"`
+	State     string     `validate:"required" json:"state"`
+	FlowToken string     `validate:"required" json:"flow_token"` // returned by start, kept by the client
+	Device    string     `validate:"max=255" json:"device"`
+	Client    ClientInfo `json:"-"`
+}
+
+type SecurityEventsRequest struct {
+	UserId int
+	Type   string `form:"type"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
 }
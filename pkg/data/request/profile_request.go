@@ -0,0 +1,66 @@
+package request
+
+type UpdateProfileRequest struct {
+	UserId   int
+	Username *string `validate:"omitempty,min=2,max=100" json:"username"`
+}
+
+type ChangePasswordRequest struct {
+	UserId          int
+	Token           string     // access token of the current session, which stays open
+	CurrentPassword string     `validate:"required,max=100" json:"current_password"`
+	NewPassword     string     `validate:"required,max=100" json:"new_password"` // checked by the password policy
+	Client          ClientInfo `json:"-"`
+}
+
+type ChangeEmailRequest struct {
+	UserId   int
+	Email    string `validate:"required,min=2,max=100" json:"email"`
+	Password string `validate:"required,max=100" json:"password"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `validate:"required" json:"token"`
+}
+
+type DeleteAccountRequest struct {
+	UserId   int
+	Password string     `validate:"required,max=100" json:"password"`
+	Client   ClientInfo `json:"-"`
+}
+
+type RestoreAccountRequest struct {
+	Email    string     `validate:"required,max=200,min=2" json:"email"`
+	Password string     `validate:"required,max=100" json:"password"`
+	Client   ClientInfo `json:"-"`
+}
+
+type EnrollTwoFactorRequest struct {
+	UserId   int
+	Password string `validate:"required,max=100" json:"password"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	UserId int
+	Code   string `validate:"required,max=20" json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	UserId   int
+	Password string `validate:"required,max=100" json:"password"`
+	Code     string `validate:"required,max=20" json:"code"` // TOTP or recovery code
+}
+
+type CreateAccessTokenRequest struct {
+	UserId        int
+	Name          string     `validate:"required,max=100" json:"name"`
+	Scopes        []string   `validate:"required,min=1" json:"scopes"`
+	ExpiresInDays int        `validate:"min=0,max=365" json:"expires_in_days"` // 90 days by default
+	Client        ClientInfo `json:"-"`
+}
+
+type RevokeAccessTokenRequest struct {
+	UserId  int
+	TokenId int
+	Client  ClientInfo `json:"-"`
+}
@@ -0,0 +1,40 @@
+package request
+
+type CreateSetRequest struct {
+	UserId      int
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type SetsRequest struct {
+	UserId int
+}
+
+type GetSetRequest struct {
+	UserId int
+	SetId  string
+}
+
+type UpdateSetRequest struct {
+	UserId      int
+	SetId       string
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type DeleteSetRequest struct {
+	UserId int
+	SetId  string
+}
+
+type AddWordToSetRequest struct {
+	UserId int
+	SetId  string
+	WordId int `json:"word_id"`
+}
+
+type RemoveWordFromSetRequest struct {
+	UserId int
+	SetId  string
+	WordId int
+}
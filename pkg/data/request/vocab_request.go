@@ -1,5 +1,10 @@
 package request
 
+import (
+	"io"
+	"time"
+)
+
 type CreateWordRequest struct {
 	UserId     int
 	Word       string `json:"word"`
@@ -17,6 +22,49 @@ type FindWordRequest struct {
 
 type VocabRequest struct {
 	UserId int
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`  // created_at, word or is_learned
+	Order  string `form:"order"` // asc or desc
+
+	IsLearned       *bool      `form:"is_learned"`
+	Cards           *bool      `form:"cards"`
+	WordTranslation *bool      `form:"word_translation"`
+	Constructor     *bool      `form:"constructor"`
+	WordAudio       *bool      `form:"word_audio"`
+	CreatedAfter    *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore   *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type SearchWordsRequest struct {
+	UserId int
+	Query  string `form:"q"`
+	Limit  int    `form:"limit"`
+}
+
+type ReviewWordRequest struct {
+	UserId         int
+	WordId         int
+	Quality        *int `json:"quality"`
+	ResponseTimeMs int  `json:"response_time_ms"`
+}
+
+type DueWordsRequest struct {
+	UserId int
+	Limit  int
+}
+
+// ExportWordsRequest exports all user's words when WordIds is nil
+type ExportWordsRequest struct {
+	UserId  int
+	WordIds []int
+}
+
+type HistoryRequest struct {
+	UserId int
+	WordId int
+	Limit  int
+	Offset int
 }
 
 type UpdateWordRequest struct {
@@ -30,6 +78,61 @@ type WordUpdate struct {
 }
 
 type FieldUpdate struct {
-	Field string      `json:"field"`
-	Value interface{} `json:"value"`
+	Field          string      `json:"field"`
+	Value          interface{} `json:"value"`
+	ResponseTimeMs int         `json:"response_time_ms,omitempty"` // only for training fields
+}
+
+type ImportWordsRequest struct {
+	UserId     int
+	Rows       []ImportRow
+	OnConflict string // skip, overwrite or merge
+	DryRun     bool
+}
+
+type ImportRow struct {
+	Line       int
+	Word       string
+	Definition string
+	Tags       string
+	Notes      string
+	Context    string
+	Source     string
+
+	// word was already studied elsewhere, see Word.ScheduleImported
+	IsLearned    bool
+	IntervalDays int
+}
+
+type ImportCSVRequest struct {
+	UserId           int       `form:"-"`
+	File             io.Reader `form:"-"`
+	Delimiter        string    `form:"delimiter"` // ",", ";", "|" or "tab"
+	HasHeader        *bool     `form:"has_header"`
+	WordColumn       string    `form:"word_column"` // header name or zero-based index
+	DefinitionColumn string    `form:"definition_column"`
+	TagsColumn       string    `form:"tags_column"`
+	NotesColumn      string    `form:"notes_column"`
+	OnConflict       string    `form:"on_conflict"`
+	DryRun           bool      `form:"dry_run"`
+}
+
+type ImportAnkiRequest struct {
+	UserId          int         `form:"-"`
+	File            io.ReaderAt `form:"-"` // .apkg is a zip archive, it's read at random offsets
+	Size            int64       `form:"-"`
+	WordField       string      `form:"word_field"`       // name of note field, first field by default
+	DefinitionField string      `form:"definition_field"` // second field by default
+	OnConflict      string      `form:"on_conflict"`
+	DryRun          bool        `form:"dry_run"`
+}
+
+type ImportKindleRequest struct {
+	UserId     int        `form:"-"`
+	File       io.Reader  `form:"-"`
+	Book       string     `form:"book"` // id, ASIN or title of the book
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	OnConflict string     `form:"on_conflict"`
+	DryRun     bool       `form:"dry_run"`
 }
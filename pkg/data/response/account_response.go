@@ -0,0 +1,13 @@
+package response
+
+type AccountImportReport struct {
+	UsernameRestored bool     `json:"username_restored"`
+	WordsCreated     int      `json:"words_created"`
+	WordsSkipped     int      `json:"words_skipped"` // already in user's vocab
+	WordsFailed      int      `json:"words_failed"`
+	AttemptsCreated  int      `json:"attempts_created"`
+	SetsCreated      int      `json:"sets_created"`
+	SetsSkipped      int      `json:"sets_skipped"` // user has a set with the same name
+	SetsFailed       int      `json:"sets_failed"`
+	Errors           []string `json:"errors"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type AdminUserResponse struct {
+	UserResponse
+	SuspendedAt  *time.Time `json:"suspended_at"`
+	WordsCount   int64      `json:"words_count"`
+	LearnedCount int64      `json:"learned_count"`
+}
+
+type AdminUsersResponse struct {
+	Users  []AdminUserResponse `json:"users"`
+	Total  int64               `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
@@ -1,11 +1,102 @@
 package response
 
+import "time"
+
 type LoginResponse struct {
-	TokenType string `json:"token_type"`
-	Token     string `json:"token"`
+	TokenType string `json:"token_type,omitempty"`
+	Token     string `json:"token,omitempty"`
+	ExpiresIn int64  `json:"expires_in"` // lifetime of the access token or the mfa challenge in seconds
+
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// Account with 2FA gets the challenge instead of tokens, which is exchanged for them with a code
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+type SessionResponse struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	StartedAt  time.Time `json:"started_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
 type UserResponse struct {
-	Email    string `json:"email"`
-	Username string `json:"username"`
+	Id         int       `json:"id"`
+	Email      string    `json:"email"`
+	Username   string    `json:"username"`
+	IsVerified bool      `json:"is_verified"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type AccountDeletionResponse struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"` // account can be restored until this time
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"` // shown as QR code
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // shown once, only hashes are stored
+}
+
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"` // the user is redirected there
+	FlowToken        string `json:"flow_token"`        // sent back with the code from the callback
+}
+
+type AccessTokenResponse struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"` // last characters of the token
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+type AccessTokenCreatedResponse struct {
+	AccessTokenResponse
+	Token string `json:"token"` // shown once, only the hash is stored
+}
+
+// JSONWebKey is a public key for verifying access tokens, RSA keys have N and E, Ed25519 keys have X
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSResponse struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type SecurityEventResponse struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	ActorId   int       `json:"actor_id,omitempty"` // admin who did it
+	Type      string    `json:"type"`
+	Details   string    `json:"details"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	RequestId string    `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SecurityEventsResponse struct {
+	Events []SecurityEventResponse `json:"events"`
+	Total  int64                   `json:"total"`
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
 }
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type SetResponse struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Words       []int     `json:"words"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -7,9 +7,86 @@ type VocabResponse struct {
 	Word            string    `json:"word"`
 	Definition      string    `json:"definition"`
 	CreatedAt       time.Time `json:"created_at"`
+	Tags            string    `json:"tags"`
+	Notes           string    `json:"notes"`
+	Context         string    `json:"context"`
+	Source          string    `json:"source"`
 	IsLearned       bool      `json:"is_learned"`
 	Cards           bool      `json:"cards"`
 	WordTranslation bool      `json:"word_translation"`
 	Constructor     bool      `json:"constructor"`
 	WordAudio       bool      `json:"word_audio"`
+	EaseFactor      float64   `json:"ease_factor"`
+	IntervalDays    int       `json:"interval_days"`
+	Repetitions     int       `json:"repetitions"`
+	DueAt           time.Time `json:"due_at"`
+}
+
+type VocabPageResponse struct {
+	Words      []VocabResponse `json:"words"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+type AttemptResponse struct {
+	Id             int       `json:"id"`
+	WordId         int       `json:"word_id"`
+	Exercise       string    `json:"exercise"`
+	IsCorrect      bool      `json:"is_correct"`
+	ResponseTimeMs int       `json:"response_time_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type HistoryResponse struct {
+	Attempts []AttemptResponse `json:"attempts"`
+	Total    int64             `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Created    int               `json:"created"`
+	Updated    int               `json:"updated"`
+	Skipped    int               `json:"skipped"`
+	Failed     int               `json:"failed"`
+	Errors     []ImportRowError  `json:"errors"`
+	Duplicates []ImportDuplicate `json:"duplicates"`
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Word    string `json:"word"`
+	Message string `json:"message"`
+}
+
+type ImportDuplicate struct {
+	Line       int    `json:"line"`
+	Word       string `json:"word"`
+	Resolution string `json:"resolution"` // skipped, overwritten or merged
+}
+
+type AnkiImportReport struct {
+	ImportReport
+	Media []ImportMedia `json:"media"`
+}
+
+// ImportMedia is a media file from the imported deck, it is not saved but can be attached to the words later
+type ImportMedia struct {
+	File  string   `json:"file"`
+	Words []string `json:"words"` // words which reference the file
+}
+
+type KindleImportReport struct {
+	ImportReport
+	Books []KindleBook `json:"books"`
+}
+
+type KindleBook struct {
+	Id      string `json:"id"`
+	Asin    string `json:"asin"`
+	Title   string `json:"title"`
+	Authors string `json:"authors"`
+	Lookups int    `json:"lookups"`
 }
@@ -0,0 +1,236 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	accountDomain "mono_pardo/internal/domain/account"
+	setsDomain "mono_pardo/internal/domain/sets"
+	usersDomain "mono_pardo/internal/domain/users"
+	wordsDomain "mono_pardo/internal/domain/words"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestDeleteAccount(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	testConfig.LoginMaxFailures = 3
+
+	env.RunMigrations(t)
+	mongoDb := env.SetupMongo(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	userFixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "reader", Email: "reader@example.com", Password: hashedPassword},
+			{Username: "other", Email: "other@example.com", Password: hashedPassword},
+		},
+	}
+	cleanupUsers := env.WithFixture(t, userFixture)
+	defer cleanupUsers()
+
+	userId := userFixture.Users[0].Id
+	otherId := userFixture.Users[1].Id
+
+	wordFixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: userId, Word: "whale", Definition: "sea animal"},
+			{UserId: otherId, Word: "harpoon", Definition: "a spear"},
+		},
+	}
+	cleanupWords := env.WithFixture(t, wordFixture)
+	defer cleanupWords()
+
+	attemptFixture := &tests.AttemptFixture{
+		Attempts: []wordsDomain.TrainingAttempt{
+			{UserId: userId, WordId: wordFixture.Words[0].Id, Exercise: "cards", IsCorrect: true},
+		},
+	}
+	cleanupAttempts := env.WithFixture(t, attemptFixture)
+	defer cleanupAttempts()
+
+	setFixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: userId, Name: "sea", Words: []int{wordFixture.Words[0].Id}},
+			{UserId: otherId, Name: "tools", Words: []int{wordFixture.Words[1].Id}},
+		},
+	}
+	cleanupSets := env.WithMongoFixture(t, setFixture)
+	defer cleanupSets()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	accountService := accountDomain.NewServiceImpl(deps.Repository, wordRepository, setsRepository)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/restore", profileController.RestoreAccount)
+	meGroup := router.Group("/api/v1/me", authMiddleware.Handle())
+	meGroup.GET("", profileController.GetProfile)
+	meGroup.DELETE("", profileController.DeleteAccount)
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func() (int, resp.LoginResponse) {
+		w := send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: "reader@example.com", Password: "test_password"})
+
+		var tokens resp.LoginResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		}
+		return w.Code, tokens
+	}
+
+	deleteAccount := func(token string) {
+		w := send("DELETE", "/api/v1/me", token, request.DeleteAccountRequest{Password: "test_password"})
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		var deletion resp.AccountDeletionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deletion))
+		assert.Equal(t, usersDomain.DefaultDeletionGracePeriod, deletion.PurgeAt.Sub(deletion.DeletedAt))
+	}
+
+	countSets := func(userId int) int64 {
+		count, err := mongoDb.DB.Collection("sets").CountDocuments(context.Background(), bson.M{"user_id": userId})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Re-authentication Required", func(t *testing.T) {
+		_, session := login()
+
+		w := send("DELETE", "/api/v1/me", session.Token, request.DeleteAccountRequest{Password: "wrong_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("DELETE", "/api/v1/me", session.Token, nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("GET", "/api/v1/me", session.Token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Soft Delete And Restore", func(t *testing.T) {
+		_, session := login()
+		deleteAccount(session.Token)
+
+		// sessions are closed and the account is hidden
+		w := send("GET", "/api/v1/me", session.Token, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		code, _ := login()
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		// data is kept during the grace period
+		assert.Equal(t, int64(1), countSets(userId))
+
+		w = send("POST", "/api/v1/authentication/restore", "", request.RestoreAccountRequest{Email: "reader@example.com", Password: "wrong_password"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = send("POST", "/api/v1/authentication/restore", "", request.RestoreAccountRequest{Email: "reader@example.com", Password: "test_password"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, _ = login()
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Restore Shares Lockout With Login", func(t *testing.T) {
+		_, session := login()
+		deleteAccount(session.Token)
+
+		restore := func(password string) int {
+			return send("POST", "/api/v1/authentication/restore", "", request.RestoreAccountRequest{Email: "reader@example.com", Password: password}).Code
+		}
+
+		assert.Equal(t, http.StatusNotFound, restore("wrong_password"))
+		assert.Equal(t, http.StatusNotFound, restore("wrong_password"))
+		assert.Equal(t, http.StatusLocked, restore("wrong_password"))
+
+		// the right password doesn't help until the lockout is over
+		assert.Equal(t, http.StatusLocked, restore("test_password"))
+		code, _ := login()
+		assert.Equal(t, http.StatusLocked, code)
+
+		for _, key := range usersDomain.CounterKeys(userFixture.Users[0]) {
+			require.NoError(t, deps.CounterStore.Reset(key))
+		}
+		assert.Equal(t, http.StatusOK, restore("test_password"))
+	})
+
+	t.Run("Purge After Grace Period", func(t *testing.T) {
+		_, session := login()
+		deleteAccount(session.Token)
+
+		counterKeys := usersDomain.CounterKeys(userFixture.Users[0])
+		for _, key := range counterKeys {
+			require.NoError(t, env.DB.DB.Create(&usersDomain.Counter{Key: key, Count: 1, ResetAt: time.Now().Add(time.Hour)}).Error)
+		}
+
+		// grace period isn't over yet
+		purged, err := accountService.PurgeDeletedAccounts(time.Now().UTC().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, purged)
+
+		purged, err = accountService.PurgeDeletedAccounts(time.Now().UTC().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		var count int64
+		env.DB.DB.Unscoped().Model(&usersDomain.User{}).Where("id = ?", userId).Count(&count)
+		assert.Equal(t, int64(0), count)
+		env.DB.DB.Model(&wordsDomain.Word{}).Where("user_id = ?", userId).Count(&count)
+		assert.Equal(t, int64(0), count)
+		env.DB.DB.Model(&wordsDomain.TrainingAttempt{}).Where("user_id = ?", userId).Count(&count)
+		assert.Equal(t, int64(0), count)
+		env.DB.DB.Model(&usersDomain.RefreshToken{}).Where("user_id = ?", userId).Count(&count)
+		assert.Equal(t, int64(0), count)
+		env.DB.DB.Model(&usersDomain.SecurityEvent{}).Where("user_id = ?", userId).Count(&count)
+		assert.Equal(t, int64(0), count)
+		env.DB.DB.Model(&usersDomain.Counter{}).Where("key IN ?", counterKeys).Count(&count)
+		assert.Equal(t, int64(0), count)
+		assert.Equal(t, int64(0), countSets(userId))
+
+		// restore isn't possible anymore
+		w := send("POST", "/api/v1/authentication/restore", "", request.RestoreAccountRequest{Email: "reader@example.com", Password: "test_password"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		// other users are untouched
+		env.DB.DB.Model(&wordsDomain.Word{}).Where("user_id = ?", otherId).Count(&count)
+		assert.Equal(t, int64(1), count)
+		assert.Equal(t, int64(1), countSets(otherId))
+	})
+}
@@ -0,0 +1,206 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	accountDomain "mono_pardo/internal/domain/account"
+	setsDomain "mono_pardo/internal/domain/sets"
+	usersDomain "mono_pardo/internal/domain/users"
+	wordsDomain "mono_pardo/internal/domain/words"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	usersInfra "mono_pardo/internal/infrastructure/users"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestExportImportAccount(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "other-token").Return(2, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	userFixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Id: 1, Username: "reader", Email: "reader@example.com", Password: "hash"},
+			{Id: 2, Username: "other", Email: "other@example.com", Password: "hash"},
+		},
+	}
+	cleanupUsers := env.WithFixture(t, userFixture)
+	defer cleanupUsers()
+
+	dueAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	wordFixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "whale", Definition: "sea animal", Tags: "sea", Context: "all whales", Source: "Moby Dick",
+				IsLearned: true, Cards: true, WordTranslation: true, Constructor: true, WordAudio: true,
+				EaseFactor: 2.7, IntervalDays: 12, Repetitions: 3, DueAt: dueAt},
+			{UserId: 1, Word: "harpoon", Definition: "a spear"},
+			{UserId: 2, Word: "harpoon", Definition: "existing definition"},
+		},
+	}
+	cleanupWords := env.WithFixture(t, wordFixture)
+	defer cleanupWords()
+
+	attemptFixture := &tests.AttemptFixture{
+		Attempts: []wordsDomain.TrainingAttempt{
+			{UserId: 1, WordId: wordFixture.Words[0].Id, Exercise: "cards", IsCorrect: true, ResponseTimeMs: 900},
+			{UserId: 1, WordId: wordFixture.Words[0].Id, Exercise: wordsDomain.ReviewExercise, IsCorrect: false},
+		},
+	}
+	cleanupAttempts := env.WithFixture(t, attemptFixture)
+	defer cleanupAttempts()
+
+	setFixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "sea", Description: "sea words", Words: []int{wordFixture.Words[0].Id, wordFixture.Words[1].Id}},
+		},
+	}
+	cleanupSets := env.WithMongoFixture(t, setFixture)
+	defer cleanupSets()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	accountService := accountDomain.NewServiceImpl(usersInfra.NewPostgresRepositoryImpl(env.DB.DB), wordRepository, setsRepository)
+	accountController := controller.NewAccountController(accountService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	meGroup := router.Group("/api/v1/me")
+	meGroup.Use(authMiddleware.Handle())
+	meGroup.GET("/export", accountController.Export)
+	meGroup.POST("/import", accountController.Import)
+
+	importArchive := func(t *testing.T, token string, body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/me/import", bytes.NewBuffer(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var exported []byte
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/me/export", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Success Export", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/me/export", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+		exported = w.Body.Bytes()
+
+		var archive accountDomain.Archive
+		require.NoError(t, json.Unmarshal(exported, &archive))
+		assert.Equal(t, accountDomain.ArchiveVersion, archive.Version)
+		assert.Equal(t, "reader@example.com", archive.Profile.Email)
+		require.Len(t, archive.Words, 2)
+		require.Len(t, archive.Sets, 1)
+
+		var whale accountDomain.ArchiveWord
+		for _, word := range archive.Words {
+			if word.Word == "whale" {
+				whale = word
+			}
+		}
+		assert.True(t, whale.IsLearned)
+		assert.Equal(t, 12, whale.IntervalDays)
+		assert.Equal(t, "Moby Dick", whale.Source)
+		assert.Len(t, whale.Attempts, 2)
+		assert.ElementsMatch(t, []int{wordFixture.Words[0].Id, wordFixture.Words[1].Id}, archive.Sets[0].Words)
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		w := importArchive(t, "other-token", []byte(`{"version": 99, "words": [], "sets": []}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Success Import into Existing Account", func(t *testing.T) {
+		require.NotNil(t, exported)
+
+		w := importArchive(t, "other-token", exported)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report resp.AccountImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 1, report.WordsCreated)
+		assert.Equal(t, 1, report.WordsSkipped)
+		assert.Equal(t, 2, report.AttemptsCreated)
+		assert.Equal(t, 1, report.SetsCreated)
+		assert.True(t, report.UsernameRestored)
+
+		var user usersDomain.User
+		env.DB.DB.First(&user, 2)
+		assert.Equal(t, "reader", user.Username)
+		assert.Equal(t, "other@example.com", user.Email)
+
+		var whale, harpoon wordsDomain.Word
+		env.DB.DB.Where("user_id = ? AND word = ?", 2, "whale").First(&whale)
+		env.DB.DB.Where("user_id = ? AND word = ?", 2, "harpoon").First(&harpoon)
+		assert.NotEqual(t, wordFixture.Words[0].Id, whale.Id)
+		assert.True(t, whale.IsLearned)
+		assert.InDelta(t, 2.7, whale.EaseFactor, 0.0001)
+		assert.Equal(t, 3, whale.Repetitions)
+		assert.True(t, dueAt.Equal(whale.DueAt))
+		assert.Equal(t, "existing definition", harpoon.Definition)
+
+		var attempts int64
+		env.DB.DB.Model(&wordsDomain.TrainingAttempt{}).Where("user_id = ? AND word_id = ?", 2, whale.Id).Count(&attempts)
+		assert.Equal(t, int64(2), attempts)
+
+		userSets, err := setsRepository.FindByUserId(2)
+		require.NoError(t, err)
+		require.Len(t, userSets, 1)
+		assert.Equal(t, "sea", userSets[0].Name)
+		assert.ElementsMatch(t, []int{whale.Id, harpoon.Id}, userSets[0].Words)
+
+		for _, set := range userSets {
+			assert.NoError(t, setsRepository.Delete(set.Id))
+		}
+		env.DB.DB.Where("user_id = ?", 2).Delete(&wordsDomain.TrainingAttempt{})
+		env.DB.DB.Delete(&whale)
+	})
+
+	t.Run("Repeated Import Skips Existing Data", func(t *testing.T) {
+		w := importArchive(t, "test-token", exported)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report resp.AccountImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 0, report.WordsCreated)
+		assert.Equal(t, 2, report.WordsSkipped)
+		assert.Equal(t, 1, report.SetsSkipped)
+	})
+}
@@ -0,0 +1,291 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	adminDomain "mono_pardo/internal/domain/admin"
+	usersDomain "mono_pardo/internal/domain/users"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestAdminUsers(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	userFixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "support", Email: "admin@pardo.app", Password: hashedPassword},
+			{Username: "reader", Email: "reader@example.com", Password: hashedPassword},
+			{Username: "teacher", Email: "teacher@school.org", Password: hashedPassword, Role: usersDomain.RoleTeacher},
+		},
+	}
+	cleanupUsers := env.WithFixture(t, userFixture)
+	defer cleanupUsers()
+
+	adminId := userFixture.Users[0].Id
+	readerId := userFixture.Users[1].Id
+
+	wordFixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: readerId, Word: "whale", Definition: "sea animal", IsLearned: true},
+			{UserId: readerId, Word: "harpoon", Definition: "a spear"},
+		},
+	}
+	cleanupWords := env.WithFixture(t, wordFixture)
+	defer cleanupWords()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	require.NoError(t, usersDomain.BootstrapAdmins(deps.Repository, []string{" admin@pardo.app", ""}))
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	adminService := adminDomain.NewServiceImpl(deps.Repository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB), deps.SecurityEventRepository, authenticationService)
+	adminController := controller.NewAdminController(adminService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+	roleMiddleware := middleware.NewRoleMiddleware(authenticationService)
+
+	router := env.Router
+	router.POST("/api/v1/authentication/login", authenticationController.Login)
+	router.GET("/api/v1/me", authMiddleware.Handle(), profileController.GetProfile)
+	adminGroup := router.Group("/api/v1/admin", authMiddleware.Handle(), roleMiddleware.Require(usersDomain.RoleAdmin))
+	adminGroup.GET("/users", adminController.ListUsers)
+	adminGroup.GET("/users/:userId", adminController.GetUser)
+	adminGroup.PATCH("/users/:userId/role", adminController.SetRole)
+	adminGroup.POST("/users/:userId/suspend", adminController.SuspendUser)
+	adminGroup.POST("/users/:userId/unsuspend", adminController.UnsuspendUser)
+	adminGroup.DELETE("/users/:userId", adminController.DeleteUser)
+	adminGroup.GET("/security-events", adminController.ListSecurityEvents)
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		var body *bytes.Buffer
+		if payload != nil {
+			jsonData, _ := json.Marshal(payload)
+			body = bytes.NewBuffer(jsonData)
+		} else {
+			body = &bytes.Buffer{}
+		}
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func(email string) (int, string) {
+		w := send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: email, Password: "test_password"})
+
+		var tokens response.LoginResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		}
+		return w.Code, tokens.Token
+	}
+
+	listUsers := func(token, query string) (int, response.AdminUsersResponse) {
+		w := send("GET", "/api/v1/admin/users"+query, token, nil)
+
+		var users response.AdminUsersResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+		}
+		return w.Code, users
+	}
+
+	_, adminToken := login("admin@pardo.app")
+	_, readerToken := login("reader@example.com")
+
+	t.Run("Role Is In Token", func(t *testing.T) {
+		claims, err := utils.ValidateToken(adminToken, deps.Keys)
+		require.NoError(t, err)
+		assert.Equal(t, usersDomain.RoleAdmin, claims.Role)
+
+		w := send("GET", "/api/v1/me", readerToken, nil)
+		var profile response.UserResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+		assert.Equal(t, usersDomain.RoleUser, profile.Role)
+	})
+
+	t.Run("Only Admins", func(t *testing.T) {
+		code, _ := listUsers("", "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, _ = listUsers(readerToken, "")
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("List And Search", func(t *testing.T) {
+		code, users := listUsers(adminToken, "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(3), users.Total)
+		assert.Len(t, users.Users, 3)
+
+		code, users = listUsers(adminToken, "?search=EXAMPLE")
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, users.Users, 1)
+		assert.Equal(t, "reader", users.Users[0].Username)
+		assert.Equal(t, int64(2), users.Users[0].WordsCount)
+		assert.Equal(t, int64(1), users.Users[0].LearnedCount)
+
+		code, users = listUsers(adminToken, "?role=teacher")
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, users.Users, 1)
+		assert.Equal(t, "teacher", users.Users[0].Username)
+
+		code, users = listUsers(adminToken, "?limit=1&offset=1")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(3), users.Total)
+		assert.Len(t, users.Users, 1)
+
+		code, _ = listUsers(adminToken, "?role=owner")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("Get User", func(t *testing.T) {
+		w := send("GET", fmt.Sprintf("/api/v1/admin/users/%d", readerId), adminToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var user response.AdminUserResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+		assert.Equal(t, "reader@example.com", user.Email)
+		assert.Equal(t, int64(2), user.WordsCount)
+		assert.Nil(t, user.SuspendedAt)
+
+		w = send("GET", "/api/v1/admin/users/999999", adminToken, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Suspend And Unsuspend", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/admin/users/%d", readerId)
+
+		w := send("POST", path+"/suspend", adminToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// sessions are closed and login is blocked
+		w = send("GET", "/api/v1/me", readerToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		code, _ := login("reader@example.com")
+		assert.Equal(t, http.StatusForbidden, code)
+
+		_, users := listUsers(adminToken, "?suspended=true")
+		require.Len(t, users.Users, 1)
+		assert.NotNil(t, users.Users[0].SuspendedAt)
+
+		w = send("POST", path+"/unsuspend", adminToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, readerToken = login("reader@example.com")
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Change Role", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/admin/users/%d/role", readerId)
+
+		w := send("PATCH", path, adminToken, request.SetRoleRequest{Role: "owner"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("PATCH", path, adminToken, request.SetRoleRequest{Role: usersDomain.RoleTeacher})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// token with the old role doesn't work anymore
+		w = send("GET", "/api/v1/me", readerToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		_, readerToken = login("reader@example.com")
+		claims, err := utils.ValidateToken(readerToken, deps.Keys)
+		require.NoError(t, err)
+		assert.Equal(t, usersDomain.RoleTeacher, claims.Role)
+	})
+
+	t.Run("Admin Can't Act On Themselves", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/admin/users/%d", adminId)
+
+		w := send("PATCH", path+"/role", adminToken, request.SetRoleRequest{Role: usersDomain.RoleUser})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send("POST", path+"/suspend", adminToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send("DELETE", path, adminToken, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Delete User", func(t *testing.T) {
+		w := send("DELETE", fmt.Sprintf("/api/v1/admin/users/%d", readerId), adminToken, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, _ := login("reader@example.com")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		_, users := listUsers(adminToken, "")
+		assert.Equal(t, int64(2), users.Total)
+	})
+
+	t.Run("Security Events", func(t *testing.T) {
+		listEvents := func(token, query string) (int, response.SecurityEventsResponse) {
+			w := send("GET", "/api/v1/admin/security-events"+query, token, nil)
+
+			var events response.SecurityEventsResponse
+			if w.Code == http.StatusOK {
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+			}
+			return w.Code, events
+		}
+
+		code, _ := listEvents(readerToken, "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, events := listEvents(adminToken, fmt.Sprintf("?user_id=%d&type=%s", readerId, usersDomain.EventRoleChanged))
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events.Events, 1)
+		assert.Equal(t, adminId, events.Events[0].ActorId)
+		assert.Equal(t, "user -> teacher", events.Events[0].Details)
+
+		for _, eventType := range []string{usersDomain.EventSuspended, usersDomain.EventUnsuspended} {
+			code, events = listEvents(adminToken, fmt.Sprintf("?user_id=%d&type=%s", readerId, eventType))
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, events.Events, 1)
+			assert.Equal(t, adminId, events.Events[0].ActorId)
+		}
+
+		code, events = listEvents(adminToken, fmt.Sprintf("?user_id=%d", readerId))
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, usersDomain.EventAccountDeleted, events.Events[0].Type)
+
+		// login of the deleted account, which is unknown now
+		code, events = listEvents(adminToken, "?user_id=0&type=login_failed")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, events.Events)
+		assert.Contains(t, events.Events[0].Details, "reader@example.com")
+
+		code, events = listEvents(adminToken, "?from=2999-01-01T00:00:00Z")
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, events.Events)
+
+		code, _ = listEvents(adminToken, "?type=unknown")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		code, _ = listEvents(adminToken, "?limit=1000")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+}
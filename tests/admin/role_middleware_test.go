@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/tests"
+)
+
+func TestRoleMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "admin-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "teacher-token").Return(2, nil)
+	mockAuthService.On("GetUserId", "user-token").Return(3, nil)
+	mockAuthService.On("GetRole", "admin-token").Return(usersDomain.RoleAdmin, nil)
+	mockAuthService.On("GetRole", "teacher-token").Return(usersDomain.RoleTeacher, nil)
+	mockAuthService.On("GetRole", "user-token").Return(usersDomain.RoleUser, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+	roleMiddleware := middleware.NewRoleMiddleware(mockAuthService)
+
+	router := gin.New()
+	router.GET("/admin", authMiddleware.Handle(), roleMiddleware.Require(usersDomain.RoleAdmin), func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString("role"))
+	})
+	router.GET("/classes", authMiddleware.Handle(), roleMiddleware.Require(usersDomain.RoleTeacher, usersDomain.RoleAdmin), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	call := func(path, token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call("/admin", ""))
+	assert.Equal(t, http.StatusOK, call("/admin", "admin-token"))
+	assert.Equal(t, http.StatusForbidden, call("/admin", "teacher-token"))
+	assert.Equal(t, http.StatusForbidden, call("/admin", "user-token"))
+
+	assert.Equal(t, http.StatusOK, call("/classes", "admin-token"))
+	assert.Equal(t, http.StatusOK, call("/classes", "teacher-token"))
+	assert.Equal(t, http.StatusForbidden, call("/classes", "user-token"))
+}
@@ -0,0 +1,50 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// openArchive unpacks .apkg and opens its collection, returns archive entries by name
+func openArchive(t *testing.T, archive []byte) (map[string][]byte, *sql.DB) {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+
+	entries := map[string][]byte{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.Name, err)
+		}
+		entries[file.Name] = data
+	}
+
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	if err = os.WriteFile(path, entries["collection.anki2"], 0o600); err != nil {
+		t.Fatalf("Failed to write collection: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to open collection: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return entries, db
+}
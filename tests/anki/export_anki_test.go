@@ -0,0 +1,130 @@
+package anki
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	wordsDomain "mono_pardo/internal/domain/words"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/tests"
+)
+
+func TestExportAnki(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	wordFixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "suitcase", Definition: "a case for clothes", IsLearned: true, Tags: "trip"},
+			{UserId: 1, Word: "ticket", Definition: "a pass"},
+			{UserId: 1, Word: "desk", Definition: "a table for work"},
+			{UserId: 2, Word: "secret", Definition: "word of other user"},
+		},
+	}
+	cleanup := env.WithFixture(t, wordFixture)
+	defer cleanup()
+
+	setFixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Words: []int{wordFixture.Words[0].Id, wordFixture.Words[1].Id, wordFixture.Words[3].Id}},
+			{UserId: 2, Name: "other"},
+		},
+	}
+	mongoCleanup := env.WithMongoFixture(t, setFixture)
+	defer mongoCleanup()
+
+	validate := validator.New()
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	setsService := setsDomain.NewServiceImpl(validate, setsInfra.NewMongoRepositoryImpl(mongoDb.DB), wordRepository)
+	exportController := controller.NewExportController(vocabService, setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	router.GET("/api/v1/vocab/export/anki", authMiddleware.Handle(), exportController.ExportVocabAnki)
+	router.GET("/api/v1/sets/:setId/export/anki", authMiddleware.Handle(), exportController.ExportSetAnki)
+
+	exportedWords := func(t *testing.T, body []byte) map[string]string {
+		_, db := openArchive(t, body)
+
+		rows, err := db.Query("SELECT sfld, tags FROM notes")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		words := map[string]string{}
+		for rows.Next() {
+			var word, tags string
+			require.NoError(t, rows.Scan(&word, &tags))
+			words[word] = tags
+		}
+		return words
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/export/anki", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Success Export Vocab", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/export/anki", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "vocabulary.apkg")
+		assert.Equal(t, map[string]string{
+			"suitcase": " learned trip ",
+			"ticket":   " learning ",
+			"desk":     " learning ",
+		}, exportedWords(t, w.Body.Bytes()))
+	})
+
+	t.Run("Success Export Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/"+setFixture.Sets[0].Id+"/export/anki", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// word of other user is in the set, but is not exported
+		assert.Equal(t, map[string]string{
+			"suitcase": " learned trip ",
+			"ticket":   " learning ",
+		}, exportedWords(t, w.Body.Bytes()))
+	})
+
+	t.Run("Attempt to Export Other User's Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/"+setFixture.Sets[1].Id+"/export/anki", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,103 @@
+package anki
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/infrastructure/anki"
+)
+
+func TestExportArchive(t *testing.T) {
+	archive, err := anki.Export("travel", []anki.Note{
+		{Id: 7, Word: "suitcase", Definition: "a case for\ncarrying clothes", Tags: []string{anki.LearnedTag, "trip"}},
+		{Id: 8, Word: "<boarding> pass", Definition: "a ticket", Tags: []string{anki.LearningTag}},
+	})
+	require.NoError(t, err)
+
+	entries, db := openArchive(t, archive)
+
+	t.Run("Archive Entries", func(t *testing.T) {
+		assert.Len(t, entries, 2)
+		assert.Contains(t, entries, anki.CollectionFile)
+		assert.Equal(t, "{}", string(entries[anki.MediaFile]))
+	})
+
+	t.Run("Collection", func(t *testing.T) {
+		var ver int
+		var models, decks string
+		err := db.QueryRow("SELECT ver, models, decks FROM col").Scan(&ver, &models, &decks)
+		require.NoError(t, err)
+		assert.Equal(t, 11, ver)
+
+		var parsedModels map[string]struct {
+			Name string `json:"name"`
+			Flds []struct {
+				Name string `json:"name"`
+			} `json:"flds"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(models), &parsedModels))
+		require.Len(t, parsedModels, 1)
+		for _, model := range parsedModels {
+			assert.Equal(t, "Pardo Word", model.Name)
+			require.Len(t, model.Flds, 2)
+			assert.Equal(t, "Word", model.Flds[0].Name)
+			assert.Equal(t, "Definition", model.Flds[1].Name)
+		}
+
+		var parsedDecks map[string]struct {
+			Name string `json:"name"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(decks), &parsedDecks))
+		names := []string{}
+		for _, deck := range parsedDecks {
+			names = append(names, deck.Name)
+		}
+		assert.ElementsMatch(t, []string{"Default", "travel"}, names)
+	})
+
+	t.Run("Notes", func(t *testing.T) {
+		rows, err := db.Query("SELECT guid, tags, flds, sfld, csum FROM notes ORDER BY id")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		type note struct {
+			guid, tags, flds, sfld string
+			csum                   int64
+		}
+		notes := []note{}
+		for rows.Next() {
+			var n note
+			require.NoError(t, rows.Scan(&n.guid, &n.tags, &n.flds, &n.sfld, &n.csum))
+			notes = append(notes, n)
+		}
+		require.Len(t, notes, 2)
+
+		assert.Equal(t, "pardo-7", notes[0].guid)
+		assert.Equal(t, " learned trip ", notes[0].tags)
+		assert.Equal(t, []string{"suitcase", "a case for<br>carrying clothes"}, strings.Split(notes[0].flds, "\x1f"))
+		assert.Equal(t, "suitcase", notes[0].sfld)
+		// first 8 hex digits of sha1("suitcase")
+		assert.Equal(t, int64(0xb6d19848), notes[0].csum)
+
+		assert.Equal(t, " learning ", notes[1].tags)
+		assert.Equal(t, "&lt;boarding&gt; pass", strings.Split(notes[1].flds, "\x1f")[0])
+		assert.Equal(t, "<boarding> pass", notes[1].sfld)
+	})
+
+	t.Run("Cards", func(t *testing.T) {
+		var count, newCards int
+		err := db.QueryRow("SELECT COUNT(*), SUM(CASE WHEN type = 0 AND queue = 0 THEN 1 ELSE 0 END) FROM cards").Scan(&count, &newCards)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, newCards)
+
+		var orphans int
+		err = db.QueryRow("SELECT COUNT(*) FROM cards WHERE nid NOT IN (SELECT id FROM notes)").Scan(&orphans)
+		require.NoError(t, err)
+		assert.Equal(t, 0, orphans)
+	})
+}
@@ -0,0 +1,161 @@
+package anki
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	"mono_pardo/internal/infrastructure/anki"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestImportAnki(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "ticket", Definition: "a pass"},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	importService := wordsDomain.NewImportServiceImpl(vocabService, wordsInfra.NewFileParserImpl())
+	importController := controller.NewImportController(importService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.POST("/import/anki", importController.ImportAnki)
+
+	deck := buildPackage(t, anki.CollectionFile,
+		[]testNote{
+			{id: 1, model: 100, tags: " travel ", fields: "suitcase\x1fa case for clothes"},
+			{id: 2, model: 100, fields: "ticket\x1fa pass [sound:ticket.mp3]"},
+			{id: 3, model: 100, fields: "desk\x1fa table"},
+			{id: 4, model: 100, fields: "\x1fno word"},
+		},
+		[]testCard{
+			{note: 1, cardTyp: 2, ivl: 30},
+			{note: 3, cardTyp: 2, ivl: 2},
+		},
+		`{"0": "ticket.mp3"}`,
+	)
+
+	importFile := func(t *testing.T, content []byte, fields map[string]string) (int, resp.AnkiImportReport) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for key, value := range fields {
+			assert.NoError(t, writer.WriteField(key, value))
+		}
+		if content != nil {
+			part, err := writer.CreateFormFile("file", "deck.apkg")
+			assert.NoError(t, err)
+			_, err = part.Write(content)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.Close())
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/anki", body)
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(w, req)
+
+		var report resp.AnkiImportReport
+		if w.Code == http.StatusOK {
+			err := json.Unmarshal(w.Body.Bytes(), &report)
+			assert.NoError(t, err)
+		}
+		return w.Code, report
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/anki", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		code, _ := importFile(t, nil, nil)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid Package", func(t *testing.T) {
+		code, _ := importFile(t, []byte("word,definition\n"), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, _ = importFile(t, deck, map[string]string{"word_field": "Missing"})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		code, report := importFile(t, deck, map[string]string{"dry_run": "true"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 4, report.Total)
+		assert.Equal(t, 2, report.Created)
+
+		var count int64
+		env.DB.DB.Model(&wordsDomain.Word{}).Where("user_id = ?", 1).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Success Import", func(t *testing.T) {
+		code, report := importFile(t, deck, map[string]string{"word_field": "Front", "definition_field": "Back"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 4, report.Total)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 1, report.Failed)
+
+		assert.Equal(t, []resp.ImportDuplicate{{Line: 2, Word: "ticket", Resolution: "skipped"}}, report.Duplicates)
+		assert.Equal(t, 4, report.Errors[0].Line)
+		assert.Equal(t, []resp.ImportMedia{{File: "ticket.mp3", Words: []string{"ticket"}}}, report.Media)
+
+		var suitcase, desk wordsDomain.Word
+		env.DB.DB.Where("user_id = ? AND word = ?", 1, "suitcase").First(&suitcase)
+		env.DB.DB.Where("user_id = ? AND word = ?", 1, "desk").First(&desk)
+
+		// mature card goes to review queue keeping its interval
+		assert.True(t, suitcase.IsLearned)
+		assert.Equal(t, "travel", suitcase.Tags)
+		assert.Equal(t, 30, suitcase.IntervalDays)
+		assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), suitcase.DueAt, time.Hour)
+
+		assert.False(t, desk.IsLearned)
+		assert.Equal(t, 0, desk.IntervalDays)
+	})
+}
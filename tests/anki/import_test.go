@@ -0,0 +1,173 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/infrastructure/anki"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+const basicModels = `{
+	"100": {"name": "Basic", "flds": [{"name": "Front", "ord": 0}, {"name": "Back", "ord": 1}]},
+	"200": {"name": "Vocab", "flds": [{"name": "Extra", "ord": 0}, {"name": "Meaning", "ord": 1}, {"name": "Term", "ord": 2}]}
+}`
+
+type testNote struct {
+	id     int64
+	model  int64
+	tags   string
+	fields string
+}
+
+type testCard struct {
+	note    int64
+	cardTyp int
+	ivl     int
+}
+
+// buildPackage writes minimal legacy collection with given notes and cards
+func buildPackage(t *testing.T, collectionName string, notes []testNote, cards []testCard, media string) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "collection")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+
+	statements := []string{
+		`CREATE TABLE col (id integer PRIMARY KEY, models text NOT NULL)`,
+		`CREATE TABLE notes (id integer PRIMARY KEY, mid integer, tags text, flds text)`,
+		`CREATE TABLE cards (id integer PRIMARY KEY, nid integer, type integer, ivl integer)`,
+	}
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	_, err = db.Exec(`INSERT INTO col VALUES (1, ?)`, basicModels)
+	require.NoError(t, err)
+	for _, note := range notes {
+		_, err = db.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?)`, note.id, note.model, note.tags, note.fields)
+		require.NoError(t, err)
+	}
+	for _, card := range cards {
+		_, err = db.Exec(`INSERT INTO cards (nid, type, ivl) VALUES (?, ?, ?)`, card.note, card.cardTyp, card.ivl)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	collection, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+	files := map[string][]byte{collectionName: collection}
+	if media != "" {
+		files[anki.MediaFile] = []byte(media)
+	}
+	for name, data := range files {
+		writer, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
+
+func importPackage(data []byte, mapping anki.Mapping) (anki.Result, error) {
+	return anki.Import(bytes.NewReader(data), int64(len(data)), mapping)
+}
+
+func TestImportPackage(t *testing.T) {
+	notes := []testNote{
+		{id: 1, model: 100, tags: " travel ", fields: "suitcase\x1fa case for <b>clothes</b>"},
+		{id: 2, model: 100, tags: "", fields: "ticket<br>\x1fa pass [sound:ticket.mp3]"},
+		{id: 3, model: 100, tags: " learned ", fields: "desk\x1f<div>a table</div><div>for work</div>"},
+		{id: 4, model: 200, tags: "", fields: "extra\x1fmeaning of term\x1fterm <img src=\"term.png\">"},
+	}
+	cards := []testCard{
+		{note: 1, cardTyp: 2, ivl: 45},
+		{note: 1, cardTyp: 2, ivl: 3},
+		{note: 2, cardTyp: 2, ivl: 5},
+		{note: 2, cardTyp: 1, ivl: -600},
+		{note: 4, cardTyp: 0, ivl: 0},
+	}
+	media := `{"0": "ticket.mp3", "1": "term.png", "2": "unused.jpg"}`
+
+	t.Run("Default Mapping", func(t *testing.T) {
+		result, err := importPackage(buildPackage(t, anki.CollectionFile, notes, cards, media), anki.Mapping{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []request.ImportRow{
+			{Line: 1, Word: "suitcase", Definition: "a case for clothes", Tags: "travel", IsLearned: true, IntervalDays: 45},
+			{Line: 2, Word: "ticket", Definition: "a pass"},
+			{Line: 3, Word: "desk", Definition: "a table\nfor work", IsLearned: true},
+			{Line: 4, Word: "extra", Definition: "meaning of term"},
+		}, result.Rows)
+
+		assert.Equal(t, []response.ImportMedia{
+			{File: "term.png", Words: []string{}},
+			{File: "ticket.mp3", Words: []string{"ticket"}},
+			{File: "unused.jpg", Words: []string{}},
+		}, result.Media)
+	})
+
+	t.Run("Field Mapping", func(t *testing.T) {
+		result, err := importPackage(buildPackage(t, "collection.anki21", notes, cards, media), anki.Mapping{Word: "term", Definition: "Meaning"})
+		require.NoError(t, err)
+
+		// only note type Vocab has mapped fields, notes of Basic are reported by validation
+		require.Len(t, result.Rows, 4)
+		assert.Equal(t, "", result.Rows[0].Word)
+		assert.Equal(t, request.ImportRow{Line: 4, Word: "term", Definition: "meaning of term"}, result.Rows[3])
+		assert.Equal(t, []string{"term"}, result.Media[0].Words)
+	})
+
+	t.Run("Unknown Field", func(t *testing.T) {
+		_, err := importPackage(buildPackage(t, anki.CollectionFile, notes, cards, ""), anki.Mapping{Word: "Missing"})
+		assert.EqualError(t, err, `field "Missing" is not found in note types`)
+	})
+
+	t.Run("Without Media Manifest", func(t *testing.T) {
+		result, err := importPackage(buildPackage(t, anki.CollectionFile, notes, cards, ""), anki.Mapping{})
+		require.NoError(t, err)
+		assert.Len(t, result.Rows, 4)
+		assert.Empty(t, result.Media)
+	})
+
+	t.Run("Compressed Collection", func(t *testing.T) {
+		_, err := importPackage(buildPackage(t, "collection.anki21b", notes, cards, ""), anki.Mapping{})
+		assert.ErrorContains(t, err, "not supported")
+	})
+
+	t.Run("Not a Package", func(t *testing.T) {
+		_, err := importPackage([]byte("word,definition"), anki.Mapping{})
+		assert.EqualError(t, err, "file is not a valid Anki package")
+	})
+
+	t.Run("Export Round Trip", func(t *testing.T) {
+		archive, err := anki.Export("vocab", []anki.Note{
+			{Id: 1, Word: "suitcase", Definition: "a case\nfor clothes", Tags: []string{anki.LearnedTag, "trip"}},
+			{Id: 2, Word: "a < b", Definition: "less", Tags: []string{anki.LearningTag}},
+		})
+		require.NoError(t, err)
+
+		result, err := importPackage(archive, anki.Mapping{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []request.ImportRow{
+			{Line: 1, Word: "suitcase", Definition: "a case\nfor clothes", Tags: "trip", IsLearned: true},
+			{Line: 2, Word: "a < b", Definition: "less"},
+		}, result.Rows)
+	})
+}
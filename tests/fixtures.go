@@ -1,10 +1,13 @@
 package tests
 
 import (
+	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 
+	setsDomain "mono_pardo/internal/domain/sets"
 	usersDomain "mono_pardo/internal/domain/users"
 	wordsDomain "mono_pardo/internal/domain/words"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
 )
 
 type UserFixture struct {
@@ -30,3 +33,46 @@ func (f *WordFixture) Setup(db *gorm.DB) error {
 func (f *WordFixture) Teardown(db *gorm.DB) error {
 	return db.Unscoped().Delete(&f.Words).Error
 }
+
+type AttemptFixture struct {
+	Attempts []wordsDomain.TrainingAttempt
+}
+
+func (f *AttemptFixture) Setup(db *gorm.DB) error {
+	return db.Create(&f.Attempts).Error
+}
+
+func (f *AttemptFixture) Teardown(db *gorm.DB) error {
+	return db.Unscoped().Delete(&f.Attempts).Error
+}
+
+// SetFixture saves sets through the repository, so Id of every set is filled after Setup
+type SetFixture struct {
+	Sets []setsDomain.WordSet
+}
+
+func (f *SetFixture) Setup(db *mongo.Database) error {
+	repository := setsInfra.NewMongoRepositoryImpl(db)
+
+	for i := range f.Sets {
+		id, err := repository.Save(f.Sets[i])
+		if err != nil {
+			return err
+		}
+		f.Sets[i].Id = id
+	}
+
+	return nil
+}
+
+func (f *SetFixture) Teardown(db *mongo.Database) error {
+	repository := setsInfra.NewMongoRepositoryImpl(db)
+
+	for _, set := range f.Sets {
+		if err := repository.Delete(set.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package kindle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestImportKindle(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "harpoon", Definition: "a spear"},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	importService := wordsDomain.NewImportServiceImpl(vocabService, wordsInfra.NewFileParserImpl())
+	importController := controller.NewImportController(importService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.POST("/import/kindle", importController.ImportKindle)
+
+	vocabDB := buildVocabDB(t)
+
+	importFile := func(t *testing.T, content []byte, fields map[string]string) (int, resp.KindleImportReport) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for key, value := range fields {
+			assert.NoError(t, writer.WriteField(key, value))
+		}
+		if content != nil {
+			part, err := writer.CreateFormFile("file", "vocab.db")
+			assert.NoError(t, err)
+			_, err = part.Write(content)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.Close())
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/kindle", body)
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(w, req)
+
+		var report resp.KindleImportReport
+		if w.Code == http.StatusOK {
+			err := json.Unmarshal(w.Body.Bytes(), &report)
+			assert.NoError(t, err)
+		}
+		return w.Code, report
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/kindle", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Request", func(t *testing.T) {
+		code, _ := importFile(t, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = importFile(t, []byte("not a database"), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, _ = importFile(t, vocabDB, map[string]string{"from": "2024-04-01T00:00:00Z", "to": "2024-03-01T00:00:00Z"})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, _ = importFile(t, vocabDB, map[string]string{"from": "yesterday"})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Dry Run Lists Books", func(t *testing.T) {
+		code, report := importFile(t, vocabDB, map[string]string{"dry_run": "true"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Total)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Len(t, report.Books, 2)
+	})
+
+	t.Run("Success Import of One Book", func(t *testing.T) {
+		code, report := importFile(t, vocabDB, map[string]string{"book": "Moby Dick", "on_conflict": "merge"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+
+		var whale, harpoon wordsDomain.Word
+		env.DB.DB.Where("user_id = ? AND word = ?", 1, "whale").First(&whale)
+		env.DB.DB.Where("user_id = ? AND word = ?", 1, "harpoon").First(&harpoon)
+
+		assert.Equal(t, "all whales are fish", whale.Context)
+		assert.Equal(t, "Moby Dick", whale.Source)
+		assert.False(t, whale.IsLearned)
+
+		assert.Equal(t, "a spear; he threw the harpoon", harpoon.Definition)
+		assert.Equal(t, "he threw the harpoon", harpoon.Context)
+		assert.Equal(t, "Moby Dick", harpoon.Source)
+
+		var count int64
+		env.DB.DB.Model(&wordsDomain.Word{}).Where("user_id = ? AND word = ?", 1, "Nosferatu").Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
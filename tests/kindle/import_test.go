@@ -0,0 +1,72 @@
+package kindle
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/infrastructure/kindle"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+func TestImportVocabDB(t *testing.T) {
+	vocabDB := buildVocabDB(t)
+
+	words := func(rows []request.ImportRow) []string {
+		result := []string{}
+		for _, row := range rows {
+			result = append(result, row.Word)
+		}
+		return result
+	}
+
+	t.Run("All Lookups", func(t *testing.T) {
+		result, err := kindle.Import(bytes.NewReader(vocabDB), kindle.Options{})
+		require.NoError(t, err)
+
+		// both forms of "whale" are imported once, with the latest usage
+		assert.Equal(t, []request.ImportRow{
+			{Line: 1, Word: "whale", Definition: "all whales are fish", Context: "all whales are fish", Source: "Moby Dick"},
+			{Line: 2, Word: "harpoon", Definition: "he threw the harpoon", Context: "he threw the harpoon", Source: "Moby Dick", IsLearned: true},
+			{Line: 3, Word: "Nosferatu", Definition: "the word Nosferatu", Context: "the word Nosferatu", Source: "Dracula"},
+		}, result.Rows)
+
+		assert.Equal(t, []response.KindleBook{
+			{Id: "book-2", Asin: "B002", Title: "Dracula", Authors: "Bram Stoker", Lookups: 1},
+			{Id: "book-1", Asin: "B001", Title: "Moby Dick", Authors: "Herman Melville", Lookups: 3},
+		}, result.Books)
+	})
+
+	t.Run("Filter by Book", func(t *testing.T) {
+		for _, book := range []string{"book-2", "B002", "dracula"} {
+			result, err := kindle.Import(bytes.NewReader(vocabDB), kindle.Options{Book: book})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Nosferatu"}, words(result.Rows))
+			assert.Len(t, result.Books, 2)
+		}
+	})
+
+	t.Run("Filter by Date Range", func(t *testing.T) {
+		from := day1.Add(time.Hour)
+		to := day3.Add(-time.Hour)
+
+		result, err := kindle.Import(bytes.NewReader(vocabDB), kindle.Options{From: &from, To: &to})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"harpoon", "Nosferatu"}, words(result.Rows))
+
+		result, err = kindle.Import(bytes.NewReader(vocabDB), kindle.Options{To: &from})
+		require.NoError(t, err)
+		assert.Equal(t, []request.ImportRow{
+			{Line: 1, Word: "whale", Definition: "a whaling voyage", Context: "a whaling voyage", Source: "Moby Dick"},
+		}, result.Rows)
+	})
+
+	t.Run("Not a Vocabulary Database", func(t *testing.T) {
+		_, err := kindle.Import(bytes.NewReader([]byte("word,definition\n")), kindle.Options{})
+		assert.EqualError(t, err, "file is not a valid Kindle vocabulary database")
+	})
+}
@@ -0,0 +1,64 @@
+package kindle
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+var (
+	day1 = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	day3 = time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+)
+
+// buildVocabDB writes database with the schema of Kindle Vocabulary Builder
+func buildVocabDB(t *testing.T) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "vocab.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+
+	statements := []string{
+		`CREATE TABLE WORDS (id TEXT PRIMARY KEY NOT NULL, word TEXT, stem TEXT, lang TEXT, category INTEGER DEFAULT 0, timestamp INTEGER DEFAULT 0, profileid TEXT)`,
+		`CREATE TABLE LOOKUPS (id TEXT PRIMARY KEY NOT NULL, word_key TEXT, book_key TEXT, dict_key TEXT, pos TEXT, usage TEXT, timestamp INTEGER DEFAULT 0)`,
+		`CREATE TABLE BOOK_INFO (id TEXT PRIMARY KEY NOT NULL, asin TEXT, guid TEXT, lang TEXT, title TEXT, authors TEXT)`,
+		`INSERT INTO BOOK_INFO VALUES ('book-1', 'B001', 'guid-1', 'en', 'Moby Dick', 'Herman Melville')`,
+		`INSERT INTO BOOK_INFO VALUES ('book-2', 'B002', 'guid-2', 'en', 'Dracula', 'Bram Stoker')`,
+		`INSERT INTO WORDS VALUES ('en:whaling', 'whaling', 'whale', 'en', 0, 0, '')`,
+		`INSERT INTO WORDS VALUES ('en:whales', 'whales', 'whale', 'en', 0, 0, '')`,
+		`INSERT INTO WORDS VALUES ('en:harpoon', 'harpoon', 'harpoon', 'en', 100, 0, '')`,
+		`INSERT INTO WORDS VALUES ('en:nosferatu', 'Nosferatu', '', 'en', 0, 0, '')`,
+	}
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	lookups := []struct {
+		id, word, book, usage string
+		at                    time.Time
+	}{
+		{"l1", "en:whaling", "book-1", "a  whaling voyage", day1},
+		{"l2", "en:harpoon", "book-1", "he threw the harpoon", day2},
+		{"l3", "en:whales", "book-1", "all whales are fish", day3},
+		{"l4", "en:nosferatu", "book-2", "the word Nosferatu", day2},
+	}
+	for _, l := range lookups {
+		_, err = db.Exec(`INSERT INTO LOOKUPS VALUES (?, ?, ?, '', '', ?, ?)`, l.id, l.word, l.book, l.usage, l.at.UnixMilli())
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return data
+}
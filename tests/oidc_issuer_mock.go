@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/config"
+)
+
+const (
+	MockOIDCClientId     = "pardo-test"
+	MockOIDCClientSecret = "pardo-test-secret"
+	MockOIDCRedirectURL  = "https://pardo.app/oidc/callback"
+	mockOIDCKeyId        = "mock-key"
+)
+
+// MockOIDCIdentity is the user who logs in at the mock issuer, Claims override claims of the ID token
+type MockOIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Claims        map[string]interface{}
+}
+
+type mockAuthorization struct {
+	Identity      MockOIDCIdentity
+	Nonce         string
+	CodeChallenge string
+	RedirectURI   string
+}
+
+// MockOIDCIssuer is a local OpenID Connect provider, so tests never talk to real ones
+type MockOIDCIssuer struct {
+	Server *httptest.Server
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+func NewMockOIDCIssuer(t *testing.T) *MockOIDCIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate issuer key: %v", err)
+	}
+
+	issuer := &MockOIDCIssuer{key: key, codes: map[string]mockAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Server.Close)
+
+	return issuer
+}
+
+func (i *MockOIDCIssuer) ProviderConfig() config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Issuer:       i.Server.URL,
+		ClientId:     MockOIDCClientId,
+		ClientSecret: MockOIDCClientSecret,
+		RedirectURL:  MockOIDCRedirectURL,
+	}
+}
+
+// Authorize does what the provider does when the user opens authorization URL and gives consent,
+// it returns code and state which the provider sends to the redirect URL
+func (i *MockOIDCIssuer) Authorize(t *testing.T, authorizationURL string, identity MockOIDCIdentity) (string, string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatalf("Invalid authorization URL: %v", err)
+	}
+
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != MockOIDCClientId {
+		t.Fatalf("Unexpected authorization request: %s", authorizationURL)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("Authorization request without PKCE: %s", authorizationURL)
+	}
+
+	code, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	i.mu.Lock()
+	i.codes[code] = mockAuthorization{
+		Identity:      identity,
+		Nonce:         query.Get("nonce"),
+		CodeChallenge: query.Get("code_challenge"),
+		RedirectURI:   query.Get("redirect_uri"),
+	}
+	i.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (i *MockOIDCIssuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 i.Server.URL,
+		"authorization_endpoint": i.Server.URL + "/authorize",
+		"token_endpoint":         i.Server.URL + "/token",
+		"jwks_uri":               i.Server.URL + "/jwks",
+	})
+}
+
+func (i *MockOIDCIssuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": mockOIDCKeyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (i *MockOIDCIssuer) token(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != MockOIDCClientId || clientSecret != MockOIDCClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	// Code is single-use
+	i.mu.Lock()
+	authorization, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || authorization.RedirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.CodeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            i.Server.URL,
+		"aud":            MockOIDCClientId,
+		"sub":            authorization.Identity.Subject,
+		"email":          authorization.Identity.Email,
+		"email_verified": authorization.Identity.EmailVerified,
+		"name":           authorization.Identity.Name,
+		"nonce":          authorization.Nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+	for name, value := range authorization.Identity.Claims {
+		claims[name] = value
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = mockOIDCKeyId
+	signed, err := idToken.SignedString(i.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
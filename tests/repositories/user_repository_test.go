@@ -31,6 +31,9 @@ func TestUserRepository(t *testing.T) {
 
 		err = userRepository.Save(testUser)
 		assert.Error(t, err, "Expected an error while saving a user with the same email")
+
+		err = userRepository.Save(domain.User{Username: "other", Email: testUser.Email, Password: "testpassword"})
+		assert.ErrorIs(t, err, domain.ErrEmailInUse)
 	})
 
 	t.Run("Test FindByEmail", func(t *testing.T) {
@@ -45,7 +48,8 @@ func TestUserRepository(t *testing.T) {
 	})
 
 	t.Run("Test FindAll", func(t *testing.T) {
-		users := userRepository.FindAll()
+		users, err := userRepository.FindAll()
+		assert.NoError(t, err)
 		assert.NotEmpty(t, users)
 	})
 
@@ -55,9 +59,30 @@ func TestUserRepository(t *testing.T) {
 		assert.Equal(t, testUser, foundUser, "Expected the found user to be the same as the test user")
 	})
 
+	t.Run("Test FindById Not Found", func(t *testing.T) {
+		_, err := userRepository.FindById(999)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Test UpdateEmail", func(t *testing.T) {
+		other := domain.User{Id: 2, Username: "other", Email: "other@example.com", Password: "testpassword"}
+		assert.NoError(t, userRepository.Save(other))
+		assert.ErrorIs(t, userRepository.UpdateEmail(other.Id, testUser.Email), domain.ErrEmailInUse)
+
+		assert.NoError(t, userRepository.UpdateEmail(other.Id, "new@example.com"))
+		foundUser, err := userRepository.FindById(other.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "new@example.com", foundUser.Email)
+		assert.True(t, foundUser.IsVerified)
+	})
+
 	t.Run("Test Delete User", func(t *testing.T) {
-		userRepository.Delete(testUser.Id)
+		assert.NoError(t, userRepository.Delete(testUser.Id))
 		_, err := userRepository.FindByEmail(testUser.Email)
 		assert.Error(t, err, "Expected error while finding the user by Email")
+
+		_, err = userRepository.FindById(testUser.Id)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.ErrorIs(t, userRepository.Delete(testUser.Id), domain.ErrUserNotFound)
 	})
 }
@@ -1 +1,133 @@
 package sets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	wordsDomain "mono_pardo/internal/domain/words"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/tests"
+)
+
+func TestAddWordToSet(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	wordFixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "suitcase", Definition: "a case for clothes"},
+			{UserId: 1, Word: "ticket", Definition: "a pass"},
+			{UserId: 2, Word: "secret", Definition: "word of other user"},
+		},
+	}
+	wordsCleanup := env.WithFixture(t, wordFixture)
+	defer wordsCleanup()
+
+	fixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Words: []int{wordFixture.Words[0].Id}},
+			{UserId: 2, Name: "work"},
+		},
+	}
+	cleanup := env.WithMongoFixture(t, fixture)
+	defer cleanup()
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.POST("/:setId/words", setsController.AddWord)
+
+	addWord := func(setId string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets/"+setId+"/words", bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets/"+fixture.Sets[0].Id+"/words", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Word ID", func(t *testing.T) {
+		w := addWord(fixture.Sets[0].Id, map[string]interface{}{"word_id": 0})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Attempt to Add Word to Other User's Set", func(t *testing.T) {
+		w := addWord(fixture.Sets[1].Id, map[string]interface{}{"word_id": wordFixture.Words[1].Id})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		set, err := setsRepository.FindById(fixture.Sets[1].Id)
+		assert.NoError(t, err)
+		assert.Empty(t, set.Words)
+	})
+
+	t.Run("Attempt to Add Word of Other User", func(t *testing.T) {
+		w := addWord(fixture.Sets[0].Id, map[string]interface{}{"word_id": wordFixture.Words[2].Id})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = addWord(fixture.Sets[0].Id, map[string]interface{}{"word_id": 999})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		set, err := setsRepository.FindById(fixture.Sets[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{wordFixture.Words[0].Id}, set.Words)
+	})
+
+	t.Run("Success Add Word", func(t *testing.T) {
+		w := addWord(fixture.Sets[0].Id, map[string]interface{}{"word_id": wordFixture.Words[1].Id})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		set, err := setsRepository.FindById(fixture.Sets[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{wordFixture.Words[0].Id, wordFixture.Words[1].Id}, set.Words)
+	})
+
+	t.Run("Add Same Word Twice", func(t *testing.T) {
+		w := addWord(fixture.Sets[0].Id, map[string]interface{}{"word_id": wordFixture.Words[1].Id})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		set, err := setsRepository.FindById(fixture.Sets[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{wordFixture.Words[0].Id, wordFixture.Words[1].Id}, set.Words)
+	})
+}
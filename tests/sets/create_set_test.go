@@ -1 +1,118 @@
 package sets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/pkg/data/request"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestCreateSet(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.GET("", setsController.GetSets)
+	setsGroup.POST("", setsController.CreateSet)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("No Input", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"description": "set without name",
+		}
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets", bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Success Create Set", func(t *testing.T) {
+		payload := request.CreateSetRequest{
+			Name:        "travel",
+			Description: "words for the trip",
+		}
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/sets", bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var created resp.SetResponse
+		err := json.Unmarshal(w.Body.Bytes(), &created)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, created.Id)
+		assert.Equal(t, "travel", created.Name)
+		assert.Empty(t, created.Words)
+
+		checkW := httptest.NewRecorder()
+		checkReq, _ := http.NewRequest("GET", "/api/v1/sets", nil)
+		checkReq.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(checkW, checkReq)
+
+		var response []resp.SetResponse
+		err = json.Unmarshal(checkW.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, created.Id, response[0].Id)
+		assert.Equal(t, "words for the trip", response[0].Description)
+	})
+}
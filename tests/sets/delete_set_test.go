@@ -1 +1,107 @@
 package sets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestDeleteSet(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Words: []int{1, 2}},
+			{UserId: 2, Name: "work"},
+		},
+	}
+	cleanup := env.WithMongoFixture(t, fixture)
+	defer cleanup()
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.GET("", setsController.GetSets)
+	setsGroup.DELETE("/:setId", setsController.DeleteSet)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/sets/"+fixture.Sets[0].Id, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Set ID Format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/sets/abc", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Attempt to Delete Other User's Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/sets/"+fixture.Sets[1].Id, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		_, err := setsRepository.FindById(fixture.Sets[1].Id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success Delete Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/sets/"+fixture.Sets[0].Id, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// check that user doesn't have any sets now
+		checkW := httptest.NewRecorder()
+		checkReq, _ := http.NewRequest("GET", "/api/v1/sets", nil)
+		checkReq.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(checkW, checkReq)
+
+		var response []resp.SetResponse
+		err := json.Unmarshal(checkW.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 0)
+	})
+}
@@ -1 +1,109 @@
 package sets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestGetSet(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Description: "words for the trip", Words: []int{1, 2}},
+			{UserId: 2, Name: "work"},
+		},
+	}
+	cleanup := env.WithMongoFixture(t, fixture)
+	defer cleanup()
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.GET("/:setId", setsController.GetSet)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/"+fixture.Sets[0].Id, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Set ID Format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/abc", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Set Not Found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/000000000000000000000000", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Attempt to Get Other User's Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/"+fixture.Sets[1].Id, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Success Get Set", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets/"+fixture.Sets[0].Id, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response resp.SetResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, fixture.Sets[0].Id, response.Id)
+		assert.Equal(t, "travel", response.Name)
+		assert.Equal(t, "words for the trip", response.Description)
+		assert.Equal(t, []int{1, 2}, response.Words)
+	})
+}
@@ -1 +1,97 @@
 package sets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestGetSets(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "test-token-user3").Return(3, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Description: "words for the trip", Words: []int{1, 2}},
+			{UserId: 1, Name: "food", Words: []int{3}},
+			{UserId: 2, Name: "work"},
+		},
+	}
+	cleanup := env.WithMongoFixture(t, fixture)
+	defer cleanup()
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.GET("", setsController.GetSets)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("User Without Sets", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets", nil)
+		req.Header.Set("Authorization", "Bearer test-token-user3")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []resp.SetResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 0)
+	})
+
+	t.Run("Success Get Sets", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/sets", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []resp.SetResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+
+		assert.Equal(t, "travel", response[0].Name)
+		assert.Equal(t, []int{1, 2}, response[0].Words)
+		assert.Equal(t, "food", response[1].Name)
+	})
+}
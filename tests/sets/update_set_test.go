@@ -1 +1,124 @@
 package sets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	setsDomain "mono_pardo/internal/domain/sets"
+	setsInfra "mono_pardo/internal/infrastructure/sets"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	"mono_pardo/tests"
+)
+
+func TestUpdateSet(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	mongoDb := env.SetupMongo(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.SetFixture{
+		Sets: []setsDomain.WordSet{
+			{UserId: 1, Name: "travel", Description: "words for the trip", Words: []int{1, 2}},
+			{UserId: 2, Name: "work"},
+		},
+	}
+	cleanup := env.WithMongoFixture(t, fixture)
+	defer cleanup()
+
+	setsRepository := setsInfra.NewMongoRepositoryImpl(mongoDb.DB)
+	validate := validator.New()
+	setsService := setsDomain.NewServiceImpl(validate, setsRepository, wordsInfra.NewPostgresRepositoryImpl(env.DB.DB))
+	setsController := controller.NewSetsController(setsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	setsGroup := router.Group("/api/v1/sets")
+	setsGroup.Use(authMiddleware.Handle())
+	setsGroup.PATCH("/:setId", setsController.UpdateSet)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/v1/sets/"+fixture.Sets[0].Id, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("No Updates", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/v1/sets/"+fixture.Sets[0].Id, bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Empty Name", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{"name": " "})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/v1/sets/"+fixture.Sets[0].Id, bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Attempt to Update Other User's Set", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{"name": "hijacked"})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/v1/sets/"+fixture.Sets[1].Id, bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		set, err := setsRepository.FindById(fixture.Sets[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "work", set.Name)
+	})
+
+	t.Run("Successful Update", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{"name": "holidays"})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/v1/sets/"+fixture.Sets[0].Id, bytes.NewBuffer(jsonData))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Description and words should stay untouched
+		set, err := setsRepository.FindById(fixture.Sets[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "holidays", set.Name)
+		assert.Equal(t, "words for the trip", set.Description)
+		assert.Equal(t, []int{1, 2}, set.Words)
+	})
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -8,11 +9,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	usersDomain "mono_pardo/internal/domain/users"
 	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
 	"mono_pardo/pkg/config"
 )
 
@@ -23,9 +27,15 @@ type TestDB struct {
 	DB *gorm.DB
 }
 
+// TestMongo represents test mongo database connection
+type TestMongo struct {
+	DB *mongo.Database
+}
+
 // TestEnv holds all test environment components
 type TestEnv struct {
 	DB     *TestDB
+	Mongo  *TestMongo
 	Router *gin.Engine
 }
 
@@ -113,12 +123,42 @@ func setupTestDB(t *testing.T, config *config.Config) *TestDB {
 	return nil
 }
 
+// SetupMongo creates unique test mongo database, it is dropped on Cleanup
+func (env *TestEnv) SetupMongo(t *testing.T) *TestMongo {
+	t.Helper()
+
+	config := loadTestConfig(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoURI))
+	if err != nil {
+		t.Fatalf("Failed to connect to mongo: %v", err)
+	}
+
+	if err = client.Ping(ctx, nil); err != nil {
+		t.Fatalf("Failed to ping mongo: %v", err)
+	}
+
+	dbName := fmt.Sprintf("%s_%d", config.MongoTestDB, time.Now().UnixNano())
+	env.Mongo = &TestMongo{DB: client.Database(dbName)}
+
+	return env.Mongo
+}
+
 // Fixture interface for creating fixtures
 type Fixture interface {
 	Setup(db *gorm.DB) error
 	Teardown(db *gorm.DB) error
 }
 
+// MongoFixture interface for creating fixtures in mongo
+type MongoFixture interface {
+	Setup(db *mongo.Database) error
+	Teardown(db *mongo.Database) error
+}
+
 // WithFixture prepare fixture for test
 func (env *TestEnv) WithFixture(t *testing.T, fixture Fixture) func() {
 	t.Helper()
@@ -134,6 +174,25 @@ func (env *TestEnv) WithFixture(t *testing.T, fixture Fixture) func() {
 	}
 }
 
+// WithMongoFixture prepare mongo fixture for test
+func (env *TestEnv) WithMongoFixture(t *testing.T, fixture MongoFixture) func() {
+	t.Helper()
+
+	if env.Mongo == nil {
+		t.Fatalf("Mongo is not set up, call SetupMongo first")
+	}
+
+	if err := fixture.Setup(env.Mongo.DB); err != nil {
+		t.Fatalf("Failed to setup mongo fixture: %v", err)
+	}
+
+	return func() {
+		if err := fixture.Teardown(env.Mongo.DB); err != nil {
+			t.Errorf("Failed to teardown mongo fixture: %v", err)
+		}
+	}
+}
+
 // RunMigrations run migrations from indicated directory
 func (env *TestEnv) RunMigrations(t *testing.T) {
 	t.Helper()
@@ -144,11 +203,25 @@ func (env *TestEnv) RunMigrations(t *testing.T) {
 		// Models
 		&usersDomain.User{},
 		&wordsDomain.Word{},
+		&wordsDomain.TrainingAttempt{},
+		&usersDomain.RefreshToken{},
+		&usersDomain.RevokedToken{},
+		&usersDomain.PasswordResetToken{},
+		&usersDomain.Counter{},
+		&usersDomain.TwoFactor{},
+		&usersDomain.RecoveryCode{},
+		&usersDomain.Identity{},
+		&usersDomain.AccessToken{},
+		&usersDomain.SecurityEvent{},
 	}
 
 	if err := env.DB.DB.AutoMigrate(models...); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
+
+	if err := wordsInfra.CreateSearchIndexes(env.DB.DB); err != nil {
+		t.Fatalf("Failed to create search indexes: %v", err)
+	}
 }
 
 // Cleanup (clean test enviroment)
@@ -157,6 +230,19 @@ func (env *TestEnv) Cleanup(t *testing.T) {
 
 	config := loadTestConfig(t)
 
+	// Remove test mongo db
+	if env.Mongo != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := env.Mongo.DB.Drop(ctx); err != nil {
+			t.Errorf("Failed to drop test mongo database: %v", err)
+		}
+		if err := env.Mongo.DB.Client().Disconnect(ctx); err != nil {
+			t.Errorf("Failed to disconnect from mongo: %v", err)
+		}
+	}
+
 	// Get *sql.DB
 	sqlDB, err := env.DB.DB.DB()
 	if err != nil {
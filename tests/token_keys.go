@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mono_pardo/internal/utils"
+)
+
+// NewTestKeySet creates a key directory with one Ed25519 key and loads it like the server does
+func NewTestKeySet(t *testing.T) *utils.KeySet {
+	t.Helper()
+
+	dir := t.TempDir()
+	WriteTestKey(t, dir, "test-key", GenerateTestKey(t), true)
+
+	keys, err := utils.LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatalf("Could not load token keys: %v", err)
+	}
+
+	return keys
+}
+
+// WriteTestKey writes the private key, or only its public key, as <kid>.pem
+func WriteTestKey(t *testing.T, dir, kid string, key ed25519.PrivateKey, private bool) {
+	t.Helper()
+
+	var block *pem.Block
+	if private {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("Could not encode private key: %v", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			t.Fatalf("Could not encode public key: %v", err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("Could not write key: %v", err)
+	}
+}
+
+func GenerateTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate key: %v", err)
+	}
+
+	return key
+}
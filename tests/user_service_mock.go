@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(user request.LoginRequest) (response.LoginResponse, error) {
+	args := m.Called(user)
+	return args.Get(0).(response.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(refreshRequest request.RefreshTokenRequest) (response.LoginResponse, error) {
+	args := m.Called(refreshRequest)
+	return args.Get(0).(response.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) GetSessions(userId int) ([]response.SessionResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]response.SessionResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(revokeRequest request.RevokeSessionRequest) error {
+	args := m.Called(revokeRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) Logout(logoutRequest request.LogoutRequest) error {
+	args := m.Called(logoutRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutEverywhere(logoutRequest request.LogoutEverywhereRequest) error {
+	args := m.Called(logoutRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyEmail(verifyRequest request.VerifyEmailRequest) error {
+	args := m.Called(verifyRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendVerification(userId int) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ForgotPassword(forgotRequest request.ForgotPasswordRequest) error {
+	args := m.Called(forgotRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(resetRequest request.ResetPasswordRequest) error {
+	args := m.Called(resetRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) UpdateProfile(updateRequest request.UpdateProfileRequest) (response.UserResponse, error) {
+	args := m.Called(updateRequest)
+	return args.Get(0).(response.UserResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ChangePassword(changeRequest request.ChangePasswordRequest) error {
+	args := m.Called(changeRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ChangeEmail(changeRequest request.ChangeEmailRequest) error {
+	args := m.Called(changeRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ConfirmEmailChange(confirmRequest request.ConfirmEmailChangeRequest) error {
+	args := m.Called(confirmRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) DeleteAccount(deleteRequest request.DeleteAccountRequest) (response.AccountDeletionResponse, error) {
+	args := m.Called(deleteRequest)
+	return args.Get(0).(response.AccountDeletionResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RestoreAccount(restoreRequest request.RestoreAccountRequest) error {
+	args := m.Called(restoreRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) EnrollTwoFactor(enrollRequest request.EnrollTwoFactorRequest) (response.TwoFactorEnrollmentResponse, error) {
+	args := m.Called(enrollRequest)
+	return args.Get(0).(response.TwoFactorEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ConfirmTwoFactor(confirmRequest request.ConfirmTwoFactorRequest) (response.RecoveryCodesResponse, error) {
+	args := m.Called(confirmRequest)
+	return args.Get(0).(response.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *MockAuthService) DisableTwoFactor(disableRequest request.DisableTwoFactorRequest) error {
+	args := m.Called(disableRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyMFA(mfaRequest request.MFALoginRequest) (response.LoginResponse, error) {
+	args := m.Called(mfaRequest)
+	return args.Get(0).(response.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) StartOIDCLogin(startRequest request.OIDCStartRequest) (response.OIDCStartResponse, error) {
+	args := m.Called(startRequest)
+	return args.Get(0).(response.OIDCStartResponse), args.Error(1)
+}
+
+func (m *MockAuthService) FinishOIDCLogin(callbackRequest request.OIDCCallbackRequest) (response.LoginResponse, error) {
+	args := m.Called(callbackRequest)
+	return args.Get(0).(response.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) CreateAccessToken(createRequest request.CreateAccessTokenRequest) (response.AccessTokenCreatedResponse, error) {
+	args := m.Called(createRequest)
+	return args.Get(0).(response.AccessTokenCreatedResponse), args.Error(1)
+}
+
+func (m *MockAuthService) GetAccessTokens(userId int) ([]response.AccessTokenResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]response.AccessTokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeAccessToken(revokeRequest request.RevokeAccessTokenRequest) error {
+	args := m.Called(revokeRequest)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AuthenticateAccessToken(token string) (int, []string, error) {
+	args := m.Called(token)
+	return args.Int(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockAuthService) Register(user request.CreateUserRequest) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GetUserId(token string) (int, error) {
+	args := m.Called(token)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthService) GetRole(token string) (string, error) {
+	args := m.Called(token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) GetSecurityEvents(eventsRequest request.SecurityEventsRequest) (response.SecurityEventsResponse, error) {
+	args := m.Called(eventsRequest)
+	return args.Get(0).(response.SecurityEventsResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RecordSecurityEvent(event usersDomain.SecurityEvent) {
+	m.Called(event)
+}
+
+func (m *MockAuthService) GetJWKS() response.JWKSResponse {
+	args := m.Called()
+	return args.Get(0).(response.JWKSResponse)
+}
+
+func (m *MockAuthService) FindUser(userId int) (response.UserResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).(response.UserResponse), args.Error(1)
+}
@@ -0,0 +1,240 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestAccessTokens(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "script owner", Email: "owner@example.com", Password: hashedPassword},
+			{Username: "other", Email: "other@example.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	router.POST("/api/v1/authentication/login", authenticationController.Login)
+	meGroup := router.Group("/api/v1/me", authMiddleware.Handle())
+	meGroup.GET("", profileController.GetProfile)
+	meGroup.GET("/tokens", profileController.GetAccessTokens)
+	meGroup.POST("/tokens", profileController.CreateAccessToken)
+	meGroup.DELETE("/tokens/:tokenId", profileController.RevokeAccessToken)
+	vocabGroup := router.Group("/api/v1/vocab", authMiddleware.HandleScoped(usersDomain.ScopeVocabRead, usersDomain.ScopeVocabWrite))
+	vocabGroup.GET("", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, ctx.GetInt("userId"))
+	})
+	vocabGroup.POST("", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusCreated, ctx.GetInt("userId"))
+	})
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func(email string) string {
+		w := send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: email, Password: "test_password"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var tokens response.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		return tokens.Token
+	}
+
+	createToken := func(session string, createRequest request.CreateAccessTokenRequest) response.AccessTokenCreatedResponse {
+		w := send("POST", "/api/v1/me/tokens", session, createRequest)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created response.AccessTokenCreatedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created
+	}
+
+	listTokens := func(session string) []response.AccessTokenResponse {
+		w := send("GET", "/api/v1/me/tokens", session, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var tokens []response.AccessTokenResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		return tokens
+	}
+
+	session := login("owner@example.com")
+	ownerId := fixture.Users[0].Id
+
+	t.Run("Invalid Scopes", func(t *testing.T) {
+		w := send("POST", "/api/v1/me/tokens", session, request.CreateAccessTokenRequest{Name: "script", Scopes: []string{"admin"}})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("POST", "/api/v1/me/tokens", session, request.CreateAccessTokenRequest{Name: "script"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("POST", "/api/v1/me/tokens", session, request.CreateAccessTokenRequest{Name: "script", Scopes: []string{usersDomain.ScopeVocabRead}, ExpiresInDays: 1000})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Token Is Limited By Scopes", func(t *testing.T) {
+		created := createToken(session, request.CreateAccessTokenRequest{Name: "reader", Scopes: []string{usersDomain.ScopeVocabRead}})
+		assert.True(t, strings.HasPrefix(created.Token, usersDomain.AccessTokenPrefix))
+		assert.Equal(t, created.Token[len(created.Token)-4:], created.Hint)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 90), created.ExpiresAt, time.Minute)
+
+		// only the hash is stored
+		var stored usersDomain.AccessToken
+		require.NoError(t, env.DB.DB.First(&stored, created.Id).Error)
+		assert.Equal(t, utils.HashToken(created.Token), stored.TokenHash)
+
+		w := send("GET", "/api/v1/vocab", created.Token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, fmt.Sprint(ownerId), w.Body.String())
+
+		w = send("POST", "/api/v1/vocab", created.Token, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// account routes need a session
+		w = send("GET", "/api/v1/me", created.Token, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = send("POST", "/api/v1/me/tokens", created.Token, request.CreateAccessTokenRequest{Name: "escalated", Scopes: []string{usersDomain.ScopeVocabWrite}})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("List And Revoke", func(t *testing.T) {
+		created := createToken(session, request.CreateAccessTokenRequest{Name: "writer", Scopes: []string{usersDomain.ScopeVocabWrite, usersDomain.ScopeVocabWrite}, ExpiresInDays: 7})
+		assert.Equal(t, []string{usersDomain.ScopeVocabWrite}, created.Scopes)
+
+		assert.Equal(t, http.StatusCreated, send("POST", "/api/v1/vocab", created.Token, nil).Code)
+
+		tokens := listTokens(session)
+		require.Len(t, tokens, 2)
+		assert.Equal(t, "writer", tokens[0].Name)
+		assert.NotNil(t, tokens[0].LastUsedAt)
+		assert.Equal(t, "reader", tokens[1].Name)
+
+		// the token can't be revoked by another user
+		w := send("DELETE", fmt.Sprintf("/api/v1/me/tokens/%d", created.Id), login("other@example.com"), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = send("DELETE", fmt.Sprintf("/api/v1/me/tokens/%d", created.Id), session, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, send("POST", "/api/v1/vocab", created.Token, nil).Code)
+		assert.Len(t, listTokens(session), 1)
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		created := createToken(session, request.CreateAccessTokenRequest{Name: "expiring", Scopes: []string{usersDomain.ScopeVocabRead}})
+		require.NoError(t, env.DB.DB.Model(&usersDomain.AccessToken{}).Where("id = ?", created.Id).Update("expires_at", time.Now().UTC().Add(-time.Second)).Error)
+
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/vocab", created.Token, nil).Code)
+	})
+
+	t.Run("Token Of Suspended User", func(t *testing.T) {
+		created := createToken(session, request.CreateAccessTokenRequest{Name: "suspended", Scopes: []string{usersDomain.ScopeVocabRead}})
+		require.NoError(t, env.DB.DB.Model(&usersDomain.User{}).Where("id = ?", ownerId).Update("suspended_at", time.Now().UTC()).Error)
+
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/vocab", created.Token, nil).Code)
+	})
+}
+
+func TestAuthMiddlewareScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	readToken := usersDomain.AccessTokenPrefix + "read"
+	setsToken := usersDomain.AccessTokenPrefix + "sets"
+	revokedToken := usersDomain.AccessTokenPrefix + "revoked"
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "session-token").Return(1, nil)
+	mockAuthService.On("AuthenticateAccessToken", readToken).Return(2, []string{usersDomain.ScopeVocabRead}, nil)
+	mockAuthService.On("AuthenticateAccessToken", setsToken).Return(3, []string{usersDomain.ScopeSetsManage}, nil)
+	mockAuthService.On("AuthenticateAccessToken", revokedToken).Return(0, []string(nil), usersDomain.ErrInvalidAccessToken)
+	mockAuthService.On("RecordSecurityEvent", mock.Anything).Return()
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := gin.New()
+	handler := func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, fmt.Sprint(ctx.GetInt("userId")))
+	}
+	router.GET("/me", authMiddleware.Handle(), handler)
+	router.GET("/vocab", authMiddleware.HandleScoped(usersDomain.ScopeVocabRead, usersDomain.ScopeVocabWrite), handler)
+	router.POST("/vocab", authMiddleware.HandleScoped(usersDomain.ScopeVocabRead, usersDomain.ScopeVocabWrite), handler)
+	router.GET("/sets", authMiddleware.HandleScoped(usersDomain.ScopeSetsManage, usersDomain.ScopeSetsManage), handler)
+
+	call := func(method, path, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// sessions have all scopes
+	assert.Equal(t, "1", call("GET", "/me", "session-token").Body.String())
+	assert.Equal(t, http.StatusOK, call("POST", "/vocab", "session-token").Code)
+	assert.Equal(t, http.StatusOK, call("GET", "/sets", "session-token").Code)
+
+	assert.Equal(t, "2", call("GET", "/vocab", readToken).Body.String())
+	assert.Equal(t, http.StatusForbidden, call("POST", "/vocab", readToken).Code)
+	assert.Equal(t, http.StatusForbidden, call("GET", "/sets", readToken).Code)
+	assert.Equal(t, http.StatusForbidden, call("GET", "/me", readToken).Code)
+
+	assert.Equal(t, "3", call("GET", "/sets", setsToken).Body.String())
+	assert.Equal(t, http.StatusForbidden, call("GET", "/vocab", setsToken).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/vocab", revokedToken).Code)
+
+	// every denied access token is recorded for its owner
+	denied := map[int]int{}
+	for _, c := range mockAuthService.Calls {
+		if c.Method == "RecordSecurityEvent" {
+			event := c.Arguments.Get(0).(usersDomain.SecurityEvent)
+			assert.Equal(t, usersDomain.EventAccessTokenDenied, event.Type)
+			denied[event.UserId]++
+		}
+	}
+	assert.Equal(t, map[int]int{0: 1, 2: 3, 3: 1}, denied)
+}
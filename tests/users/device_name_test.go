@@ -0,0 +1,56 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestDeviceName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var device string
+	service := new(tests.MockAuthService)
+	service.On("Login", mock.Anything).Run(func(args mock.Arguments) {
+		device = args.Get(0).(request.LoginRequest).Device
+	}).Return(response.LoginResponse{}, nil)
+
+	authenticationController := controller.NewAuthenticationController(service)
+	router := gin.New()
+	router.POST("/login", authenticationController.Login)
+
+	login := func(userAgent string) {
+		data, _ := json.Marshal(request.LoginRequest{Email: "test@email.com", Password: "test_password"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	t.Run("Short User Agent", func(t *testing.T) {
+		login("Firefox")
+		assert.Equal(t, "Firefox", device)
+	})
+
+	t.Run("Long User Agent Is Cut On Rune Boundary", func(t *testing.T) {
+		// 254 bytes, then a 3-byte rune which doesn't fit into 255
+		login(strings.Repeat("a", 254) + "€€")
+		assert.Equal(t, strings.Repeat("a", 254), device)
+		assert.True(t, utf8.ValidString(device))
+	})
+}
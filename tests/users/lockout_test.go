@@ -0,0 +1,217 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api"
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/errors"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	usersInfra "mono_pardo/internal/infrastructure/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/config"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/tests"
+)
+
+func TestLoginLockout(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	testConfig.LoginMaxFailures = 3
+	testConfig.LoginLockoutDuration = time.Minute
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.CounterStore = usersInfra.NewPostgresCounterStoreImpl(env.DB.DB)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	router := env.Router
+	router.POST("/api/v1/authentication/login", authenticationController.Login)
+
+	login := func(email, password string) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(request.LoginRequest{Email: email, Password: password})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/authentication/login", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Success Login Resets Failures", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusOK, login("test@email.com", "test_password").Code)
+
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusOK, login("test@email.com", "test_password").Code)
+	})
+
+	t.Run("Account Is Locked", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusBadRequest, login("test@email.com", "wrong").Code)
+
+		w := login("test@email.com", "wrong")
+		assert.Equal(t, http.StatusLocked, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		var apiError errors.APIError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiError))
+		assert.Equal(t, errors.AccountLockedError, apiError.Type)
+
+		// even the right password doesn't help until the lockout is over
+		assert.Equal(t, http.StatusLocked, login("test@email.com", "test_password").Code)
+	})
+
+	t.Run("Unknown Email Is Locked The Same Way", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, login("unknown@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusBadRequest, login("unknown@email.com", "wrong").Code)
+		assert.Equal(t, http.StatusLocked, login("unknown@email.com", "wrong").Code)
+	})
+
+	t.Run("Lockout Ends", func(t *testing.T) {
+		// move the end of the window to the past
+		err := env.DB.DB.Model(&usersDomain.Counter{}).Where("1 = 1").Update("reset_at", time.Now().UTC().Add(-time.Second)).Error
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, login("test@email.com", "test_password").Code)
+	})
+}
+
+func TestMemoryCounterStore(t *testing.T) {
+	store := usersInfra.NewMemoryCounterStore()
+
+	count, resetAt, err := store.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.True(t, resetAt.IsZero())
+
+	count, resetAt, err = store.Increment("key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), resetAt, time.Second)
+
+	count, sameResetAt, _ := store.Increment("key", time.Minute)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, resetAt, sameResetAt)
+
+	count, _, _ = store.Get("key")
+	assert.Equal(t, 2, count)
+
+	// other keys are counted separately
+	count, _, _ = store.Increment("other", time.Minute)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, store.Reset("key"))
+	count, _, _ = store.Get("key")
+	assert.Equal(t, 0, count)
+
+	// new window starts after the previous one ends
+	store.Increment("short", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	count, _, _ = store.Get("short")
+	assert.Equal(t, 0, count)
+	count, _, _ = store.Increment("short", time.Minute)
+	assert.Equal(t, 1, count)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := usersInfra.NewMemoryCounterStore()
+	rateLimit := middleware.NewRateLimitMiddleware(store, "test", 2, time.Minute)
+	otherLimit := middleware.NewRateLimitMiddleware(store, "other", 1, time.Minute)
+
+	router := gin.New()
+	router.POST("/limited", rateLimit.Handle(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	router.POST("/other", otherLimit.Handle(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	call := func(path, ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, nil)
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, call("/limited", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, call("/limited", "10.0.0.1").Code)
+
+	w := call("/limited", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// other clients and limiters are counted separately
+	assert.Equal(t, http.StatusOK, call("/limited", "10.0.0.2").Code)
+	assert.Equal(t, http.StatusOK, call("/other", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("/other", "10.0.0.1").Code)
+}
+
+func TestRateLimitTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(trustedProxies []string) *gin.Engine {
+		router, err := api.NewEngine(&config.Config{TrustedProxies: trustedProxies})
+		require.NoError(t, err)
+
+		rateLimit := middleware.NewRateLimitMiddleware(usersInfra.NewMemoryCounterStore(), "test", 1, time.Minute)
+		router.POST("/limited", rateLimit.Handle(), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	call := func(router *gin.Engine, remoteIP, forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/limited", nil)
+		req.RemoteAddr = remoteIP + ":1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Spoofed Header Is Ignored", func(t *testing.T) {
+		router := newRouter(nil)
+
+		assert.Equal(t, http.StatusOK, call(router, "203.0.113.7", "10.0.0.1"))
+		assert.Equal(t, http.StatusTooManyRequests, call(router, "203.0.113.7", "10.0.0.2"))
+	})
+
+	t.Run("Header Of Trusted Proxy", func(t *testing.T) {
+		router := newRouter([]string{"192.168.0.0/16"})
+
+		assert.Equal(t, http.StatusOK, call(router, "192.168.0.10", "203.0.113.7"))
+		assert.Equal(t, http.StatusOK, call(router, "192.168.0.10", "203.0.113.8"))
+		assert.Equal(t, http.StatusTooManyRequests, call(router, "192.168.0.11", "203.0.113.7"))
+	})
+
+	_, err := api.NewEngine(&config.Config{TrustedProxies: []string{"not an ip"}})
+	assert.Error(t, err)
+}
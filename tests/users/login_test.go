@@ -5,14 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/go-playground/validator"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 
 	"mono_pardo/internal/api/controller"
 	usersDomain "mono_pardo/internal/domain/users"
-	usersInfra "mono_pardo/internal/infrastructure/users"
 	"mono_pardo/internal/utils"
 	"mono_pardo/pkg/data/request"
 	"mono_pardo/pkg/data/response"
@@ -26,6 +26,7 @@ func TestLogin(t *testing.T) {
 	env.RunMigrations(t)
 
 	hashedPassword, _ := utils.HashPassword("test_password")
+	legacyPassword, _ := utils.BcryptHasher{Cost: bcrypt.MinCost}.Hash("legacy_password")
 	fixture := &tests.UserFixture{
 		Users: []usersDomain.User{
 			{
@@ -33,15 +34,19 @@ func TestLogin(t *testing.T) {
 				Email:    "test@email.com",
 				Password: hashedPassword,
 			},
+			{
+				Username: "legacy username",
+				Email:    "legacy@email.com",
+				Password: legacyPassword,
+			},
 		},
 	}
 
 	cleanup := env.WithFixture(t, fixture)
 	defer cleanup()
 
-	userRepository := usersInfra.NewPostgresRepositoryImpl(env.DB.DB)
-	validate := validator.New()
-	authenticationService := usersDomain.NewServiceImpl(testConfig, validate, userRepository)
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
 	authenticationController := controller.NewAuthenticationController(authenticationService)
 
 	router := env.Router
@@ -144,5 +149,37 @@ func TestLogin(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "Bearer", response.TokenType)
 		assert.NotEmpty(t, response.Token)
+		assert.NotEmpty(t, response.RefreshToken)
+	})
+
+	t.Run("Legacy Hash Is Upgraded", func(t *testing.T) {
+		login := func(password string) int {
+			jsonData, _ := json.Marshal(request.LoginRequest{Email: "legacy@email.com", Password: password})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/v1/authentication/login", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		storedHash := func() string {
+			user, err := deps.Repository.FindByEmail("legacy@email.com")
+			assert.NoError(t, err)
+			return user.Password
+		}
+
+		// a wrong password doesn't change the hash
+		assert.Equal(t, http.StatusBadRequest, login("wrong_password"))
+		assert.Equal(t, legacyPassword, storedHash())
+
+		assert.Equal(t, http.StatusOK, login("legacy_password"))
+		upgraded := storedHash()
+		assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+		assert.False(t, utils.DefaultPasswordHasher.NeedsRehash(upgraded))
+
+		assert.Equal(t, http.StatusOK, login("legacy_password"))
+		assert.Equal(t, upgraded, storedHash())
 	})
 }
@@ -0,0 +1,125 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	usersInfra "mono_pardo/internal/infrastructure/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestLogout(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.RevocationStore = usersInfra.NewCachedRevocationStore(usersInfra.NewPostgresRevocationStoreImpl(env.DB.DB), 0)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/refresh", authenticationController.Refresh)
+	authenticationGroup.POST("/logout", authMiddleware.Handle(), authenticationController.Logout)
+	authenticationGroup.POST("/logout/all", authMiddleware.Handle(), authenticationController.LogoutEverywhere)
+	router.GET("/api/v1/protected", authMiddleware.Handle(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	login := func(t *testing.T) response.LoginResponse {
+		jsonData, _ := json.Marshal(request.LoginRequest{Email: "test@email.com", Password: "test_password"})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/authentication/login", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res response.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res
+	}
+
+	send := func(method, path, token string, body []byte) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	refresh := func(refreshToken string) int {
+		jsonData, _ := json.Marshal(request.RefreshTokenRequest{RefreshToken: refreshToken})
+		return send("POST", "/api/v1/authentication/refresh", "", jsonData)
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("POST", "/api/v1/authentication/logout", "", nil))
+		assert.Equal(t, http.StatusUnauthorized, send("POST", "/api/v1/authentication/logout/all", "", nil))
+	})
+
+	t.Run("Token Has JTI", func(t *testing.T) {
+		claims, err := utils.ValidateToken(login(t).Token, deps.Keys)
+		require.NoError(t, err)
+		assert.NotEmpty(t, claims.Id)
+		assert.NotEmpty(t, claims.SessionId)
+	})
+
+	t.Run("Logout Revokes Only Current Session", func(t *testing.T) {
+		phone := login(t)
+		laptop := login(t)
+
+		assert.Equal(t, http.StatusOK, send("GET", "/api/v1/protected", phone.Token, nil))
+		assert.Equal(t, http.StatusOK, send("POST", "/api/v1/authentication/logout", phone.Token, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/protected", phone.Token, nil))
+		assert.Equal(t, http.StatusUnauthorized, refresh(phone.RefreshToken))
+
+		assert.Equal(t, http.StatusOK, send("GET", "/api/v1/protected", laptop.Token, nil))
+	})
+
+	t.Run("Logout Everywhere", func(t *testing.T) {
+		phone := login(t)
+		laptop := login(t)
+
+		assert.Equal(t, http.StatusOK, send("POST", "/api/v1/authentication/logout/all", laptop.Token, nil))
+
+		for _, tokens := range []response.LoginResponse{phone, laptop} {
+			assert.Equal(t, http.StatusUnauthorized, send("GET", "/api/v1/protected", tokens.Token, nil))
+			assert.Equal(t, http.StatusUnauthorized, refresh(tokens.RefreshToken))
+		}
+
+		// new login is not affected
+		assert.Equal(t, http.StatusOK, send("GET", "/api/v1/protected", login(t).Token, nil))
+	})
+}
@@ -0,0 +1,223 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	usersDomain "mono_pardo/internal/domain/users"
+	oidcInfra "mono_pardo/internal/infrastructure/oidc"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "verified", Email: "verified@example.com", Password: hashedPassword, IsVerified: true},
+			{Username: "unverified", Email: "unverified@example.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	issuer := tests.NewMockOIDCIssuer(t)
+	oidcProviders := map[string]usersDomain.OIDCProvider{
+		"mock": oidcInfra.NewProviderImpl(issuer.ProviderConfig(), issuer.Server.Client()),
+	}
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.OIDCProviders = oidcProviders
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/oidc/:provider/start", authenticationController.StartOIDCLogin)
+	authenticationGroup.POST("/oidc/:provider/callback", authenticationController.FinishOIDCLogin)
+
+	send := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	start := func() response.OIDCStartResponse {
+		w := send("/api/v1/authentication/oidc/mock/start", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var flow response.OIDCStartResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &flow))
+		return flow
+	}
+
+	callback := func(flow response.OIDCStartResponse, code, state string) *httptest.ResponseRecorder {
+		return send("/api/v1/authentication/oidc/mock/callback", request.OIDCCallbackRequest{Code: code, State: state, FlowToken: flow.FlowToken})
+	}
+
+	// login returns id of the logged in user, or 0 with the status of the failed login
+	login := func(identity tests.MockOIDCIdentity) (int, int) {
+		flow := start()
+		code, state := issuer.Authorize(t, flow.AuthorizationURL, identity)
+
+		w := callback(flow, code, state)
+		if w.Code != http.StatusOK {
+			return 0, w.Code
+		}
+
+		var tokens response.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+		userId, err := authenticationService.GetUserId(tokens.Token)
+		require.NoError(t, err)
+		return userId, w.Code
+	}
+
+	countIdentities := func() int64 {
+		var count int64
+		env.DB.DB.Model(&usersDomain.Identity{}).Count(&count)
+		return count
+	}
+
+	t.Run("Unknown Provider", func(t *testing.T) {
+		w := send("/api/v1/authentication/oidc/unknown/start", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("New User Is Created", func(t *testing.T) {
+		userId, code := login(tests.MockOIDCIdentity{Subject: "subject-1", Email: "new@example.com", EmailVerified: true, Name: "New User"})
+		require.Equal(t, http.StatusOK, code)
+
+		user, err := deps.Repository.FindById(userId)
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", user.Email)
+		assert.Equal(t, "New User", user.Username)
+		assert.True(t, user.IsVerified)
+		assert.Equal(t, usersDomain.RoleUser, user.Role)
+
+		// the same subject logs into the same user, even if the email at the provider has changed
+		sameUserId, code := login(tests.MockOIDCIdentity{Subject: "subject-1", Email: "changed@example.com", EmailVerified: true})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, userId, sameUserId)
+	})
+
+	t.Run("Verified Email Is Linked", func(t *testing.T) {
+		userId, code := login(tests.MockOIDCIdentity{Subject: "subject-2", Email: "verified@example.com", EmailVerified: true})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, fixture.Users[0].Id, userId)
+
+		identity, err := deps.IdentityRepository.FindBySubject("mock", "subject-2")
+		require.NoError(t, err)
+		assert.Equal(t, fixture.Users[0].Id, identity.UserId)
+	})
+
+	t.Run("Unverified Emails Are Not Linked", func(t *testing.T) {
+		before := countIdentities()
+
+		_, code := login(tests.MockOIDCIdentity{Subject: "subject-3", Email: "verified@example.com", EmailVerified: false})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		_, code = login(tests.MockOIDCIdentity{Subject: "subject-4", Email: "unverified@example.com", EmailVerified: true})
+		assert.Equal(t, http.StatusConflict, code)
+
+		assert.Equal(t, before, countIdentities())
+	})
+
+	t.Run("State Must Match", func(t *testing.T) {
+		flow := start()
+		code, _ := issuer.Authorize(t, flow.AuthorizationURL, tests.MockOIDCIdentity{Subject: "subject-1"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, callback(flow, code, "forged").Code)
+
+		other := start()
+		_, otherState := issuer.Authorize(t, other.AuthorizationURL, tests.MockOIDCIdentity{Subject: "subject-1"})
+		assert.Equal(t, http.StatusUnprocessableEntity, callback(flow, code, otherState).Code)
+	})
+
+	t.Run("Code Is Single-Use", func(t *testing.T) {
+		flow := start()
+		code, state := issuer.Authorize(t, flow.AuthorizationURL, tests.MockOIDCIdentity{Subject: "subject-1"})
+
+		assert.Equal(t, http.StatusOK, callback(flow, code, state).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, callback(flow, code, state).Code)
+	})
+
+	t.Run("Invalid ID Token", func(t *testing.T) {
+		claims := []map[string]interface{}{
+			{"aud": "another-client"},
+			{"iss": "https://another.issuer"},
+			{"nonce": "replayed"},
+			{"exp": 1},
+		}
+
+		for _, override := range claims {
+			_, code := login(tests.MockOIDCIdentity{Subject: "subject-1", Claims: override})
+			assert.Equal(t, http.StatusUnprocessableEntity, code, override)
+		}
+	})
+}
+
+func TestOIDCProvider(t *testing.T) {
+	issuer := tests.NewMockOIDCIssuer(t)
+	provider := oidcInfra.NewProviderImpl(issuer.ProviderConfig(), issuer.Server.Client())
+
+	verifier, err := utils.GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	authorizationURL, err := provider.AuthorizationURL("state", "nonce", utils.PKCEChallenge(verifier))
+	require.NoError(t, err)
+	assert.Contains(t, authorizationURL, issuer.Server.URL+"/authorize?")
+	assert.Contains(t, authorizationURL, "scope=openid+email+profile")
+
+	t.Run("Exchange", func(t *testing.T) {
+		code, state := issuer.Authorize(t, authorizationURL, tests.MockOIDCIdentity{Subject: "subject", Email: "user@example.com", EmailVerified: true, Name: "User"})
+		assert.Equal(t, "state", state)
+
+		claims, err := provider.Exchange(code, verifier)
+		require.NoError(t, err)
+		assert.Equal(t, usersDomain.OIDCClaims{Subject: "subject", Email: "user@example.com", EmailVerified: true, Name: "User", Nonce: "nonce"}, claims)
+
+		_, err = provider.Exchange(code, verifier)
+		assert.Error(t, err)
+	})
+
+	t.Run("Wrong Code Verifier", func(t *testing.T) {
+		code, _ := issuer.Authorize(t, authorizationURL, tests.MockOIDCIdentity{Subject: "subject"})
+
+		_, err := provider.Exchange(code, "wrong")
+		assert.Error(t, err)
+	})
+
+	t.Run("Audience Of Several Clients", func(t *testing.T) {
+		code, _ := issuer.Authorize(t, authorizationURL, tests.MockOIDCIdentity{Subject: "subject", Claims: map[string]interface{}{
+			"aud": []string{tests.MockOIDCClientId, "another-client"},
+		}})
+		_, err := provider.Exchange(code, verifier)
+		assert.Error(t, err)
+
+		code, _ = issuer.Authorize(t, authorizationURL, tests.MockOIDCIdentity{Subject: "subject", Claims: map[string]interface{}{
+			"aud": []string{tests.MockOIDCClientId, "another-client"},
+			"azp": tests.MockOIDCClientId,
+		}})
+		_, err = provider.Exchange(code, verifier)
+		assert.NoError(t, err)
+	})
+}
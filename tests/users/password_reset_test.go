@@ -0,0 +1,170 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	mailerInfra "mono_pardo/internal/infrastructure/mailer"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestPasswordReset(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("old_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	mailDir := t.TempDir()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.Mailer = mailerInfra.NewFileMailerImpl(mailDir, "test@pardo.app")
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/password/forgot", authenticationController.ForgotPassword)
+	authenticationGroup.POST("/password/reset", authenticationController.ResetPassword)
+	router.GET("/api/v1/protected", authMiddleware.Handle(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func(password string) (int, response.LoginResponse) {
+		w := send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: "test@email.com", Password: password})
+
+		var tokens response.LoginResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		}
+		return w.Code, tokens
+	}
+
+	// Emails are sent in the background
+	waitMails := func(count int) {
+		assert.Eventually(t, func() bool {
+			files, _ := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+			return len(files) == count
+		}, 2*time.Second, 20*time.Millisecond)
+	}
+
+	code, session := login("old_password")
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("Unknown Email Looks The Same", func(t *testing.T) {
+		w := send("POST", "/api/v1/authentication/password/forgot", "", request.ForgotPasswordRequest{Email: "unknown@email.com"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		time.Sleep(100 * time.Millisecond)
+		waitMails(0)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		w := send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: "invalid", Password: "new_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Success Reset", func(t *testing.T) {
+		w := send("POST", "/api/v1/authentication/password/forgot", "", request.ForgotPasswordRequest{Email: "test@email.com"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		waitMails(1)
+
+		to, token := lastMail(t, mailDir)
+		assert.Equal(t, "test@email.com", to)
+
+		// only the hash of the token is stored
+		var stored usersDomain.PasswordResetToken
+		require.NoError(t, env.DB.DB.Where("token_hash = ?", utils.HashToken(token)).First(&stored).Error)
+		assert.NotEqual(t, token, stored.TokenHash)
+
+		w = send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: token, Password: "new_password"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, _ := login("old_password")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = login("new_password")
+		assert.Equal(t, http.StatusOK, code)
+
+		// sessions started with the old password are closed
+		w = send("GET", "/api/v1/protected", session.Token, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		// token is single-use
+		w = send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: token, Password: "other_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Earlier Links Are Invalidated", func(t *testing.T) {
+		send("POST", "/api/v1/authentication/password/forgot", "", request.ForgotPasswordRequest{Email: "test@email.com"})
+		waitMails(2)
+		_, first := lastMail(t, mailDir)
+
+		send("POST", "/api/v1/authentication/password/forgot", "", request.ForgotPasswordRequest{Email: "test@email.com"})
+		waitMails(3)
+		_, second := lastMail(t, mailDir)
+
+		w := send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: second, Password: "newest_password"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: first, Password: "other_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		token, record, err := usersDomain.NewPasswordResetToken(fixture.Users[0].Id, -time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, deps.PasswordResetRepository.Save(*record))
+
+		w := send("POST", "/api/v1/authentication/password/reset", "", request.ResetPasswordRequest{Token: token, Password: "new_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Emails Per User Are Limited", func(t *testing.T) {
+		// 3 emails were already sent within the hour
+		w := send("POST", "/api/v1/authentication/password/forgot", "", request.ForgotPasswordRequest{Email: "test@email.com"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		time.Sleep(100 * time.Millisecond)
+		waitMails(3)
+	})
+}
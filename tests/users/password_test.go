@@ -0,0 +1,70 @@
+package users
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"mono_pardo/internal/utils"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	params := utils.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+	argon2id := utils.Argon2idHasher{Params: params}
+	hasher := utils.NewPasswordHasher(argon2id, utils.BcryptHasher{Cost: bcrypt.MinCost})
+
+	t.Run("Argon2id", func(t *testing.T) {
+		hash, err := hasher.Hash("test_password")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+		other, err := hasher.Hash("test_password")
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, other, "salt must be random")
+
+		assert.NoError(t, hasher.Verify(hash, "test_password"))
+		assert.ErrorIs(t, hasher.Verify(hash, "wrong_password"), utils.ErrPasswordMismatch)
+		assert.False(t, hasher.NeedsRehash(hash))
+	})
+
+	t.Run("Legacy Bcrypt", func(t *testing.T) {
+		hash, err := utils.BcryptHasher{Cost: bcrypt.MinCost}.Hash("test_password")
+		require.NoError(t, err)
+
+		assert.NoError(t, hasher.Verify(hash, "test_password"))
+		assert.ErrorIs(t, hasher.Verify(hash, "wrong_password"), utils.ErrPasswordMismatch)
+		assert.True(t, hasher.NeedsRehash(hash))
+	})
+
+	t.Run("Changed Parameters", func(t *testing.T) {
+		hash, err := hasher.Hash("test_password")
+		require.NoError(t, err)
+
+		stronger := utils.NewPasswordHasher(utils.Argon2idHasher{Params: utils.Argon2idParams{Memory: 2048, Iterations: 2, Parallelism: 1}})
+		assert.NoError(t, stronger.Verify(hash, "test_password"), "parameters are read from the hash")
+		assert.True(t, stronger.NeedsRehash(hash))
+	})
+
+	t.Run("Invalid Hashes", func(t *testing.T) {
+		hash, err := hasher.Hash("test_password")
+		require.NoError(t, err)
+
+		for _, invalid := range []string{
+			"",
+			"plain text",
+			"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+			strings.TrimSuffix(hash, hash[strings.LastIndex(hash, "$"):]),
+		} {
+			err := hasher.Verify(invalid, "test_password")
+			assert.Error(t, err, invalid)
+			assert.NotErrorIs(t, err, utils.ErrPasswordMismatch, invalid)
+			assert.True(t, hasher.NeedsRehash(invalid), invalid)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	mailerInfra "mono_pardo/internal/infrastructure/mailer"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestProfile(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword, IsVerified: true},
+			{Username: "other", Email: "other@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	mailDir := t.TempDir()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.Mailer = mailerInfra.NewFileMailerImpl(mailDir, "test@pardo.app")
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/email/confirm", profileController.ConfirmEmailChange)
+	meGroup := router.Group("/api/v1/me", authMiddleware.Handle())
+	meGroup.GET("", profileController.GetProfile)
+	meGroup.PATCH("", profileController.UpdateProfile)
+	meGroup.POST("/password", profileController.ChangePassword)
+	meGroup.POST("/email", profileController.ChangeEmail)
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func(email, password string) (int, response.LoginResponse) {
+		w := send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: email, Password: password})
+
+		var tokens response.LoginResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		}
+		return w.Code, tokens
+	}
+
+	getProfile := func(token string) (int, response.UserResponse) {
+		w := send("GET", "/api/v1/me", token, nil)
+
+		var profile response.UserResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+		}
+		return w.Code, profile
+	}
+
+	_, session := login("test@email.com", "test_password")
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		code, _ := getProfile("")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Get Profile", func(t *testing.T) {
+		code, profile := getProfile(session.Token)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, fixture.Users[0].Id, profile.Id)
+		assert.Equal(t, "test username", profile.Username)
+		assert.Equal(t, "test@email.com", profile.Email)
+		assert.True(t, profile.IsVerified)
+		assert.False(t, profile.CreatedAt.IsZero())
+	})
+
+	t.Run("Update Username", func(t *testing.T) {
+		w := send("PATCH", "/api/v1/me", session.Token, map[string]string{"username": "  new name "})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var profile response.UserResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+		assert.Equal(t, "new name", profile.Username)
+		assert.False(t, profile.UpdatedAt.Before(profile.CreatedAt))
+
+		w = send("PATCH", "/api/v1/me", session.Token, map[string]string{})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("PATCH", "/api/v1/me", session.Token, map[string]string{"username": "a"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Change Password", func(t *testing.T) {
+		_, otherSession := login("test@email.com", "test_password")
+
+		w := send("POST", "/api/v1/me/password", session.Token, request.ChangePasswordRequest{
+			CurrentPassword: "wrong_password", NewPassword: "new_password",
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("POST", "/api/v1/me/password", session.Token, request.ChangePasswordRequest{
+			CurrentPassword: "test_password", NewPassword: "new_password",
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, _ := login("test@email.com", "test_password")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = login("test@email.com", "new_password")
+		assert.Equal(t, http.StatusOK, code)
+
+		// current session stays open, others are closed
+		code, _ = getProfile(session.Token)
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = getProfile(otherSession.Token)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Change Email", func(t *testing.T) {
+		w := send("POST", "/api/v1/me/email", session.Token, request.ChangeEmailRequest{Email: "new@email.com", Password: "wrong_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("POST", "/api/v1/me/email", session.Token, request.ChangeEmailRequest{Email: "other@email.com", Password: "new_password"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = send("POST", "/api/v1/me/email", session.Token, request.ChangeEmailRequest{Email: "new@email.com", Password: "new_password"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		to, token := lastMail(t, mailDir)
+		assert.Equal(t, "new@email.com", to)
+
+		// email isn't changed until it's confirmed
+		_, profile := getProfile(session.Token)
+		assert.Equal(t, "test@email.com", profile.Email)
+
+		w = send("POST", "/api/v1/authentication/email/confirm", "", request.ConfirmEmailChangeRequest{Token: token})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		_, profile = getProfile(session.Token)
+		assert.Equal(t, "new@email.com", profile.Email)
+		assert.True(t, profile.IsVerified)
+
+		// old address is notified
+		to, _ = lastMail(t, mailDir)
+		assert.Equal(t, "test@email.com", to)
+
+		// link is one-time
+		w = send("POST", "/api/v1/authentication/email/confirm", "", request.ConfirmEmailChangeRequest{Token: token})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		code, _ := login("new@email.com", "new_password")
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
@@ -0,0 +1,166 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestRefreshToken(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/refresh", authenticationController.Refresh)
+	authenticationGroup.GET("/sessions", authMiddleware.Handle(), authenticationController.GetSessions)
+	authenticationGroup.DELETE("/sessions/:sessionId", authMiddleware.Handle(), authenticationController.RevokeSession)
+
+	post := func(t *testing.T, path string, payload interface{}) (int, response.LoginResponse) {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		var res response.LoginResponse
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		}
+		return w.Code, res
+	}
+
+	login := func(t *testing.T, device string) response.LoginResponse {
+		code, res := post(t, "/api/v1/authentication/login", request.LoginRequest{
+			Email: "test@email.com", Password: "test_password", Device: device,
+		})
+		require.Equal(t, http.StatusOK, code)
+		return res
+	}
+
+	refresh := func(t *testing.T, token string) (int, response.LoginResponse) {
+		return post(t, "/api/v1/authentication/refresh", request.RefreshTokenRequest{RefreshToken: token})
+	}
+
+	getSessions := func(t *testing.T, accessToken string) []response.SessionResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/authentication/sessions", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var sessions []response.SessionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sessions))
+		return sessions
+	}
+
+	t.Run("Invalid Refresh Token", func(t *testing.T) {
+		code, _ := refresh(t, "unknown-token")
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, _ = refresh(t, "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		tokens := login(t, "laptop")
+
+		code, rotated := refresh(t, tokens.RefreshToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.NotEmpty(t, rotated.Token)
+		assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+
+		code, _ = refresh(t, rotated.RefreshToken)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Reuse Revokes Token Family", func(t *testing.T) {
+		tokens := login(t, "phone")
+
+		code, rotated := refresh(t, tokens.RefreshToken)
+		require.Equal(t, http.StatusOK, code)
+
+		// old token is replayed, e.g. by an attacker
+		code, _ = refresh(t, tokens.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		// legitimate token of the same family doesn't work anymore
+		code, _ = refresh(t, rotated.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Per-Device Revocation", func(t *testing.T) {
+		tablet := login(t, "tablet")
+		desktop := login(t, "desktop")
+
+		sessions := getSessions(t, desktop.Token)
+		var tabletSession string
+		for _, session := range sessions {
+			if session.Device == "tablet" {
+				tabletSession = session.Id
+			}
+		}
+		require.NotEmpty(t, tabletSession)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/authentication/sessions/"+tabletSession, nil)
+		req.Header.Set("Authorization", "Bearer "+desktop.Token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		code, _ := refresh(t, tablet.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, _ = refresh(t, desktop.RefreshToken)
+		assert.Equal(t, http.StatusOK, code)
+
+		for _, session := range getSessions(t, desktop.Token) {
+			assert.NotEqual(t, tabletSession, session.Id)
+		}
+	})
+
+	t.Run("Revoke Unknown Session", func(t *testing.T) {
+		tokens := login(t, "laptop")
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/api/v1/authentication/sessions/unknown", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
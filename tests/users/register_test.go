@@ -7,12 +7,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-playground/validator"
 	"github.com/stretchr/testify/assert"
 
 	"mono_pardo/internal/api/controller"
 	usersDomain "mono_pardo/internal/domain/users"
-	usersInfra "mono_pardo/internal/infrastructure/users"
 	"mono_pardo/pkg/data/request"
 	"mono_pardo/tests"
 )
@@ -23,9 +21,8 @@ func TestRegister(t *testing.T) {
 
 	env.RunMigrations(t)
 
-	userRepository := usersInfra.NewPostgresRepositoryImpl(env.DB.DB)
-	validate := validator.New()
-	authenticationService := usersDomain.NewServiceImpl(testConfig, validate, userRepository)
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
 	authenticationController := controller.NewAuthenticationController(authenticationService)
 
 	router := env.Router
@@ -36,7 +33,7 @@ func TestRegister(t *testing.T) {
 		payload := request.CreateUserRequest{
 			Username: "new user",
 			Email:    "new@email.com",
-			Password: "password123",
+			Password: "correct-horse-staple",
 		}
 		jsonData, _ := json.Marshal(payload)
 
@@ -60,7 +57,7 @@ func TestRegister(t *testing.T) {
 		payload := request.CreateUserRequest{
 			Username: "another user",
 			Email:    "new@email.com",
-			Password: "password123",
+			Password: "correct-horse-staple",
 		}
 		jsonData, _ := json.Marshal(payload)
 
@@ -70,7 +67,42 @@ func TestRegister(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Password Policy", func(t *testing.T) {
+		cases := map[string]struct {
+			password string
+			message  string
+		}{
+			"Too Short":     {"shorty", "password must be at least 8 characters long"},
+			"Common":        {"Password123", "password is too common, please choose another one"},
+			"Same As Email": {"Policy@Email.com", "password must not be the same as the email"},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				payload := request.CreateUserRequest{
+					Username: "policy user",
+					Email:    "policy@email.com",
+					Password: c.password,
+				}
+				jsonData, _ := json.Marshal(payload)
+
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest("POST", "/api/v1/authentication/register", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				assert.Contains(t, w.Body.String(), c.message)
+			})
+		}
+
+		var count int64
+		env.DB.DB.Model(&usersDomain.User{}).Where("email = ?", "policy@email.com").Count(&count)
+		assert.Zero(t, count)
 	})
 
 	t.Run("Invalid JSON", func(t *testing.T) {
@@ -89,7 +121,7 @@ func TestRegister(t *testing.T) {
 		payload := request.CreateUserRequest{
 			Username: "valid name",
 			Email:    "invalid-email-address",
-			Password: "password123",
+			Password: "correct-horse-staple",
 		}
 		jsonData, _ := json.Marshal(payload)
 
@@ -99,6 +131,6 @@ func TestRegister(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 }
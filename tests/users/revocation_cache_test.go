@@ -0,0 +1,107 @@
+package users
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	usersInfra "mono_pardo/internal/infrastructure/users"
+)
+
+// countingStore is in-memory backing store which counts lookups
+type countingStore struct {
+	revoked map[string]time.Time
+	lookups int
+	fail    bool
+}
+
+func (s *countingStore) Revoke(id string, expiresAt time.Time) error {
+	if s.fail {
+		return errors.New("store is down")
+	}
+	s.revoked[id] = expiresAt
+	return nil
+}
+
+func (s *countingStore) IsRevoked(ids ...string) (bool, error) {
+	s.lookups++
+	if s.fail {
+		return false, errors.New("store is down")
+	}
+	for _, id := range ids {
+		if _, ok := s.revoked[id]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCachedRevocationStore(t *testing.T) {
+	t.Run("Revoked Through Cache", func(t *testing.T) {
+		backing := &countingStore{revoked: map[string]time.Time{}}
+		store := usersInfra.NewCachedRevocationStore(backing, time.Minute)
+
+		assert.NoError(t, store.Revoke("jti-1", time.Now().Add(time.Hour)))
+
+		revoked, err := store.IsRevoked("jti-1", "sid-1")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		assert.Equal(t, 0, backing.lookups)
+		assert.Contains(t, backing.revoked, "jti-1")
+	})
+
+	t.Run("Not Revoked Results Are Cached", func(t *testing.T) {
+		backing := &countingStore{revoked: map[string]time.Time{}}
+		store := usersInfra.NewCachedRevocationStore(backing, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			revoked, err := store.IsRevoked("jti-1", "sid-1")
+			assert.NoError(t, err)
+			assert.False(t, revoked)
+		}
+		assert.Equal(t, 2, backing.lookups)
+
+		// revocation made through this instance is visible immediately
+		assert.NoError(t, store.Revoke("sid-1", time.Now().Add(time.Hour)))
+		revoked, err := store.IsRevoked("jti-1", "sid-1")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Revocation by Other Instance Is Noticed After TTL", func(t *testing.T) {
+		backing := &countingStore{revoked: map[string]time.Time{}}
+		store := usersInfra.NewCachedRevocationStore(backing, 20*time.Millisecond)
+
+		revoked, _ := store.IsRevoked("jti-1")
+		assert.False(t, revoked)
+
+		backing.revoked["jti-1"] = time.Now().Add(time.Hour)
+		revoked, _ = store.IsRevoked("jti-1")
+		assert.False(t, revoked)
+
+		time.Sleep(30 * time.Millisecond)
+		revoked, _ = store.IsRevoked("jti-1")
+		assert.True(t, revoked)
+	})
+
+	t.Run("Empty Ids Are Ignored", func(t *testing.T) {
+		backing := &countingStore{revoked: map[string]time.Time{}}
+		store := usersInfra.NewCachedRevocationStore(backing, time.Minute)
+
+		revoked, err := store.IsRevoked("", "")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		assert.Equal(t, 0, backing.lookups)
+	})
+
+	t.Run("Store Errors Are Returned", func(t *testing.T) {
+		backing := &countingStore{revoked: map[string]time.Time{}, fail: true}
+		store := usersInfra.NewCachedRevocationStore(backing, time.Minute)
+
+		_, err := store.IsRevoked("jti-1")
+		assert.Error(t, err)
+		assert.Error(t, store.Revoke("jti-1", time.Now().Add(time.Hour)))
+	})
+}
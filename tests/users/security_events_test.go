@@ -0,0 +1,172 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestSecurityEvents(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "audited", Email: "audited@email.com", Password: hashedPassword, IsVerified: true},
+			{Username: "other", Email: "other@email.com", Password: hashedPassword, IsVerified: true},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	userId := fixture.Users[0].Id
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	router.Use(middleware.RequestIdMiddleware())
+	router.POST("/api/v1/authentication/login", authenticationController.Login)
+	router.POST("/api/v1/authentication/logout/all", authMiddleware.Handle(), authenticationController.LogoutEverywhere)
+	meGroup := router.Group("/api/v1/me", authMiddleware.Handle())
+	meGroup.POST("/password", profileController.ChangePassword)
+	meGroup.GET("/security-events", profileController.GetSecurityEvents)
+
+	send := func(method, path, token, requestId string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "audit-test/1.0")
+		req.RemoteAddr = "203.0.113.7:4321"
+		if requestId != "" {
+			req.Header.Set(middleware.RequestIdHeader, requestId)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func(email, password, requestId string) (int, response.LoginResponse) {
+		w := send("POST", "/api/v1/authentication/login", "", requestId, request.LoginRequest{Email: email, Password: password})
+
+		var tokens response.LoginResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		}
+		return w.Code, tokens
+	}
+
+	getEvents := func(token, query string) (int, response.SecurityEventsResponse) {
+		w := send("GET", "/api/v1/me/security-events"+query, token, "", nil)
+
+		var events response.SecurityEventsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+		}
+		return w.Code, events
+	}
+
+	code, _ := login("audited@email.com", "wrong_password", "failed-login")
+	require.Equal(t, http.StatusBadRequest, code)
+	_, session := login("audited@email.com", "test_password", "good-login")
+	_, otherSession := login("other@email.com", "test_password", "")
+
+	t.Run("Request Id", func(t *testing.T) {
+		w := send("GET", "/api/v1/me/security-events", session.Token, "", nil)
+		assert.Regexp(t, `^[A-Za-z0-9_-]+$`, w.Header().Get(middleware.RequestIdHeader))
+
+		w = send("GET", "/api/v1/me/security-events", session.Token, "proxy-id.42", nil)
+		assert.Equal(t, "proxy-id.42", w.Header().Get(middleware.RequestIdHeader))
+
+		w = send("GET", "/api/v1/me/security-events", session.Token, "not a valid id", nil)
+		assert.NotEqual(t, "not a valid id", w.Header().Get(middleware.RequestIdHeader))
+	})
+
+	t.Run("Logins", func(t *testing.T) {
+		code, events := getEvents(session.Token, "")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events.Events, 2)
+		assert.Equal(t, int64(2), events.Total)
+
+		// the newest first
+		succeeded, failed := events.Events[0], events.Events[1]
+		assert.Equal(t, usersDomain.EventLoginSucceeded, succeeded.Type)
+		assert.Equal(t, "password", succeeded.Details)
+		assert.Equal(t, "good-login", succeeded.RequestId)
+		assert.Equal(t, usersDomain.EventLoginFailed, failed.Type)
+		assert.Equal(t, "failed-login", failed.RequestId)
+
+		for _, event := range events.Events {
+			assert.Equal(t, userId, event.UserId)
+			assert.Equal(t, "203.0.113.7", event.IP)
+			assert.Equal(t, "audit-test/1.0", event.UserAgent)
+		}
+	})
+
+	t.Run("Password Change And Logout", func(t *testing.T) {
+		w := send("POST", "/api/v1/me/password", session.Token, "", request.ChangePasswordRequest{
+			CurrentPassword: "test_password", NewPassword: "correct-horse-staple",
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send("POST", "/api/v1/authentication/logout/all", session.Token, "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		_, session = login("audited@email.com", "correct-horse-staple", "")
+
+		code, events := getEvents(session.Token, "?type="+usersDomain.EventPasswordChanged)
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, events.Events, 1)
+
+		code, events = getEvents(session.Token, "?type="+usersDomain.EventTokenRevoked)
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, events.Events, 1)
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		code, events := getEvents(session.Token, "?limit=2&offset=1")
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, events.Events, 2)
+		assert.Equal(t, 2, events.Limit)
+		assert.Equal(t, int64(5), events.Total)
+
+		for _, query := range []string{"?type=unknown", "?offset=-1", fmt.Sprintf("?limit=%d", usersDomain.MaxSecurityEventsLimit+1)} {
+			code, _ = getEvents(session.Token, query)
+			assert.Equal(t, http.StatusUnprocessableEntity, code, query)
+		}
+	})
+
+	t.Run("Only Own Events", func(t *testing.T) {
+		code, events := getEvents(otherSession.Token, "")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events.Events, 1)
+		assert.Equal(t, usersDomain.EventLoginSucceeded, events.Events[0].Type)
+
+		code, _ = getEvents("", "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -0,0 +1,59 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"mono_pardo/internal/api/controller"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestTokenErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(path string, err error, body interface{}) int {
+		service := new(tests.MockAuthService)
+		service.On("Refresh", mock.Anything).Return(response.LoginResponse{}, err)
+		service.On("VerifyMFA", mock.Anything).Return(response.LoginResponse{}, err)
+
+		authenticationController := controller.NewAuthenticationController(service)
+		router := gin.New()
+		router.POST("/refresh", authenticationController.Refresh)
+		router.POST("/mfa", authenticationController.VerifyMFA)
+
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	refresh := request.RefreshTokenRequest{RefreshToken: "token"}
+	mfa := request.MFALoginRequest{MFAToken: "token", Code: "123456"}
+
+	t.Run("Invalid Tokens Are Unauthorized", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("/refresh", usersDomain.ErrInvalidRefreshToken, refresh))
+		assert.Equal(t, http.StatusUnauthorized, send("/refresh", usersDomain.ErrRefreshTokenReused, refresh))
+		assert.Equal(t, http.StatusUnauthorized, send("/mfa", usersDomain.ErrInvalidMFACode, mfa))
+		assert.Equal(t, http.StatusUnauthorized, send("/mfa", usersDomain.ErrInvalidMFAChallenge, mfa))
+		assert.Equal(t, http.StatusLocked, send("/mfa", &usersDomain.LockedError{}, mfa))
+	})
+
+	t.Run("Other Errors Are Internal", func(t *testing.T) {
+		err := fmt.Errorf("connection refused")
+		assert.Equal(t, http.StatusInternalServerError, send("/refresh", err, refresh))
+		assert.Equal(t, http.StatusInternalServerError, send("/mfa", err, mfa))
+	})
+}
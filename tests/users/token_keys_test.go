@@ -0,0 +1,205 @@
+package users
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestTokenKeys(t *testing.T) {
+	t.Run("RSA And Ed25519 Keys", func(t *testing.T) {
+		dir := t.TempDir()
+		tests.WriteTestKey(t, dir, "ed", tests.GenerateTestKey(t), true)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		writePEM(t, dir, "rsa", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+		for _, kid := range []string{"ed", "rsa"} {
+			keys, err := utils.LoadKeySet(dir, kid)
+			require.NoError(t, err)
+
+			token, err := utils.GenerateToken(time.Minute, 7, "session", usersDomain.RoleUser, keys)
+			require.NoError(t, err)
+			assert.Equal(t, kid, tokenHeader(t, token)["kid"])
+
+			claims, err := utils.ValidateToken(token, keys)
+			require.NoError(t, err)
+			assert.Equal(t, float64(7), claims.Subject)
+			assert.Equal(t, "session", claims.SessionId)
+		}
+
+		keys, err := utils.LoadKeySet(dir, "rsa")
+		require.NoError(t, err)
+		jwks := keys.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, response.JSONWebKey{Kid: "ed", Kty: "OKP", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: jwks.Keys[0].X}, jwks.Keys[0])
+		assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+		assert.Equal(t, "AQAB", jwks.Keys[1].E)
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), jwks.Keys[1].N)
+	})
+
+	t.Run("Invalid Key Directory", func(t *testing.T) {
+		_, err := utils.LoadKeySet(t.TempDir(), "")
+		assert.Error(t, err)
+
+		dir := t.TempDir()
+		tests.WriteTestKey(t, dir, "first", tests.GenerateTestKey(t), true)
+		tests.WriteTestKey(t, dir, "second", tests.GenerateTestKey(t), true)
+		tests.WriteTestKey(t, dir, "public", tests.GenerateTestKey(t), false)
+
+		// the signing key is ambiguous, unknown, or can't sign
+		for _, kid := range []string{"", "third", "public"} {
+			_, err = utils.LoadKeySet(dir, kid)
+			assert.Error(t, err, kid)
+		}
+
+		weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(weakKey)
+		require.NoError(t, err)
+		writePEM(t, dir, "weak", "PRIVATE KEY", der)
+
+		_, err = utils.LoadKeySet(dir, "first")
+		assert.ErrorContains(t, err, "shorter than 2048 bits")
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		oldKey, newKey := tests.GenerateTestKey(t), tests.GenerateTestKey(t)
+		tests.WriteTestKey(t, dir, "2026-01", oldKey, true)
+
+		keys, err := utils.LoadKeySet(dir, "")
+		require.NoError(t, err)
+		oldToken, err := utils.GenerateToken(time.Hour, 1, "session", usersDomain.RoleUser, keys)
+		require.NoError(t, err)
+
+		// the new key is published before it signs tokens
+		tests.WriteTestKey(t, dir, "2026-07", newKey, true)
+		keys, err = utils.LoadKeySet(dir, "2026-01")
+		require.NoError(t, err)
+		assert.Len(t, keys.JWKS().Keys, 2)
+		token, err := utils.GenerateToken(time.Hour, 1, "session", usersDomain.RoleUser, keys)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-01", tokenHeader(t, token)["kid"])
+
+		// the old key only verifies tokens, which were signed with it
+		tests.WriteTestKey(t, dir, "2026-01", oldKey, false)
+		keys, err = utils.LoadKeySet(dir, "2026-07")
+		require.NoError(t, err)
+		token, err = utils.GenerateToken(time.Hour, 1, "session", usersDomain.RoleUser, keys)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-07", tokenHeader(t, token)["kid"])
+		_, err = utils.ValidateToken(oldToken, keys)
+		assert.NoError(t, err)
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.pem")))
+		keys, err = utils.LoadKeySet(dir, "")
+		require.NoError(t, err)
+		_, err = utils.ValidateToken(oldToken, keys)
+		assert.Error(t, err)
+		_, err = utils.ValidateToken(token, keys)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Forged Tokens", func(t *testing.T) {
+		keys := tests.NewTestKeySet(t)
+		now := time.Now().Unix()
+		claims := jwt.MapClaims{"sub": 1, "exp": now + 60, "iat": now}
+
+		// public key used as HMAC secret
+		hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		hmacToken.Header["kid"] = "test-key"
+		x, err := base64.RawURLEncoding.DecodeString(keys.JWKS().Keys[0].X)
+		require.NoError(t, err)
+		forged, err := hmacToken.SignedString(x)
+		require.NoError(t, err)
+		_, err = utils.ValidateToken(forged, keys)
+		assert.Error(t, err)
+
+		// token of another key with the same id
+		otherToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		otherToken.Header["kid"] = "test-key"
+		forged, err = otherToken.SignedString(tests.GenerateTestKey(t))
+		require.NoError(t, err)
+		_, err = utils.ValidateToken(forged, keys)
+		assert.Error(t, err)
+
+		// token signed with the shared secret after the switch to keys
+		legacy, err := utils.GenerateToken(time.Minute, 1, "session", usersDomain.RoleUser, utils.NewHMACKeySet("secret"))
+		require.NoError(t, err)
+		_, err = utils.ValidateToken(legacy, keys)
+		assert.Error(t, err)
+		assert.Empty(t, utils.NewHMACKeySet("secret").JWKS().Keys)
+	})
+
+	t.Run("JWKS Endpoint", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		keys := tests.NewTestKeySet(t)
+		authenticationService := usersDomain.NewServiceImpl(usersDomain.Deps{Keys: keys, PasswordHasher: utils.DefaultPasswordHasher})
+		authenticationController := controller.NewAuthenticationController(authenticationService)
+
+		router := gin.New()
+		router.GET("/.well-known/jwks.json", authenticationController.JWKS)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+		var jwks response.JWKSResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+		require.Len(t, jwks.Keys, 1)
+
+		// another service verifies our token with the published key only
+		token, err := utils.GenerateToken(time.Minute, 5, "session", usersDomain.RoleUser, keys)
+		require.NoError(t, err)
+
+		x, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+		require.NoError(t, err)
+		parsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+			assert.Equal(t, jwks.Keys[0].Kid, token.Header["kid"])
+			return ed25519.PublicKey(x), nil
+		})
+		require.NoError(t, err)
+		assert.True(t, parsed.Valid)
+	})
+}
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600))
+}
+
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	return parsed.Header
+}
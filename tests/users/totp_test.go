@@ -0,0 +1,102 @@
+package users
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"hash"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/utils"
+)
+
+func TestTOTP(t *testing.T) {
+	t.Run("RFC 6238 Test Vectors", func(t *testing.T) {
+		seeds := map[string]struct {
+			key       []byte
+			algorithm func() hash.Hash
+		}{
+			"SHA1":   {[]byte("12345678901234567890"), sha1.New},
+			"SHA256": {[]byte("12345678901234567890123456789012"), sha256.New},
+			"SHA512": {[]byte("1234567890123456789012345678901234567890123456789012345678901234"), sha512.New},
+		}
+
+		vectors := []struct {
+			unix     int64
+			mode     string
+			expected string
+		}{
+			{59, "SHA1", "94287082"},
+			{59, "SHA256", "46119246"},
+			{59, "SHA512", "90693936"},
+			{1111111109, "SHA1", "07081804"},
+			{1111111109, "SHA256", "68084774"},
+			{1111111109, "SHA512", "25091201"},
+			{1111111111, "SHA1", "14050471"},
+			{1111111111, "SHA256", "67062674"},
+			{1111111111, "SHA512", "99943326"},
+			{1234567890, "SHA1", "89005924"},
+			{1234567890, "SHA256", "91819424"},
+			{1234567890, "SHA512", "93441116"},
+			{2000000000, "SHA1", "69279037"},
+			{2000000000, "SHA256", "90698825"},
+			{2000000000, "SHA512", "38618901"},
+			{20000000000, "SHA1", "65353130"},
+			{20000000000, "SHA256", "77737706"},
+			{20000000000, "SHA512", "47863826"},
+		}
+
+		for _, vector := range vectors {
+			seed := seeds[vector.mode]
+			code := utils.GenerateTOTP(seed.key, time.Unix(vector.unix, 0), 30*time.Second, 8, seed.algorithm)
+			assert.Equal(t, vector.expected, code, "%s at %d", vector.mode, vector.unix)
+		}
+	})
+
+	t.Run("Validate With Clock Drift", func(t *testing.T) {
+		secret, err := utils.GenerateTOTPSecret()
+		require.NoError(t, err)
+
+		key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+		require.NoError(t, err)
+		assert.Len(t, key, 20)
+
+		now := time.Unix(1700000000, 0)
+		code := utils.GenerateTOTP(key, now, utils.TOTPPeriod, utils.TOTPDigits, sha1.New)
+
+		counter, ok := utils.ValidateTOTP(secret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, now.Unix()/30, counter)
+
+		_, ok = utils.ValidateTOTP(secret, code, now.Add(30*time.Second))
+		assert.True(t, ok)
+		_, ok = utils.ValidateTOTP(secret, code, now.Add(-30*time.Second))
+		assert.True(t, ok)
+		_, ok = utils.ValidateTOTP(secret, code, now.Add(90*time.Second))
+		assert.False(t, ok)
+
+		_, ok = utils.ValidateTOTP(secret, "12345", now)
+		assert.False(t, ok)
+		_, ok = utils.ValidateTOTP("not base32!", code, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("URI", func(t *testing.T) {
+		uri := utils.TOTPURI("Pardo", "teacher@school.org", "JBSWY3DPEHPK3PXP")
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		assert.Equal(t, "otpauth", parsed.Scheme)
+		assert.Equal(t, "totp", parsed.Host)
+		assert.Equal(t, "/Pardo:teacher@school.org", parsed.Path)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+		assert.Equal(t, "Pardo", parsed.Query().Get("issuer"))
+		assert.Equal(t, "6", parsed.Query().Get("digits"))
+	})
+}
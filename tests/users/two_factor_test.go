@@ -0,0 +1,205 @@
+package users
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestTwoFactor(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	hashedPassword, _ := utils.HashPassword("test_password")
+	fixture := &tests.UserFixture{
+		Users: []usersDomain.User{
+			{Username: "test username", Email: "test@email.com", Password: hashedPassword},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	testConfig.LoginMaxFailures = 3
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+	profileController := controller.NewProfileController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/mfa", authenticationController.VerifyMFA)
+	meGroup := router.Group("/api/v1/me", authMiddleware.Handle())
+	meGroup.GET("", profileController.GetProfile)
+	meGroup.POST("/2fa/enroll", profileController.EnrollTwoFactor)
+	meGroup.POST("/2fa/confirm", profileController.ConfirmTwoFactor)
+	meGroup.POST("/2fa/disable", profileController.DisableTwoFactor)
+
+	send := func(path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	login := func() response.LoginResponse {
+		w := send("/api/v1/authentication/login", "", request.LoginRequest{Email: "test@email.com", Password: "test_password"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res response.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res
+	}
+
+	verifyMFA := func(mfaToken, code string) *httptest.ResponseRecorder {
+		return send("/api/v1/authentication/mfa", "", request.MFALoginRequest{MFAToken: mfaToken, Code: code})
+	}
+
+	var secret string
+	codeAt := func(at time.Time) string {
+		key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+		require.NoError(t, err)
+		return utils.GenerateTOTP(key, at, utils.TOTPPeriod, utils.TOTPDigits, sha1.New)
+	}
+
+	session := login()
+	var recoveryCodes []string
+
+	t.Run("Enrol", func(t *testing.T) {
+		w := send("/api/v1/me/2fa/enroll", session.Token, request.EnrollTwoFactorRequest{Password: "wrong_password"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("/api/v1/me/2fa/enroll", session.Token, request.EnrollTwoFactorRequest{Password: "test_password"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var enrollment response.TwoFactorEnrollmentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrollment))
+		secret = enrollment.Secret
+		assert.NotEmpty(t, secret)
+		assert.Contains(t, enrollment.URI, "otpauth://totp/Pardo:test@email.com?")
+		assert.Contains(t, enrollment.URI, "secret="+secret)
+
+		// 2FA isn't required until it's confirmed
+		assert.NotEmpty(t, login().Token)
+	})
+
+	t.Run("Confirm", func(t *testing.T) {
+		w := send("/api/v1/me/2fa/confirm", session.Token, request.ConfirmTwoFactorRequest{Code: "000000"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("/api/v1/me/2fa/confirm", session.Token, request.ConfirmTwoFactorRequest{Code: codeAt(time.Now())})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var codes response.RecoveryCodesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &codes))
+		assert.Len(t, codes.RecoveryCodes, 10)
+		recoveryCodes = codes.RecoveryCodes
+
+		// only hashes are stored
+		var stored usersDomain.RecoveryCode
+		require.NoError(t, env.DB.DB.Where("code_hash = ?", utils.HashToken(strings.ReplaceAll(recoveryCodes[0], "-", ""))).First(&stored).Error)
+
+		// can't enrol again while 2FA is enabled
+		w = send("/api/v1/me/2fa/enroll", session.Token, request.EnrollTwoFactorRequest{Password: "test_password"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Login Requires Code", func(t *testing.T) {
+		challenge := login()
+		assert.True(t, challenge.MFARequired)
+		assert.NotEmpty(t, challenge.MFAToken)
+		assert.Empty(t, challenge.Token)
+		assert.Empty(t, challenge.RefreshToken)
+
+		// challenge isn't an access token
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/me", nil)
+		req.Header.Set("Authorization", "Bearer "+challenge.MFAToken)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA("invalid", codeAt(time.Now())).Code)
+
+		// the code used for confirmation can't be used again
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA(challenge.MFAToken, codeAt(time.Now())).Code)
+
+		code := codeAt(time.Now().Add(utils.TOTPPeriod))
+		w = verifyMFA(challenge.MFAToken, code)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var tokens response.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		assert.NotEmpty(t, tokens.Token)
+		assert.NotEmpty(t, tokens.RefreshToken)
+
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA(challenge.MFAToken, code).Code)
+	})
+
+	t.Run("Recovery Code Is Single-Use", func(t *testing.T) {
+		challenge := login()
+
+		assert.Equal(t, http.StatusOK, verifyMFA(challenge.MFAToken, recoveryCodes[0]).Code)
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA(challenge.MFAToken, recoveryCodes[0]).Code)
+	})
+
+	t.Run("Wrong Codes Lock The Account", func(t *testing.T) {
+		failuresKey := "mfa_failures:" + strconv.Itoa(fixture.Users[0].Id)
+		require.NoError(t, deps.CounterStore.Reset(failuresKey))
+		challenge := login()
+
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA(challenge.MFAToken, "111111").Code)
+		assert.Equal(t, http.StatusUnauthorized, verifyMFA(challenge.MFAToken, "222222").Code)
+		assert.Equal(t, http.StatusLocked, verifyMFA(challenge.MFAToken, "333333").Code)
+
+		// even the right code doesn't help until the lockout is over
+		assert.Equal(t, http.StatusLocked, verifyMFA(challenge.MFAToken, recoveryCodes[1]).Code)
+
+		require.NoError(t, deps.CounterStore.Reset(failuresKey))
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		w := send("/api/v1/me/2fa/disable", session.Token, request.DisableTwoFactorRequest{Password: "wrong_password", Code: recoveryCodes[2]})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("/api/v1/me/2fa/disable", session.Token, request.DisableTwoFactorRequest{Password: "test_password", Code: "444444"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = send("/api/v1/me/2fa/disable", session.Token, request.DisableTwoFactorRequest{Password: "test_password", Code: recoveryCodes[2]})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotEmpty(t, login().Token)
+
+		var count int64
+		env.DB.DB.Model(&usersDomain.RecoveryCode{}).Where("user_id = ?", fixture.Users[0].Id).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
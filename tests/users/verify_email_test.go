@@ -0,0 +1,151 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	usersDomain "mono_pardo/internal/domain/users"
+	mailerInfra "mono_pardo/internal/infrastructure/mailer"
+	"mono_pardo/pkg/data/request"
+	"mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+var linkToken = regexp.MustCompile(`token=(\S+)`)
+
+// lastMail returns recipient and token from the link (if any) of the latest email written by file mailer
+func lastMail(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no emails were sent")
+	sort.Strings(files)
+
+	content, err := os.ReadFile(files[len(files)-1])
+	require.NoError(t, err)
+
+	to := regexp.MustCompile(`To: (\S+)`).FindSubmatch(content)
+	require.NotNil(t, to)
+
+	match := linkToken.FindSubmatch(content)
+	if match == nil {
+		return string(to[1]), ""
+	}
+
+	token, err := url.QueryUnescape(string(match[1]))
+	require.NoError(t, err)
+
+	return string(to[1]), token
+}
+
+func TestVerifyEmail(t *testing.T) {
+	env, testConfig := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mailDir := t.TempDir()
+
+	deps := tests.NewUsersDeps(t, env, testConfig)
+	deps.Mailer = mailerInfra.NewFileMailerImpl(mailDir, "test@pardo.app")
+	authenticationService := usersDomain.NewServiceImpl(deps)
+	authenticationController := controller.NewAuthenticationController(authenticationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(authenticationService)
+	verifiedMiddleware := middleware.NewVerifiedMiddleware(authenticationService)
+
+	router := env.Router
+	authenticationGroup := router.Group("/api/v1/authentication")
+	authenticationGroup.POST("/register", authenticationController.Register)
+	authenticationGroup.POST("/login", authenticationController.Login)
+	authenticationGroup.POST("/verify", authenticationController.VerifyEmail)
+	authenticationGroup.POST("/verify/resend", authMiddleware.Handle(), authenticationController.ResendVerification)
+	router.GET("/api/v1/vocab", authMiddleware.Handle(), verifiedMiddleware.Handle(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	send := func(method, path, token string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/api/v1/authentication/register", "", request.CreateUserRequest{
+		Username: "reader", Email: "reader@email.com", Password: "correct-horse-staple",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = send("POST", "/api/v1/authentication/login", "", request.LoginRequest{Email: "reader@email.com", Password: "correct-horse-staple"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var tokens response.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+
+	t.Run("Verification Email Is Sent on Registration", func(t *testing.T) {
+		to, token := lastMail(t, mailDir)
+		assert.Equal(t, "reader@email.com", to)
+		assert.NotEmpty(t, token)
+	})
+
+	t.Run("Unverified User Is Blocked", func(t *testing.T) {
+		w := send("GET", "/api/v1/vocab", tokens.Token, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		w := send("POST", "/api/v1/authentication/verify", "", request.VerifyEmailRequest{Token: "invalid"})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		// access token can't be used as verification token
+		w = send("POST", "/api/v1/authentication/verify", "", request.VerifyEmailRequest{Token: tokens.Token})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Resend", func(t *testing.T) {
+		before, _ := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+
+		w := send("POST", "/api/v1/authentication/verify/resend", tokens.Token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		after, _ := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+		assert.Len(t, after, len(before)+1)
+	})
+
+	t.Run("Success Verification", func(t *testing.T) {
+		_, token := lastMail(t, mailDir)
+
+		w := send("POST", "/api/v1/authentication/verify", "", request.VerifyEmailRequest{Token: token})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send("GET", "/api/v1/vocab", tokens.Token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// link is one-time
+		w = send("POST", "/api/v1/authentication/verify", "", request.VerifyEmailRequest{Token: token})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = send("POST", "/api/v1/authentication/verify/resend", tokens.Token, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator"
+
+	usersDomain "mono_pardo/internal/domain/users"
+	mailerInfra "mono_pardo/internal/infrastructure/mailer"
+	usersInfra "mono_pardo/internal/infrastructure/users"
+	"mono_pardo/internal/utils"
+	"mono_pardo/pkg/config"
+)
+
+// NewUsersDeps wires the users service to the test database, tests replace the fields they need to differ
+func NewUsersDeps(t *testing.T, env *TestEnv, config config.Config) usersDomain.Deps {
+	t.Helper()
+
+	return usersDomain.Deps{
+		Config:                  config,
+		Keys:                    NewTestKeySet(t),
+		PasswordHasher:          utils.DefaultPasswordHasher,
+		Validate:                validator.New(),
+		Repository:              usersInfra.NewPostgresRepositoryImpl(env.DB.DB),
+		RefreshTokenRepository:  usersInfra.NewPostgresRefreshTokenRepositoryImpl(env.DB.DB),
+		RevocationStore:         usersInfra.NewPostgresRevocationStoreImpl(env.DB.DB),
+		PasswordResetRepository: usersInfra.NewPostgresPasswordResetRepositoryImpl(env.DB.DB),
+		CounterStore:            usersInfra.NewMemoryCounterStore(),
+		TwoFactorRepository:     usersInfra.NewPostgresTwoFactorRepositoryImpl(env.DB.DB),
+		IdentityRepository:      usersInfra.NewPostgresIdentityRepositoryImpl(env.DB.DB),
+		AccessTokenRepository:   usersInfra.NewPostgresAccessTokenRepositoryImpl(env.DB.DB),
+		SecurityEventRepository: usersInfra.NewPostgresSecurityEventRepositoryImpl(env.DB.DB),
+		Mailer:                  mailerInfra.NewFileMailerImpl(t.TempDir(), "test@pardo.app"),
+	}
+}
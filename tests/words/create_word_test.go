@@ -27,7 +27,7 @@ func TestCreateWord(t *testing.T) {
 
 	env.RunMigrations(t)
 
-	mockAuthService := &MockAuthService{}
+	mockAuthService := &tests.MockAuthService{}
 	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
 	mockAuthService.On("GetUserId", "test-token-user2").Return(2, nil)
 	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
@@ -108,7 +108,7 @@ func TestCreateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("Missing Word", func(t *testing.T) {
@@ -124,7 +124,7 @@ func TestCreateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("Duplicate Word for Same User", func(t *testing.T) {
@@ -141,7 +141,7 @@ func TestCreateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusConflict, w.Code)
 	})
 
 	t.Run("Same Word Different User", func(t *testing.T) {
@@ -183,12 +183,12 @@ func TestCreateWord(t *testing.T) {
 
 		router.ServeHTTP(checkW, checkReq)
 
-		var response []resp.VocabResponse
+		var response resp.VocabPageResponse
 		err := json.Unmarshal(checkW.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
 		found := false
-		for _, word := range response {
+		for _, word := range response.Words {
 			if word.Word == "newword" {
 				found = true
 				assert.Equal(t, "brand new word definition", word.Definition)
@@ -25,7 +25,7 @@ func TestDeleteWord(t *testing.T) {
 
 	env.RunMigrations(t)
 
-	mockAuthService := &MockAuthService{}
+	mockAuthService := &tests.MockAuthService{}
 	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
 	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
 
@@ -99,7 +99,7 @@ func TestDeleteWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
 	t.Run("Attempt to Delete Other User's Word", func(t *testing.T) {
@@ -109,7 +109,7 @@ func TestDeleteWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("Success Delete Word", func(t *testing.T) {
@@ -128,9 +128,9 @@ func TestDeleteWord(t *testing.T) {
 
 		router.ServeHTTP(checkW, checkReq)
 
-		var response []resp.VocabResponse
+		var response resp.VocabPageResponse
 		err := json.Unmarshal(checkW.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Len(t, response, 0)
+		assert.Len(t, response.Words, 0)
 	})
 }
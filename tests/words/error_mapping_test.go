@@ -0,0 +1,83 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	apiErrors "mono_pardo/internal/api/errors"
+	usersDomain "mono_pardo/internal/domain/users"
+	wordsDomain "mono_pardo/internal/domain/words"
+	"mono_pardo/pkg/data/request"
+)
+
+func TestErrorMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(err error) (int, apiErrors.APIError) {
+		router := gin.New()
+		router.GET("/", func(ctx *gin.Context) {
+			controller.SendServiceError(ctx, err)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		router.ServeHTTP(w, req)
+
+		var apiErr apiErrors.APIError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+		return w.Code, apiErr
+	}
+
+	t.Run("Kinds", func(t *testing.T) {
+		code, apiErr := send(wordsDomain.ErrWordNotFound)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, apiErrors.NotFoundError, apiErr.Type)
+		assert.Equal(t, "word_not_found", apiErr.Code)
+
+		code, apiErr = send(wordsDomain.ErrWordExists)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, apiErrors.ConflictError, apiErr.Type)
+
+		code, _ = send(usersDomain.ErrAccountSuspended)
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, apiErr = send(usersDomain.ErrWrongPassword)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, "wrong_password", apiErr.Code)
+	})
+
+	t.Run("Wrapped Error", func(t *testing.T) {
+		code, apiErr := send(fmt.Errorf("cannot delete: %w", usersDomain.ErrUserNotFound))
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "user_not_found", apiErr.Code)
+	})
+
+	t.Run("Validator Fields", func(t *testing.T) {
+		err := validator.New().Struct(request.ChangePasswordRequest{})
+		require.Error(t, err)
+
+		code, apiErr := send(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, apiErrors.ValidationError, apiErr.Type)
+		assert.Equal(t, "invalid_request", apiErr.Code)
+		assert.Contains(t, apiErr.Fields, "current_password")
+		assert.Contains(t, apiErr.Fields, "new_password")
+	})
+
+	t.Run("Other Errors Are Internal", func(t *testing.T) {
+		code, apiErr := send(fmt.Errorf("cannot update user: connection refused"))
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, apiErrors.InternalError, apiErr.Type)
+		assert.Empty(t, apiErr.Code)
+		assert.NotContains(t, apiErr.Message, "connection refused")
+	})
+}
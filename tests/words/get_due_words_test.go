@@ -0,0 +1,141 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestGetDueWords(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	now := time.Now()
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{
+				UserId:     1,
+				Word:       "training",
+				Definition: "still in first-pass stage",
+				IsLearned:  false,
+				DueAt:      now.Add(-48 * time.Hour),
+			},
+			{
+				UserId:     1,
+				Word:       "overdue",
+				Definition: "due two days ago",
+				IsLearned:  true,
+				DueAt:      now.Add(-48 * time.Hour),
+			},
+			{
+				UserId:     1,
+				Word:       "today",
+				Definition: "due an hour ago",
+				IsLearned:  true,
+				DueAt:      now.Add(-time.Hour),
+			},
+			{
+				UserId:     1,
+				Word:       "tomorrow",
+				Definition: "due tomorrow",
+				IsLearned:  true,
+				DueAt:      now.Add(24 * time.Hour),
+			},
+			{
+				UserId:     2,
+				Word:       "foreign",
+				Definition: "other user's word",
+				IsLearned:  true,
+				DueAt:      now.Add(-time.Hour),
+			},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	vocabController := controller.NewVocabController(vocabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.GET("/due", vocabController.GetDueWords)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/due", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/due?limit=abc", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Success Get Due Words", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/due", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []resp.VocabResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+
+		// the most overdue word goes first
+		assert.Equal(t, "overdue", response[0].Word)
+		assert.Equal(t, "today", response[1].Word)
+	})
+
+	t.Run("Limit Due Words", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/due?limit=1", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []resp.VocabResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "overdue", response[0].Word)
+	})
+}
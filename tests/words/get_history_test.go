@@ -0,0 +1,219 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestGetHistory(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{
+				UserId:     1,
+				Word:       "hello",
+				Definition: "greeting",
+			},
+			{
+				UserId:     2,
+				Word:       "world",
+				Definition: "planet earth",
+			},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	now := time.Now()
+
+	attemptFixture := &tests.AttemptFixture{
+		Attempts: []wordsDomain.TrainingAttempt{
+			{UserId: 2, WordId: 2, Exercise: "cards", IsCorrect: true, CreatedAt: now.Add(-time.Hour)},
+		},
+	}
+	attemptCleanup := env.WithFixture(t, attemptFixture)
+	defer attemptCleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	vocabController := controller.NewVocabController(vocabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.PATCH("", vocabController.UpdateWord)
+	vocabGroup.GET("/:wordId/history", vocabController.GetHistory)
+
+	train := func(field string, value bool, responseTimeMs int) {
+		payload := []map[string]interface{}{
+			{
+				"id": 1,
+				"updates": []map[string]interface{}{
+					{
+						"field":            field,
+						"value":            value,
+						"response_time_ms": responseTimeMs,
+					},
+				},
+			},
+		}
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "PATCH", "/api/v1/vocab", payload)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/1/history", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Attempt to Get Other User's History", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/2/history", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Invalid Pagination", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/1/history?offset=-1", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Negative Response Time", func(t *testing.T) {
+		payload := []map[string]interface{}{
+			{
+				"id": 1,
+				"updates": []map[string]interface{}{
+					{"field": "cards", "value": true, "response_time_ms": -5},
+				},
+			},
+		}
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "PATCH", "/api/v1/vocab", payload)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Non Training Updates Are Not Recorded", func(t *testing.T) {
+		payload := []map[string]interface{}{
+			{
+				"id": 1,
+				"updates": []map[string]interface{}{
+					{"field": "definition", "value": "new greeting"},
+				},
+			},
+		}
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "PATCH", "/api/v1/vocab", payload)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var count int64
+		err := env.DB.DB.Model(&wordsDomain.TrainingAttempt{}).Where("word_id = ?", 1).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Success Get History", func(t *testing.T) {
+		train("cards", false, 3200)
+		train("cards", true, 1500)
+		train("word_audio", true, 800)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/1/history", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response resp.HistoryResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), response.Total)
+		assert.Len(t, response.Attempts, 3)
+
+		// the latest attempt goes first
+		assert.Equal(t, "word_audio", response.Attempts[0].Exercise)
+		assert.True(t, response.Attempts[0].IsCorrect)
+		assert.Equal(t, 800, response.Attempts[0].ResponseTimeMs)
+		assert.Equal(t, "cards", response.Attempts[2].Exercise)
+		assert.False(t, response.Attempts[2].IsCorrect)
+		assert.Equal(t, 3200, response.Attempts[2].ResponseTimeMs)
+	})
+
+	t.Run("Paginated History", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/1/history?limit=2&offset=2", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response resp.HistoryResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), response.Total)
+		assert.Equal(t, 2, response.Limit)
+		assert.Equal(t, 2, response.Offset)
+		assert.Len(t, response.Attempts, 1)
+		assert.Equal(t, "cards", response.Attempts[0].Exercise)
+	})
+
+	t.Run("Repeated Result Is Recorded", func(t *testing.T) {
+		train("word_audio", true, 600)
+
+		var count int64
+		err := env.DB.DB.Model(&wordsDomain.TrainingAttempt{}).Where("word_id = ?", 1).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), count)
+	})
+}
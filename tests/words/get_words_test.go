@@ -25,7 +25,7 @@ func TestGetWords(t *testing.T) {
 
 	env.RunMigrations(t)
 
-	mockAuthService := &MockAuthService{}
+	mockAuthService := &tests.MockAuthService{}
 	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
 	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
 
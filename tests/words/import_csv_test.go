@@ -0,0 +1,240 @@
+package words
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	"mono_pardo/internal/infrastructure/delimited"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestImportCSV(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "hello", Definition: "greeting", Tags: "basics"},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	importService := wordsDomain.NewImportServiceImpl(vocabService, wordsInfra.NewFileParserImpl())
+	importController := controller.NewImportController(importService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.POST("/import/csv", importController.ImportCSV)
+
+	importFile := func(t *testing.T, content string, fields map[string]string) (int, resp.ImportReport) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for key, value := range fields {
+			assert.NoError(t, writer.WriteField(key, value))
+		}
+		if content != "" {
+			part, err := writer.CreateFormFile("file", "words.csv")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.Close())
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/csv", body)
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		router.ServeHTTP(w, req)
+
+		var report resp.ImportReport
+		if w.Code == http.StatusOK {
+			err := json.Unmarshal(w.Body.Bytes(), &report)
+			assert.NoError(t, err)
+		}
+		return w.Code, report
+	}
+
+	countWords := func(t *testing.T) int64 {
+		var count int64
+		err := env.DB.DB.Model(&wordsDomain.Word{}).Where("user_id = ?", 1).Count(&count).Error
+		assert.NoError(t, err)
+		return count
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/import/csv", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		code, _ := importFile(t, "", map[string]string{"dry_run": "true"})
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid Settings", func(t *testing.T) {
+		code, _ := importFile(t, "word,definition\ncat,animal\n", map[string]string{"on_conflict": "replace"})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, _ = importFile(t, "word,definition\ncat,animal\n", map[string]string{"delimiter": "#"})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, _ = importFile(t, "word,definition\ncat,animal\n", map[string]string{"word_column": "term", "definition_column": "definition"})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("Dry Run Reports Errors", func(t *testing.T) {
+		content := "word,definition\ncat,small animal\n,no word\ndog,\ncat,duplicate\nhello,hi\n"
+
+		code, report := importFile(t, content, map[string]string{"dry_run": "true"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 5, report.Total)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 3, report.Failed)
+
+		assert.Len(t, report.Errors, 3)
+		assert.Equal(t, 3, report.Errors[0].Line)
+		assert.Equal(t, "word is required field", report.Errors[0].Message)
+		assert.Equal(t, 4, report.Errors[1].Line)
+		assert.Equal(t, "definition is required field", report.Errors[1].Message)
+		assert.Equal(t, 5, report.Errors[2].Line)
+
+		assert.Len(t, report.Duplicates, 1)
+		assert.Equal(t, "hello", report.Duplicates[0].Word)
+		assert.Equal(t, "skipped", report.Duplicates[0].Resolution)
+
+		// nothing is saved in dry-run mode
+		assert.Equal(t, int64(1), countWords(t))
+	})
+
+	t.Run("Import TSV With Column Mapping", func(t *testing.T) {
+		content := "travel\tcat\tsmall animal\tpet\n\tdog\tloyal animal\tpet\n"
+
+		code, report := importFile(t, content, map[string]string{
+			"delimiter":         "tab",
+			"has_header":        "false",
+			"word_column":       "1",
+			"definition_column": "2",
+			"tags_column":       "3",
+		})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 0, report.Failed)
+		assert.Equal(t, int64(3), countWords(t))
+
+		var word wordsDomain.Word
+		err := env.DB.DB.Where("user_id = ? AND word = ?", 1, "dog").First(&word).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "loyal animal", word.Definition)
+		assert.Equal(t, "pet", word.Tags)
+	})
+
+	t.Run("Merge Definitions", func(t *testing.T) {
+		content := "Word,Definition,Tags,Notes\nhello,salutation,\"basics, greetings\",informal\n"
+
+		code, report := importFile(t, content, map[string]string{"on_conflict": "merge"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, "merged", report.Duplicates[0].Resolution)
+
+		var word wordsDomain.Word
+		err := env.DB.DB.Where("user_id = ? AND word = ?", 1, "hello").First(&word).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "greeting; salutation", word.Definition)
+		assert.Equal(t, "basics greetings", word.Tags)
+		assert.Equal(t, "informal", word.Notes)
+	})
+
+	t.Run("Overwrite Definitions", func(t *testing.T) {
+		content := "word;definition\nhello;a polite word\n"
+
+		code, report := importFile(t, content, map[string]string{"on_conflict": "overwrite", "delimiter": ";"})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, "overwritten", report.Duplicates[0].Resolution)
+
+		var word wordsDomain.Word
+		err := env.DB.DB.Where("user_id = ? AND word = ?", 1, "hello").First(&word).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "a polite word", word.Definition)
+		assert.Equal(t, "", word.Tags)
+	})
+}
+
+func TestParseDelimited(t *testing.T) {
+	t.Run("Header Names", func(t *testing.T) {
+		content := "Notes,Definition,Word\n\"multi\nline\",\"big, grey animal\",elephant\n\n"
+
+		rows, err := delimited.Parse(strings.NewReader(content), delimited.Options{Delimiter: ',', HasHeader: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, "elephant", rows[0].Word)
+		assert.Equal(t, "big, grey animal", rows[0].Definition)
+		assert.Equal(t, "multi\nline", rows[0].Notes)
+	})
+
+	t.Run("First Columns Without Header", func(t *testing.T) {
+		content := "cat\tanimal\ndog\n\tempty word\n"
+
+		rows, err := delimited.Parse(strings.NewReader(content), delimited.Options{Delimiter: '\t'})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "animal", rows[0].Definition)
+		assert.Equal(t, "", rows[1].Definition)
+		assert.Equal(t, "", rows[2].Word)
+		assert.Equal(t, "empty word", rows[2].Definition)
+	})
+
+	t.Run("Unknown Column", func(t *testing.T) {
+		content := "word,definition\ncat,animal\n"
+
+		_, err := delimited.Parse(strings.NewReader(content), delimited.Options{
+			Delimiter: ',',
+			HasHeader: true,
+			Mapping:   delimited.Mapping{Word: "word", Definition: "meaning"},
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,197 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestPaginateWords(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "delta", Definition: "d", CreatedAt: start, IsLearned: true, Cards: true},
+			{UserId: 1, Word: "alpha", Definition: "a", CreatedAt: start.Add(time.Hour), Cards: true},
+			{UserId: 1, Word: "echo", Definition: "e", CreatedAt: start.Add(2 * time.Hour)},
+			{UserId: 1, Word: "charlie", Definition: "c", CreatedAt: start.Add(3 * time.Hour), IsLearned: true},
+			{UserId: 1, Word: "bravo", Definition: "b", CreatedAt: start.Add(4 * time.Hour)},
+			{UserId: 2, Word: "foxtrot", Definition: "f", CreatedAt: start},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	vocabController := controller.NewVocabController(vocabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.GET("", vocabController.GetWords)
+
+	getPage := func(t *testing.T, query url.Values) (int, resp.VocabPageResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab?"+query.Encode(), nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		var response resp.VocabPageResponse
+		if w.Code == http.StatusOK {
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+		}
+		return w.Code, response
+	}
+
+	wordsOf := func(page resp.VocabPageResponse) []string {
+		words := []string{}
+		for _, word := range page.Words {
+			words = append(words, word.Word)
+		}
+		return words
+	}
+
+	t.Run("Default Page", func(t *testing.T) {
+		code, page := getPage(t, url.Values{"limit": {"0"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"delta", "alpha", "echo", "charlie", "bravo"}, wordsOf(page))
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Without Pagination", func(t *testing.T) {
+		// clients which don't paginate get an array of all words, filters and sorting still work
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab?sort=word&is_learned=false", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var words []resp.VocabResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &words))
+		assert.Equal(t, []string{"alpha", "bravo", "echo"}, wordsOf(resp.VocabPageResponse{Words: words}))
+	})
+
+	t.Run("Follow Cursor", func(t *testing.T) {
+		code, first := getPage(t, url.Values{"limit": {"2"}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"delta", "alpha"}, wordsOf(first))
+		assert.True(t, first.HasMore)
+		assert.NotEmpty(t, first.NextCursor)
+
+		code, second := getPage(t, url.Values{"limit": {"2"}, "cursor": {first.NextCursor}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"echo", "charlie"}, wordsOf(second))
+		assert.True(t, second.HasMore)
+
+		code, third := getPage(t, url.Values{"limit": {"2"}, "cursor": {second.NextCursor}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"bravo"}, wordsOf(third))
+		assert.False(t, third.HasMore)
+	})
+
+	t.Run("Sort By Word Descending", func(t *testing.T) {
+		code, first := getPage(t, url.Values{"sort": {"word"}, "order": {"desc"}, "limit": {"3"}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"echo", "delta", "charlie"}, wordsOf(first))
+
+		code, second := getPage(t, url.Values{"sort": {"word"}, "order": {"desc"}, "limit": {"3"}, "cursor": {first.NextCursor}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"bravo", "alpha"}, wordsOf(second))
+	})
+
+	t.Run("Sort By Learning Status", func(t *testing.T) {
+		code, first := getPage(t, url.Values{"sort": {"is_learned"}, "order": {"desc"}, "limit": {"1"}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"charlie"}, wordsOf(first))
+
+		code, rest := getPage(t, url.Values{"sort": {"is_learned"}, "order": {"desc"}, "cursor": {first.NextCursor}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"delta", "bravo", "echo", "alpha"}, wordsOf(rest))
+	})
+
+	t.Run("Filter Not Learned", func(t *testing.T) {
+		code, page := getPage(t, url.Values{"is_learned": {"false"}, "limit": {"10"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"alpha", "echo", "bravo"}, wordsOf(page))
+	})
+
+	t.Run("Filter By Exercise", func(t *testing.T) {
+		code, page := getPage(t, url.Values{"cards": {"true"}, "is_learned": {"false"}, "limit": {"10"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"alpha"}, wordsOf(page))
+	})
+
+	t.Run("Filter Created After", func(t *testing.T) {
+		code, page := getPage(t, url.Values{"created_after": {start.Add(2 * time.Hour).Format(time.RFC3339)}, "limit": {"10"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"charlie", "bravo"}, wordsOf(page))
+	})
+
+	t.Run("Invalid Parameters", func(t *testing.T) {
+		malformed := []url.Values{
+			{"limit": {"abc"}},
+			{"is_learned": {"maybe"}},
+			{"created_after": {"yesterday"}},
+		}
+
+		for _, query := range malformed {
+			code, _ := getPage(t, query)
+			assert.Equal(t, http.StatusBadRequest, code, query.Encode())
+		}
+
+		invalid := []url.Values{
+			{"sort": {"definition"}},
+			{"order": {"sideways"}},
+			{"limit": {"1000"}},
+			{"cursor": {"not-a-cursor"}},
+		}
+
+		for _, query := range invalid {
+			code, _ := getPage(t, query)
+			assert.Equal(t, http.StatusUnprocessableEntity, code, query.Encode())
+		}
+	})
+
+	t.Run("Cursor From Another Sorting", func(t *testing.T) {
+		_, first := getPage(t, url.Values{"limit": {"2"}})
+
+		code, _ := getPage(t, url.Values{"sort": {"word"}, "cursor": {first.NextCursor}})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+}
@@ -0,0 +1,172 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestReviewWord(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	dueAt := time.Now().Add(-time.Hour)
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{
+				UserId:     1,
+				Word:       "hello",
+				Definition: "greeting",
+				IsLearned:  false,
+				Cards:      true,
+			},
+			{
+				UserId:       2,
+				Word:         "world",
+				Definition:   "planet earth",
+				IsLearned:    true,
+				EaseFactor:   2.5,
+				IntervalDays: 1,
+				Repetitions:  1,
+				DueAt:        dueAt,
+			},
+			{
+				UserId:          1,
+				Word:            "test2",
+				Definition:      "test def",
+				Cards:           true,
+				WordTranslation: true,
+				Constructor:     true,
+				WordAudio:       true,
+				IsLearned:       true,
+				EaseFactor:      2.5,
+				IntervalDays:    1,
+				Repetitions:     1,
+				DueAt:           dueAt,
+			},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	vocabController := controller.NewVocabController(vocabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.POST("/:wordId/review", vocabController.ReviewWord)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/vocab/3/review", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Missing Quality", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/3/review", map[string]interface{}{})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Quality Out Of Range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/3/review", map[string]interface{}{"quality": 6})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("Word In Training Stage", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/1/review", map[string]interface{}{"quality": 5})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Attempt to Review Other User's Word", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/2/review", map[string]interface{}{"quality": 5})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Successful Review", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/3/review", map[string]interface{}{"quality": 4})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response resp.VocabResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, response.IntervalDays)
+		assert.Equal(t, 2, response.Repetitions)
+
+		var reviewedWord wordsDomain.Word
+		err = env.DB.DB.First(&reviewedWord, 3).Error
+		assert.NoError(t, err)
+		assert.Equal(t, 6, reviewedWord.IntervalDays)
+		assert.Equal(t, 2, reviewedWord.Repetitions)
+		assert.WithinDuration(t, time.Now().Add(6*24*time.Hour), reviewedWord.DueAt, time.Minute)
+	})
+
+	t.Run("Failed Review", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := createJSONRequest(t, "POST", "/api/v1/vocab/3/review", map[string]interface{}{"quality": 0})
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var reviewedWord wordsDomain.Word
+		err := env.DB.DB.First(&reviewedWord, 3).Error
+		assert.NoError(t, err)
+		assert.Equal(t, 1, reviewedWord.IntervalDays)
+		assert.Equal(t, 0, reviewedWord.Repetitions)
+		assert.True(t, reviewedWord.IsLearned)
+	})
+}
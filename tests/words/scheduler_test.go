@@ -0,0 +1,93 @@
+package words
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	wordsDomain "mono_pardo/internal/domain/words"
+)
+
+func TestScheduler(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("First Review After Trainings", func(t *testing.T) {
+		word := wordsDomain.Word{}
+		word.ScheduleFirstReview(now)
+
+		assert.Equal(t, wordsDomain.DefaultEaseFactor, word.EaseFactor)
+		assert.Equal(t, 1, word.Repetitions)
+		assert.Equal(t, 1, word.IntervalDays)
+		assert.Equal(t, now.Add(day), word.DueAt)
+	})
+
+	t.Run("Successful Reviews Grow Interval", func(t *testing.T) {
+		word := wordsDomain.Word{EaseFactor: wordsDomain.DefaultEaseFactor}
+
+		assert.NoError(t, word.Review(4, now))
+		assert.Equal(t, 1, word.IntervalDays)
+		assert.Equal(t, 1, word.Repetitions)
+
+		assert.NoError(t, word.Review(4, now))
+		assert.Equal(t, 6, word.IntervalDays)
+		assert.Equal(t, 2, word.Repetitions)
+
+		assert.NoError(t, word.Review(4, now))
+		assert.Equal(t, 15, word.IntervalDays)
+		assert.Equal(t, 3, word.Repetitions)
+		assert.Equal(t, now.Add(15*day), word.DueAt)
+
+		// quality 4 keeps ease factor the same
+		assert.InDelta(t, wordsDomain.DefaultEaseFactor, word.EaseFactor, 0.0001)
+	})
+
+	t.Run("Perfect Review Increases Ease Factor", func(t *testing.T) {
+		word := wordsDomain.Word{EaseFactor: wordsDomain.DefaultEaseFactor}
+
+		assert.NoError(t, word.Review(5, now))
+		assert.InDelta(t, 2.6, word.EaseFactor, 0.0001)
+	})
+
+	t.Run("Lapse Resets Repetitions", func(t *testing.T) {
+		word := wordsDomain.Word{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}
+
+		assert.NoError(t, word.Review(1, now))
+		assert.Equal(t, 0, word.Repetitions)
+		assert.Equal(t, 1, word.IntervalDays)
+		assert.Equal(t, now.Add(day), word.DueAt)
+
+		// failed answer makes the word harder: 2.5 + (0.1 - 4*(0.08+4*0.02))
+		assert.InDelta(t, 1.96, word.EaseFactor, 0.0001)
+	})
+
+	t.Run("Ease Factor Lower Bound", func(t *testing.T) {
+		word := wordsDomain.Word{EaseFactor: wordsDomain.MinEaseFactor}
+
+		assert.NoError(t, word.Review(0, now))
+		assert.Equal(t, wordsDomain.MinEaseFactor, word.EaseFactor)
+	})
+
+	t.Run("Invalid Quality", func(t *testing.T) {
+		word := wordsDomain.Word{EaseFactor: wordsDomain.DefaultEaseFactor}
+
+		assert.Error(t, word.Review(-1, now))
+		assert.Error(t, word.Review(6, now))
+		assert.Equal(t, 0, word.Repetitions)
+	})
+
+	t.Run("Schedule Imported Word", func(t *testing.T) {
+		word := wordsDomain.Word{}
+
+		word.ScheduleImported(30, now)
+		assert.True(t, word.IsLearned)
+		assert.True(t, word.Cards && word.WordTranslation && word.Constructor && word.WordAudio)
+		assert.Equal(t, 30, word.IntervalDays)
+		assert.Equal(t, now.Add(30*day), word.DueAt)
+
+		// next successful review grows the imported interval
+		assert.NoError(t, word.Review(4, now))
+		assert.Equal(t, 75, word.IntervalDays)
+	})
+}
@@ -0,0 +1,139 @@
+package words
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+
+	"mono_pardo/internal/api/controller"
+	"mono_pardo/internal/api/middleware"
+	wordsDomain "mono_pardo/internal/domain/words"
+	wordsInfra "mono_pardo/internal/infrastructure/words"
+	resp "mono_pardo/pkg/data/response"
+	"mono_pardo/tests"
+)
+
+func TestSearchWords(t *testing.T) {
+	env, _ := tests.NewTestEnv(t)
+	defer env.Cleanup(t)
+
+	env.RunMigrations(t)
+
+	mockAuthService := &tests.MockAuthService{}
+	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
+	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
+
+	fixture := &tests.WordFixture{
+		Words: []wordsDomain.Word{
+			{UserId: 1, Word: "apple", Definition: "a round fruit"},
+			{UserId: 1, Word: "application", Definition: "a formal request"},
+			{UserId: 1, Word: "pineapple", Definition: "tropical plant"},
+			{UserId: 1, Word: "banana", Definition: "a long yellow fruit"},
+			{UserId: 1, Word: "necessary", Definition: "needed to be done"},
+			{UserId: 1, Word: "100%_sure", Definition: "completely certain"},
+			{UserId: 2, Word: "apples", Definition: "other user's fruit"},
+		},
+	}
+	cleanup := env.WithFixture(t, fixture)
+	defer cleanup()
+
+	wordRepository := wordsInfra.NewPostgresRepositoryImpl(env.DB.DB)
+	validate := validator.New()
+	vocabService := wordsDomain.NewServiceImpl(validate, wordRepository)
+	vocabController := controller.NewVocabController(vocabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(mockAuthService)
+
+	router := env.Router
+	vocabGroup := router.Group("/api/v1/vocab")
+	vocabGroup.Use(authMiddleware.Handle())
+	vocabGroup.GET("/search", vocabController.SearchWords)
+
+	search := func(t *testing.T, query url.Values) (int, []string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/search?"+query.Encode(), nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+
+		router.ServeHTTP(w, req)
+
+		words := []string{}
+		if w.Code == http.StatusOK {
+			var response []resp.VocabResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for _, word := range response {
+				words = append(words, word.Word)
+			}
+		}
+		return w.Code, words
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/vocab/search?q=apple", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Empty Query", func(t *testing.T) {
+		code, _ := search(t, url.Values{"q": {"  "}})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("Exact Match Goes First", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"apple"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"apple", "pineapple"}, words)
+	})
+
+	t.Run("Prefix Match", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"ap"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{"apple", "application"}, words)
+	})
+
+	t.Run("Typo Tolerance", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"neccesary"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"necessary"}, words)
+	})
+
+	t.Run("Match Definition", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"fruit"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{"apple", "banana"}, words)
+	})
+
+	t.Run("Wildcards Are Escaped", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"%"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, words)
+
+		code, words = search(t, url.Values{"q": {"100%"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"100%_sure"}, words)
+	})
+
+	t.Run("Limit Results", func(t *testing.T) {
+		code, words := search(t, url.Values{"q": {"ap"}, "limit": {"1"}})
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, words, 1)
+	})
+}
@@ -25,7 +25,7 @@ func TestUpdateWord(t *testing.T) {
 
 	env.RunMigrations(t)
 
-	mockAuthService := &MockAuthService{}
+	mockAuthService := &tests.MockAuthService{}
 	mockAuthService.On("GetUserId", "test-token").Return(1, nil)
 	mockAuthService.On("GetUserId", "test-token-user2").Return(2, nil)
 	mockAuthService.On("GetUserId", "").Return(0, fmt.Errorf("empty token"))
@@ -120,7 +120,7 @@ func TestUpdateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("Unauthorized Access", func(t *testing.T) {
@@ -141,7 +141,7 @@ func TestUpdateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("Invalid Field Name", func(t *testing.T) {
@@ -162,7 +162,7 @@ func TestUpdateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("Invalid Value Type", func(t *testing.T) {
@@ -183,7 +183,7 @@ func TestUpdateWord(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("Training Failure", func(t *testing.T) {
@@ -238,6 +238,11 @@ func TestUpdateWord(t *testing.T) {
 		err := env.DB.DB.First(&updatedWord, 3).Error
 		assert.NoError(t, err)
 		assert.True(t, updatedWord.IsLearned)
+
+		// Verify word was put to the review queue
+		assert.Equal(t, 1, updatedWord.Repetitions)
+		assert.Equal(t, 1, updatedWord.IntervalDays)
+		assert.True(t, updatedWord.DueAt.After(time.Now()))
 	})
 
 	t.Run("Successful Update", func(t *testing.T) {